@@ -0,0 +1,168 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+)
+
+// +kubebuilder:validation:Enum=spa;mobile;server-web;machine
+// ClientArchetype represents a preset of client settings encoding the
+// security best practices of a common kind of client
+type ClientArchetype string
+
+const (
+	// ClientArchetypeSPA is the archetype of single-page applications, which
+	// are public clients using the authorization code grant with PKCE
+	ClientArchetypeSPA ClientArchetype = "spa"
+	// ClientArchetypeMobile is the archetype of native mobile applications,
+	// which are public clients using the authorization code grant with PKCE
+	ClientArchetypeMobile ClientArchetype = "mobile"
+	// ClientArchetypeServerWeb is the archetype of server-side web
+	// applications, which are confidential clients using the authorization
+	// code grant
+	ClientArchetypeServerWeb ClientArchetype = "server-web"
+	// ClientArchetypeMachine is the archetype of machine-to-machine clients
+	ClientArchetypeMachine ClientArchetype = "machine"
+)
+
+// archetype holds the defaults of the clients of an archetype and the
+// constraints they must satisfy
+type archetype struct {
+	grantTypes              []GrantType
+	responseTypes           []ResponseType
+	tokenEndpointAuthMethod TokenEndpointAuthMethod
+	// public is whether clients of the archetype can't keep a secret, and
+	// must thus use PKCE instead of authenticating at the token endpoint
+	public bool
+	// redirectURIs is whether clients of the archetype use redirect-based
+	// flows
+	redirectURIs bool
+	// corsOrigins is whether clients of the archetype run in browsers
+	corsOrigins bool
+}
+
+var archetypes = map[ClientArchetype]archetype{
+	ClientArchetypeSPA: {
+		grantTypes:              []GrantType{"authorization_code", "refresh_token"},
+		responseTypes:           []ResponseType{"code"},
+		tokenEndpointAuthMethod: "none",
+		public:                  true,
+		redirectURIs:            true,
+		corsOrigins:             true,
+	},
+	ClientArchetypeMobile: {
+		grantTypes:              []GrantType{"authorization_code", "refresh_token"},
+		responseTypes:           []ResponseType{"code"},
+		tokenEndpointAuthMethod: "none",
+		public:                  true,
+		redirectURIs:            true,
+	},
+	ClientArchetypeServerWeb: {
+		grantTypes:              []GrantType{"authorization_code", "refresh_token"},
+		responseTypes:           []ResponseType{"code"},
+		tokenEndpointAuthMethod: "client_secret_basic",
+		redirectURIs:            true,
+	},
+	ClientArchetypeMachine: {
+		grantTypes:              []GrantType{"client_credentials"},
+		tokenEndpointAuthMethod: "client_secret_basic",
+	},
+}
+
+// GetArchetype returns the archetype of the client, which is given by the
+// deprecated profile if no archetype is set
+func (c *OAuth2Client) GetArchetype() ClientArchetype {
+	if c.Spec.Archetype != "" {
+		return c.Spec.Archetype
+	}
+	return ClientArchetype(c.Spec.Profile)
+}
+
+// validateArchetype checks the settings of the client against the
+// constraints of its archetype
+func (c *OAuth2Client) validateArchetype() error {
+	if c.Spec.Archetype != "" && c.Spec.Profile != "" && ClientArchetype(c.Spec.Profile) != c.Spec.Archetype {
+		return fmt.Errorf("profile %s contradicts archetype %s", c.Spec.Profile, c.Spec.Archetype)
+	}
+
+	name := c.GetArchetype()
+	a, ok := archetypes[name]
+	if !ok {
+		return nil
+	}
+
+	if !a.redirectURIs && (len(c.Spec.RedirectURIs) > 0 || len(c.Spec.PostLogoutRedirectURIs) > 0) {
+		return fmt.Errorf("clients with the %s archetype can't have redirect URIs", name)
+	}
+	if a.redirectURIs && len(c.Spec.RedirectURIs) == 0 {
+		return fmt.Errorf("clients with the %s archetype must have redirect URIs", name)
+	}
+	if !a.corsOrigins && len(c.Spec.AllowedCorsOrigins) > 0 {
+		return fmt.Errorf("clients with the %s archetype can't have allowed CORS origins", name)
+	}
+	if a.public && c.GetTokenEndpointAuthMethod() != "none" {
+		return fmt.Errorf("clients with the %s archetype are public clients using PKCE and can't authenticate at the token endpoint", name)
+	}
+	if !a.public && c.GetTokenEndpointAuthMethod() == "none" {
+		return fmt.Errorf("clients with the %s archetype must authenticate at the token endpoint", name)
+	}
+	if !a.redirectURIs {
+		return nil
+	}
+	if c.HasGrantType("implicit") {
+		return fmt.Errorf("clients with the %s archetype can't use the implicit grant", name)
+	}
+	for _, rt := range c.GetResponseTypes() {
+		if rt != "code" {
+			return fmt.Errorf("clients with the %s archetype can only use the code response type", name)
+		}
+	}
+	return nil
+}
+
+// GetGrantTypes returns the grant types of the client, which default to
+// those of its archetype
+func (c *OAuth2Client) GetGrantTypes() []GrantType {
+	if a, ok := archetypes[c.GetArchetype()]; ok && len(c.Spec.GrantTypes) == 0 {
+		return a.grantTypes
+	}
+	return c.Spec.GrantTypes
+}
+
+// GetResponseTypes returns the response types of the client, which default
+// to those of its archetype
+func (c *OAuth2Client) GetResponseTypes() []ResponseType {
+	if a, ok := archetypes[c.GetArchetype()]; ok && len(c.Spec.ResponseTypes) == 0 {
+		return a.responseTypes
+	}
+	return c.Spec.ResponseTypes
+}
+
+// GetTokenEndpointAuthMethod returns the token endpoint authentication
+// method of the client, which defaults to that of its archetype
+func (c *OAuth2Client) GetTokenEndpointAuthMethod() TokenEndpointAuthMethod {
+	if a, ok := archetypes[c.GetArchetype()]; ok && c.Spec.TokenEndpointAuthMethod == "" {
+		return a.tokenEndpointAuthMethod
+	}
+	return c.Spec.TokenEndpointAuthMethod
+}
+
+// IsPublic reports whether the client is a public client, which doesn't
+// authenticate at the token endpoint and has no client secret
+func (c *OAuth2Client) IsPublic() bool {
+	return c.GetTokenEndpointAuthMethod() == "none"
+}
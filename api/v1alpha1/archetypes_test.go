@@ -0,0 +1,92 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArchetypes(t *testing.T) {
+
+	client := func(archetype ClientArchetype) *OAuth2Client {
+		c := &OAuth2Client{Spec: OAuth2ClientSpec{
+			Archetype:  archetype,
+			Scope:      "read",
+			SecretName: "foo",
+		}}
+		if archetype != ClientArchetypeMachine {
+			c.Spec.RedirectURIs = []RedirectURI{"https://example.com/callback"}
+		}
+		return c
+	}
+
+	t.Run("case=defaults", func(t *testing.T) {
+		for archetype, expected := range map[ClientArchetype]struct {
+			grantTypes    []string
+			responseTypes []string
+			authMethod    string
+		}{
+			ClientArchetypeSPA:       {[]string{"authorization_code", "refresh_token"}, []string{"code"}, "none"},
+			ClientArchetypeMobile:    {[]string{"authorization_code", "refresh_token"}, []string{"code"}, "none"},
+			ClientArchetypeServerWeb: {[]string{"authorization_code", "refresh_token"}, []string{"code"}, "client_secret_basic"},
+			ClientArchetypeMachine:   {[]string{"client_credentials"}, []string{}, "client_secret_basic"},
+		} {
+			t.Run("archetype="+string(archetype), func(t *testing.T) {
+				c := client(archetype)
+				assert.NoError(t, c.Validate())
+
+				hydraClient := c.ToOAuth2ClientJSON()
+				assert.Equal(t, expected.grantTypes, hydraClient.GrantTypes)
+				assert.Equal(t, expected.responseTypes, hydraClient.ResponseTypes)
+				assert.Equal(t, expected.authMethod, hydraClient.TokenEndpointAuthMethod)
+			})
+		}
+	})
+
+	t.Run("case=the machine profile is the machine archetype", func(t *testing.T) {
+		c := client("")
+		c.Spec.RedirectURIs = nil
+		c.Spec.Profile = ClientProfileMachine
+
+		assert.Equal(t, ClientArchetypeMachine, c.GetArchetype())
+		assert.NoError(t, c.Validate())
+		assert.Equal(t, []GrantType{"client_credentials"}, c.GetGrantTypes())
+	})
+
+	t.Run("case=constraints", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			archetype ClientArchetype
+			modify    func(c *OAuth2Client)
+		}{
+			"spa with a client secret":         {ClientArchetypeSPA, func(c *OAuth2Client) { c.Spec.TokenEndpointAuthMethod = "client_secret_post" }},
+			"spa with the implicit grant":      {ClientArchetypeSPA, func(c *OAuth2Client) { c.Spec.GrantTypes = []GrantType{"implicit"} }},
+			"spa with the token response type": {ClientArchetypeSPA, func(c *OAuth2Client) { c.Spec.ResponseTypes = []ResponseType{"code", "token"} }},
+			"spa without redirect URIs":        {ClientArchetypeSPA, func(c *OAuth2Client) { c.Spec.RedirectURIs = nil }},
+			"mobile with CORS origins":         {ClientArchetypeMobile, func(c *OAuth2Client) { c.Spec.AllowedCorsOrigins = []RedirectURI{"https://example.com"} }},
+			"server-web as a public client":    {ClientArchetypeServerWeb, func(c *OAuth2Client) { c.Spec.TokenEndpointAuthMethod = "none" }},
+			"machine with redirect URIs":       {ClientArchetypeMachine, func(c *OAuth2Client) { c.Spec.RedirectURIs = []RedirectURI{"https://example.com/callback"} }},
+			"contradicting profile":            {ClientArchetypeSPA, func(c *OAuth2Client) { c.Spec.Profile = ClientProfileMachine }},
+		} {
+			t.Run("case="+name, func(t *testing.T) {
+				c := client(tc.archetype)
+				tc.modify(c)
+				assert.Error(t, c.Validate())
+			})
+		}
+	})
+}
@@ -0,0 +1,127 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OAuth2ClientConditionType represents the type of an OAuth2Client condition
+type OAuth2ClientConditionType string
+
+const (
+	// OAuth2ClientConditionReady reports whether the current generation of
+	// the client has been applied to ORY Hydra
+	OAuth2ClientConditionReady OAuth2ClientConditionType = "Ready"
+	// OAuth2ClientConditionSecretExpired is set when the client secret is past its SecretTTL
+	OAuth2ClientConditionSecretExpired OAuth2ClientConditionType = "SecretExpired"
+	// OAuth2ClientConditionRedirectURIsAllowed reports whether all redirect URIs
+	// match the redirect URI allow pattern in effect for the client's namespace
+	OAuth2ClientConditionRedirectURIsAllowed OAuth2ClientConditionType = "RedirectURIsAllowed"
+	// OAuth2ClientConditionConflictDetected reports whether the client has
+	// been changed in ORY Hydra since the controller last wrote it
+	OAuth2ClientConditionConflictDetected OAuth2ClientConditionType = "ConflictDetected"
+	// OAuth2ClientConditionManualSecret reports whether the client secret has
+	// been set manually by an operator
+	OAuth2ClientConditionManualSecret OAuth2ClientConditionType = "ManualSecret"
+	// OAuth2ClientConditionUnknownFields reports whether the manifest last
+	// applied to the client has spec fields unknown to the controller
+	OAuth2ClientConditionUnknownFields OAuth2ClientConditionType = "UnknownFields"
+	// OAuth2ClientConditionCredentialsVerified reports whether a token could
+	// be obtained with the credentials of the client's Secret
+	OAuth2ClientConditionCredentialsVerified OAuth2ClientConditionType = "CredentialsVerified"
+	// OAuth2ClientConditionPolicyCompliant reports whether the client complies
+	// with the OAuth2ClientPolicies applying to its namespace
+	OAuth2ClientConditionPolicyCompliant OAuth2ClientConditionType = "PolicyCompliant"
+	// OAuth2ClientConditionExpired is set when the client is past its
+	// ExpiresAfter or NotAfter
+	OAuth2ClientConditionExpired OAuth2ClientConditionType = "Expired"
+	// OAuth2ClientConditionInSync reports whether the client registered in
+	// ORY Hydra matches the spec, only set in audit mode
+	OAuth2ClientConditionInSync OAuth2ClientConditionType = "InSync"
+	// OAuth2ClientConditionSuspended reports whether the reconciliation of the
+	// client is suspended by its spec
+	OAuth2ClientConditionSuspended OAuth2ClientConditionType = "Suspended"
+	// OAuth2ClientConditionPublicClient is set when the client is a public
+	// client, which has no client secret and gets no Secret
+	OAuth2ClientConditionPublicClient OAuth2ClientConditionType = "PublicClient"
+	// OAuth2ClientConditionSecretPushed reports whether the Secret of the
+	// client is pushed to an external secret store by a PushSecret
+	OAuth2ClientConditionSecretPushed OAuth2ClientConditionType = "SecretPushed"
+	// OAuth2ClientConditionSecretTargetsSynced reports whether copies of the
+	// Secret of the client are kept in all its SecretTargets
+	OAuth2ClientConditionSecretTargetsSynced OAuth2ClientConditionType = "SecretTargetsSynced"
+	// OAuth2ClientConditionHydraAvailable reports whether ORY Hydra was
+	// available on the last reconciliation of the client, only set once it
+	// wasn't
+	OAuth2ClientConditionHydraAvailable OAuth2ClientConditionType = "HydraAvailable"
+)
+
+// OAuth2ClientCondition contains details about the state of an OAuth2Client
+type OAuth2ClientCondition struct {
+	// Type is the type of the condition
+	Type OAuth2ClientConditionType `json:"type"`
+	// Status is the status of the condition, one of True, False or Unknown
+	Status apiv1.ConditionStatus `json:"status"`
+	// LastTransitionTime is the last time the condition changed its status
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// Reason is a machine-readable explanation of the condition's last transition
+	Reason string `json:"reason,omitempty"`
+	// Message is a human-readable explanation of the condition's last transition
+	Message string `json:"message,omitempty"`
+}
+
+// GetCondition returns the condition of the given type, or nil if it is not set
+func (s *OAuth2ClientStatus) GetCondition(t OAuth2ClientConditionType) *OAuth2ClientCondition {
+	for i := range s.Conditions {
+		if s.Conditions[i].Type == t {
+			return &s.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// SetCondition adds or updates the condition of the given type. The
+// transition time is only updated if the status changes. It reports whether
+// the condition has changed.
+func (s *OAuth2ClientStatus) SetCondition(t OAuth2ClientConditionType, status apiv1.ConditionStatus, reason, message string) bool {
+	if c := s.GetCondition(t); c != nil {
+		if c.Status == status && c.Reason == reason && c.Message == message {
+			return false
+		}
+		if c.Status != status {
+			c.LastTransitionTime = metav1.Now()
+		}
+		c.Status, c.Reason, c.Message = status, reason, message
+		return true
+	}
+
+	s.Conditions = append(s.Conditions, OAuth2ClientCondition{
+		Type:               t,
+		Status:             status,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+		Message:            message,
+	})
+	return true
+}
+
+// IsConditionTrue reports whether the condition of the given type has status True
+func (s *OAuth2ClientStatus) IsConditionTrue(t OAuth2ClientConditionType) bool {
+	c := s.GetCondition(t)
+	return c != nil && c.Status == apiv1.ConditionTrue
+}
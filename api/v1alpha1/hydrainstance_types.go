@@ -0,0 +1,118 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HydraInstanceSpec describes the admin endpoint of an ORY Hydra installation
+type HydraInstanceSpec struct {
+	// +kubebuilder:validation:MaxLength=64
+	// +kubebuilder:validation:Pattern=^https?://.*
+	//
+	// URL is the URL of the ORY Hydra admin API
+	URL string `json:"url"`
+
+	// +kubebuilder:validation:Maximum=65535
+	//
+	// Port is the port of the ORY Hydra admin API, 4445 by default
+	Port int `json:"port,omitempty"`
+
+	// +kubebuilder:validation:Pattern=(^$|^/.*)
+	//
+	// Endpoint is the path of the client endpoint. If not set, it is
+	// detected from the version ORY Hydra reports, `/clients` up to 1.x
+	// and `/admin/clients` from 2.0
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// +kubebuilder:validation:Pattern=(^$|https?)
+	//
+	// ForwardedProto, if set, is sent as the X-Forwarded-Proto header
+	ForwardedProto string `json:"forwardedProto,omitempty"`
+
+	// TLS configures the TLS connection to the ORY Hydra admin API
+	TLS *HydraInstanceTLS `json:"tls,omitempty"`
+
+	// Auth configures how the controller authenticates to the ORY Hydra
+	// admin API, e.g. when it is behind an authenticating proxy
+	Auth *HydraInstanceAuth `json:"auth,omitempty"`
+
+	// Headers are sent with every request to the ORY Hydra admin API, e.g.
+	// the routing headers of an API gateway
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// HydraInstanceTLS configures the TLS connection to an ORY Hydra instance
+type HydraInstanceTLS struct {
+	// SecretRef references a Secret holding the CA certificates trusted for
+	// ORY Hydra's certificate under `ca.crt` and, for mutual TLS, the client
+	// certificate and key under `tls.crt` and `tls.key`
+	SecretRef apiv1.SecretReference `json:"secretRef"`
+
+	// ServerName, if set, is verified instead of the host name of the URL
+	ServerName string `json:"serverName,omitempty"`
+}
+
+// HydraInstanceAuth configures the authentication to an ORY Hydra instance
+type HydraInstanceAuth struct {
+	// SecretRef references a Secret holding either a bearer token under
+	// `token`, a user name and password for basic authentication under
+	// `username` and `password`, or with TokenURL, the credentials of an
+	// OAuth2 client under `client_id` and `client_secret`
+	SecretRef apiv1.SecretReference `json:"secretRef"`
+
+	// Header, if set, is the header the token is sent in as it is, e.g.
+	// X-API-Key, instead of as a bearer token in the Authorization header
+	Header string `json:"header,omitempty"`
+
+	// +kubebuilder:validation:Pattern=(^$|^https?://.*)
+	//
+	// TokenURL, if set, is the token endpoint the controller obtains access
+	// tokens from with the client credentials grant, sent as bearer tokens
+	TokenURL string `json:"tokenURL,omitempty"`
+
+	// Scopes are the scopes requested with the client credentials grant
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,categories=ory
+// +kubebuilder:printcolumn:name="URL",type="string",JSONPath=".spec.url"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// HydraInstance is the Schema for the hydrainstances API. It describes an
+// ORY Hydra installation OAuth2Clients can be registered in.
+type HydraInstance struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec HydraInstanceSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// HydraInstanceList contains a list of HydraInstance
+type HydraInstanceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HydraInstance `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&HydraInstance{}, &HydraInstanceList{})
+}
@@ -0,0 +1,141 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"regexp"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HydraMaesterConfigurationName is the name of the only HydraMaesterConfiguration
+// read by the controller
+const HydraMaesterConfigurationName = "default"
+
+// HydraMaesterConfigurationSpec holds settings of the controller which
+// override its flags. Settings which are not set fall back to the flags.
+type HydraMaesterConfigurationSpec struct {
+	// Defaults holds the defaults which namespaces can override
+	Defaults *ConfigurationDefaults `json:"defaults,omitempty"`
+
+	// Policies holds the policies OAuth2Clients are reconciled with
+	Policies *ConfigurationPolicies `json:"policies,omitempty"`
+
+	// Rotation configures the rotation of client secrets
+	Rotation *ConfigurationRotation `json:"rotation,omitempty"`
+
+	// GarbageCollection configures the deletion of clients from ORY Hydra
+	// whose OAuth2Client no longer exists
+	GarbageCollection *ConfigurationGarbageCollection `json:"garbageCollection,omitempty"`
+}
+
+// ConfigurationDefaults holds the defaults of the controller which
+// namespaces can override with annotations
+type ConfigurationDefaults struct {
+	// RedirectURIAllowPattern is the regular expression all redirect URIs
+	// must match in namespaces which don't override it. An empty pattern
+	// allows all redirect URIs.
+	RedirectURIAllowPattern *string `json:"redirectURIAllowPattern,omitempty"`
+
+	// JWTAudiences maps namespaces to the audience of the OAuth2Clients in
+	// them which use the `jwt` access token strategy and don't set an audience
+	JWTAudiences map[string][]string `json:"jwtAudiences,omitempty"`
+}
+
+// ConfigurationPolicies holds the policies OAuth2Clients are reconciled with
+type ConfigurationPolicies struct {
+	// StrictFields fails the reconciliation of clients whose last applied
+	// manifest has spec fields unknown to the controller
+	StrictFields *bool `json:"strictFields,omitempty"`
+
+	// DriftDetectionInterval is the interval at which registered clients
+	// are compared with ORY Hydra, 0 to disable drift detection
+	DriftDetectionInterval *metav1.Duration `json:"driftDetectionInterval,omitempty"`
+}
+
+// ConfigurationRotation configures the rotation of client secrets
+type ConfigurationRotation struct {
+	// SecretTTL is the lifetime of the client secret of clients which,
+	// like their template, don't set one
+	SecretTTL *metav1.Duration `json:"secretTTL,omitempty"`
+}
+
+// ConfigurationGarbageCollection configures the garbage collection of
+// clients in ORY Hydra
+type ConfigurationGarbageCollection struct {
+	// Enabled turns the garbage collection on or off
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Interval is the interval of the garbage collection
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+// HydraMaesterConfigurationStatus defines the observed state of HydraMaesterConfiguration
+type HydraMaesterConfigurationStatus struct {
+	// ObservedGeneration is the last generation of the configuration which
+	// has been checked
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// ReconciliationError is set if the configuration is invalid or not read
+	ReconciliationError ReconciliationError `json:"reconciliationError,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories=ory
+
+// HydraMaesterConfiguration is the Schema for the hydramaesterconfigurations
+// API. The controller reads the one named default, which configures it
+// without redeploying it.
+type HydraMaesterConfiguration struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   HydraMaesterConfigurationSpec   `json:"spec,omitempty"`
+	Status HydraMaesterConfigurationStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// HydraMaesterConfigurationList contains a list of HydraMaesterConfiguration
+type HydraMaesterConfigurationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HydraMaesterConfiguration `json:"items"`
+}
+
+// Validate checks the settings of the configuration
+func (c *HydraMaesterConfiguration) Validate() error {
+	if d := c.Spec.Defaults; d != nil && d.RedirectURIAllowPattern != nil {
+		if _, err := regexp.Compile(*d.RedirectURIAllowPattern); err != nil {
+			return fmt.Errorf("invalid redirect URI allow pattern: %w", err)
+		}
+	}
+	if p := c.Spec.Policies; p != nil && p.DriftDetectionInterval != nil && p.DriftDetectionInterval.Duration < 0 {
+		return fmt.Errorf("the drift detection interval can't be negative")
+	}
+	if r := c.Spec.Rotation; r != nil && r.SecretTTL != nil && r.SecretTTL.Duration <= 0 {
+		return fmt.Errorf("the secret TTL must be positive")
+	}
+	if gc := c.Spec.GarbageCollection; gc != nil && gc.Interval != nil && gc.Interval.Duration <= 0 {
+		return fmt.Errorf("the garbage collection interval must be positive")
+	}
+	return nil
+}
+
+func init() {
+	SchemeBuilder.Register(&HydraMaesterConfiguration{}, &HydraMaesterConfigurationList{})
+}
@@ -0,0 +1,56 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateConfiguration(t *testing.T) {
+
+	pattern := func(p string) *string { return &p }
+	duration := func(d time.Duration) *metav1.Duration { return &metav1.Duration{Duration: d} }
+
+	for name, tc := range map[string]struct {
+		spec  HydraMaesterConfigurationSpec
+		valid bool
+	}{
+		"empty": {HydraMaesterConfigurationSpec{}, true},
+		"complete": {HydraMaesterConfigurationSpec{
+			Defaults:          &ConfigurationDefaults{RedirectURIAllowPattern: pattern(`https://[^/]+\.example\.com(/.*)?`)},
+			Policies:          &ConfigurationPolicies{DriftDetectionInterval: duration(0)},
+			Rotation:          &ConfigurationRotation{SecretTTL: duration(time.Hour)},
+			GarbageCollection: &ConfigurationGarbageCollection{Interval: duration(time.Hour)},
+		}, true},
+		"invalid redirect URI allow pattern": {HydraMaesterConfigurationSpec{Defaults: &ConfigurationDefaults{RedirectURIAllowPattern: pattern("https://(")}}, false},
+		"negative drift detection interval":  {HydraMaesterConfigurationSpec{Policies: &ConfigurationPolicies{DriftDetectionInterval: duration(-time.Minute)}}, false},
+		"zero secret TTL":                    {HydraMaesterConfigurationSpec{Rotation: &ConfigurationRotation{SecretTTL: duration(0)}}, false},
+		"zero garbage collection interval":   {HydraMaesterConfigurationSpec{GarbageCollection: &ConfigurationGarbageCollection{Interval: duration(0)}}, false},
+	} {
+		t.Run("case="+name, func(t *testing.T) {
+			c := &HydraMaesterConfiguration{Spec: tc.spec}
+			if tc.valid {
+				assert.NoError(t, c.Validate())
+			} else {
+				assert.Error(t, c.Validate())
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:validation:Enum=RS256;ES256;ES512;HS256;HS512
+// JSONWebKeyAlgorithm represents the algorithm of a JSON web key
+type JSONWebKeyAlgorithm string
+
+// +kubebuilder:validation:Enum=sig;enc
+// JSONWebKeyUse represents the intended use of a JSON web key
+type JSONWebKeyUse string
+
+const (
+	// JSONWebKeyUseSignature is the use of signing keys
+	JSONWebKeyUseSignature JSONWebKeyUse = "sig"
+	// JSONWebKeyUseEncryption is the use of encryption keys
+	JSONWebKeyUseEncryption JSONWebKeyUse = "enc"
+)
+
+// JSONWebKeySetSecretKey is the key of the Secret of a JsonWebKeySet holding
+// the key set, including its private keys
+const JSONWebKeySetSecretKey = "jwks.json"
+
+// JsonWebKeySetSpec defines the desired state of JsonWebKeySet
+type JsonWebKeySetSpec struct {
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=(^$|^[a-zA-Z0-9._-]+$)
+	//
+	// SetName is the name of the key set in ORY Hydra, `<namespace>.<name>`
+	// by default
+	SetName string `json:"setName,omitempty"`
+
+	// Algorithm is the algorithm of the generated keys
+	Algorithm JSONWebKeyAlgorithm `json:"algorithm"`
+
+	// Use is the intended use of the generated keys, `sig` by default
+	Use JSONWebKeyUse `json:"use,omitempty"`
+
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*
+	//
+	// SecretName is the name of the Secret the key set, including its
+	// private keys, is written to
+	SecretName string `json:"secretName"`
+
+	// RotationInterval, if set, is the interval at which a new key is
+	// generated. The previous key is kept in the set, so that signatures
+	// made with it can still be verified, until the next rotation.
+	RotationInterval *metav1.Duration `json:"rotationInterval,omitempty"`
+}
+
+// JsonWebKeySetStatus defines the observed state of JsonWebKeySet
+type JsonWebKeySetStatus struct {
+	// ObservedGeneration is the last generation which has been applied to
+	// ORY Hydra
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// KeyIDs are the IDs of the keys generated by the controller which are
+	// still in the set, the current key first
+	KeyIDs []string `json:"keyIDs,omitempty"`
+	// RotatedAt is the time the current key was generated at
+	RotatedAt *metav1.Time `json:"rotatedAt,omitempty"`
+	// ReconciliationError is the error the last reconciliation failed with
+	ReconciliationError ReconciliationError `json:"reconciliationError,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories=ory
+// +kubebuilder:printcolumn:name="Algorithm",type="string",JSONPath=".spec.algorithm"
+// +kubebuilder:printcolumn:name="Rotated",type="date",JSONPath=".status.rotatedAt"
+
+// JsonWebKeySet is the Schema for the jsonwebkeysets API. It describes a JSON
+// web key set managed in ORY Hydra.
+type JsonWebKeySet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   JsonWebKeySetSpec   `json:"spec,omitempty"`
+	Status JsonWebKeySetStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// JsonWebKeySetList contains a list of JsonWebKeySet
+type JsonWebKeySetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []JsonWebKeySet `json:"items"`
+}
+
+// GetSetName returns the name of the key set in ORY Hydra
+func (k *JsonWebKeySet) GetSetName() string {
+	if k.Spec.SetName != "" {
+		return k.Spec.SetName
+	}
+	return fmt.Sprintf("%s.%s", k.Namespace, k.Name)
+}
+
+// GetUse returns the intended use of the keys of the set
+func (k *JsonWebKeySet) GetUse() JSONWebKeyUse {
+	if k.Spec.Use != "" {
+		return k.Spec.Use
+	}
+	return JSONWebKeyUseSignature
+}
+
+func init() {
+	SchemeBuilder.Register(&JsonWebKeySet{}, &JsonWebKeySetList{})
+}
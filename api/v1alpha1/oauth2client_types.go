@@ -17,20 +17,36 @@ package v1alpha1
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+	"text/template"
+	"time"
 
-	"github.com/ory/hydra-maester/hydra"
+	"github.com/ory/hydra-maester/pkg/hydraclient"
+	apiv1 "k8s.io/api/core/v1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type StatusCode string
 
 const (
-	StatusRegistrationFailed  StatusCode = "CLIENT_REGISTRATION_FAILED"
-	StatusCreateSecretFailed  StatusCode = "SECRET_CREATION_FAILED"
-	StatusUpdateFailed        StatusCode = "CLIENT_UPDATE_FAILED"
-	StatusInvalidSecret       StatusCode = "INVALID_SECRET"
-	StatusInvalidHydraAddress StatusCode = "INVALID_HYDRA_ADDRESS"
+	StatusRegistrationFailed     StatusCode = "CLIENT_REGISTRATION_FAILED"
+	StatusCreateSecretFailed     StatusCode = "SECRET_CREATION_FAILED"
+	StatusUpdateFailed           StatusCode = "CLIENT_UPDATE_FAILED"
+	StatusInvalidSecret          StatusCode = "INVALID_SECRET"
+	StatusInvalidHydraAddress    StatusCode = "INVALID_HYDRA_ADDRESS"
+	StatusInvalidSpec            StatusCode = "INVALID_SPEC"
+	StatusRedirectURINotAllowed  StatusCode = "REDIRECT_URI_NOT_ALLOWED"
+	StatusConflictDetected       StatusCode = "CONFLICT_DETECTED"
+	StatusTemplateNotFound       StatusCode = "TEMPLATE_NOT_FOUND"
+	StatusUnknownFields          StatusCode = "UNKNOWN_FIELDS"
+	StatusKeyGenerationFailed    StatusCode = "KEY_GENERATION_FAILED"
+	StatusTrustFailed            StatusCode = "TRUST_FAILED"
+	StatusPolicyViolation        StatusCode = "POLICY_VIOLATION"
+	StatusStoreCredentialsFailed StatusCode = "CREDENTIALS_STORE_FAILED"
+	StatusPushSecretFailed       StatusCode = "SECRET_PUSH_FAILED"
 )
 
 // HydraAdmin defines the desired hydra admin instance to use for OAuth2Client
@@ -69,14 +85,36 @@ type HydraAdmin struct {
 // OAuth2ClientSpec defines the desired state of OAuth2Client
 type OAuth2ClientSpec struct {
 
+	// ClientID is the ID of the client in ORY Hydra. If not set, ORY Hydra
+	// generates one when the client is registered.
+	ClientID string `json:"clientID,omitempty"`
+
 	// ClientName is the human-readable string name of the client to be presented to the end-user during authorization.
 	ClientName string `json:"clientName,omitempty"`
 
-	// +kubebuilder:validation:MaxItems=4
+	// +kubebuilder:validation:Enum=spa;mobile;server-web;machine
+	//
+	// Archetype expands into the settings of a common kind of client and
+	// enforces the security best practices for it: `spa` and `mobile` are
+	// public clients using the authorization code grant with PKCE,
+	// `server-web` is a confidential client using the authorization code
+	// grant and `machine` a client using the `client_credentials` grant.
+	// Settings given explicitly take precedence over the defaults of the
+	// archetype, but must satisfy its constraints.
+	Archetype ClientArchetype `json:"archetype,omitempty"`
+
+	// +kubebuilder:validation:Enum=machine
+	//
+	// Profile is the deprecated predecessor of Archetype. `machine` is the
+	// same as the `machine` archetype.
+	Profile ClientProfile `json:"profile,omitempty"`
+
+	// +kubebuilder:validation:MaxItems=6
 	// +kubebuilder:validation:MinItems=1
 	//
-	// GrantTypes is an array of grant types the client is allowed to use.
-	GrantTypes []GrantType `json:"grantTypes"`
+	// GrantTypes is an array of grant types the client is allowed to use. It
+	// is required unless an archetype is set.
+	GrantTypes []GrantType `json:"grantTypes,omitempty"`
 
 	// +kubebuilder:validation:MaxItems=3
 	// +kubebuilder:validation:MinItems=1
@@ -101,8 +139,14 @@ type OAuth2ClientSpec struct {
 	//
 	// Scope is a string containing a space-separated list of scope values (as
 	// described in Section 3.3 of OAuth 2.0 [RFC6749]) that the client
-	// can use when requesting access tokens.
-	Scope string `json:"scope"`
+	// can use when requesting access tokens. It is mutually exclusive with
+	// ScopeArray.
+	Scope string `json:"scope,omitempty"`
+
+	// ScopeArray is an array of scope values (as described in Section 3.3 of
+	// OAuth 2.0 [RFC6749]) that the client can use when requesting access
+	// tokens. It is mutually exclusive with Scope.
+	ScopeArray []string `json:"scopeArray,omitempty"`
 
 	// +kubebuilder:validation:MinLength=1
 	// +kubebuilder:validation:MaxLength=253
@@ -115,19 +159,371 @@ type OAuth2ClientSpec struct {
 	// this client
 	HydraAdmin HydraAdmin `json:"hydraAdmin,omitempty"`
 
+	// HydraInstanceRef references the HydraInstance to manage this client in.
+	// It is mutually exclusive with HydraAdmin.
+	HydraInstanceRef *apiv1.LocalObjectReference `json:"hydraInstanceRef,omitempty"`
+
 	// +kubebuilder:validation:Enum=client_secret_basic;client_secret_post;private_key_jwt;none
 	//
 	// Indication which authentication method shoud be used for the token endpoint
 	TokenEndpointAuthMethod TokenEndpointAuthMethod `json:"tokenEndpointAuthMethod,omitempty"`
 
+	// +kubebuilder:validation:Enum=RS256;RS384;RS512;PS256;PS384;PS512;ES256;ES384;ES512;HS256;HS384;HS512
+	//
+	// TokenEndpointAuthSigningAlg is the algorithm that must be used for
+	// signing the JWT used to authenticate the client at the token endpoint
+	// with the `private_key_jwt` or `client_secret_jwt` methods
+	TokenEndpointAuthSigningAlg string `json:"tokenEndpointAuthSigningAlg,omitempty"`
+
+	// +kubebuilder:validation:Enum=opaque;jwt
+	//
+	// AccessTokenStrategy is the strategy ORY Hydra issues the access tokens
+	// of this client with, overriding its global setting. With `jwt` and no
+	// audience set, the audience defaults to the one the
+	// HydraMaesterConfiguration maps the namespace of the client to.
+	AccessTokenStrategy AccessTokenStrategy `json:"accessTokenStrategy,omitempty"`
+
 	// Metadata is abritrary data
 	Metadata json.RawMessage `json:"metadata,omitempty"`
+
+	// SkipConsent skips the consent screen for this client. It should only
+	// be set for trusted first-party clients.
+	SkipConsent bool `json:"skipConsent,omitempty"`
+
+	// SkipLogoutConsent skips the logout consent screen for this client. It
+	// should only be set for trusted first-party clients.
+	SkipLogoutConsent bool `json:"skipLogoutConsent,omitempty"`
+
+	// UnmanagedFields lists ORY Hydra client properties, by their JSON name
+	// (e.g. `redirect_uris`), that are managed outside of this resource. Their
+	// current values in ORY Hydra are preserved when the client is updated.
+	UnmanagedFields []string `json:"unmanagedFields,omitempty"`
+
+	// GCExempt marks the client in ORY Hydra as exempt from garbage
+	// collection, so it is only removed from ORY Hydra when this resource is
+	// deleted.
+	GCExempt bool `json:"gcExempt,omitempty"`
+
+	// UpdateStrategy defines how the client is updated in ORY Hydra. With
+	// `Replace` (the default) all properties are asserted, with `Merge` only
+	// properties set in this spec are asserted and all others are left untouched.
+	UpdateStrategy UpdateStrategy `json:"updateStrategy,omitempty"`
+
+	// ConflictPolicy defines what happens when the client has been changed in
+	// ORY Hydra since the controller last wrote it. With `Overwrite` (the
+	// default) the changes are overwritten, with `Hold` the client is left
+	// untouched until the policy is changed to `Overwrite`. Either way, the
+	// ConflictDetected condition is set.
+	ConflictPolicy ConflictPolicy `json:"conflictPolicy,omitempty"`
+
+	// SecretTTL is the lifetime of the client secret. Once it has passed ORY
+	// Hydra rejects the secret and the SecretExpired condition is set.
+	SecretTTL *metav1.Duration `json:"secretTTL,omitempty"`
+
+	// RotationPolicy, if set, makes the controller rotate the client secret
+	// once it is older than the policy allows
+	RotationPolicy *RotationPolicy `json:"rotationPolicy,omitempty"`
+
+	// RevokeTokensOnRotation deletes the access tokens issued to the client from
+	// ORY Hydra once its client secret has been rotated, so that tokens obtained
+	// with leaked credentials can't be used any longer
+	RevokeTokensOnRotation bool `json:"revokeTokensOnRotation,omitempty"`
+
+	// ExpiresAfter is the lifetime of the client, counted from the creation
+	// of this resource. Once it has passed the client expires.
+	ExpiresAfter *metav1.Duration `json:"expiresAfter,omitempty"`
+
+	// NotAfter is the time the client expires at. If ExpiresAfter is set as
+	// well, the client expires at whichever comes first.
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+
+	// ExpiryAction defines what happens once the client has expired. With
+	// `Disable` (the default) the client is deleted from ORY Hydra, this
+	// resource and its Secret are kept and the Expired condition is set. With
+	// `Delete` this resource is deleted as well.
+	ExpiryAction ExpiryAction `json:"expiryAction,omitempty"`
+
+	// Suspend stops the reconciliation of the client, e.g. to freeze it
+	// during an incident. The client in ORY Hydra and the Secret are left as
+	// they are until it is unset, but deleting this resource still deletes
+	// the client.
+	Suspend bool `json:"suspend,omitempty"`
+
+	// DeletionPolicy defines what happens to the client in ORY Hydra and the
+	// Secret when this resource is deleted. With `Delete` (the default) both
+	// are deleted, with `Orphan` both are kept and the client is exempted from
+	// garbage collection.
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// ConsentHints are hints for login and consent apps. They are added to
+	// the client metadata under the `hydra-maester.ory.sh/consent-hints` key.
+	ConsentHints *ConsentHints `json:"consentHints,omitempty"`
+
+	// Extra holds ORY Hydra client properties, by their JSON name, that are
+	// not modeled by this spec yet. They are sent to ORY Hydra as they are,
+	// but never override the properties modeled by this spec.
+	Extra map[string]apiextensionsv1beta1.JSON `json:"extra,omitempty"`
+
+	// SecretProjection defines which client properties are written to the
+	// Secret and under which keys. By default the Secret holds the client ID
+	// under `client_id` and the client secret under `client_secret`.
+	SecretProjection *SecretProjection `json:"secretProjection,omitempty"`
+
+	// SecretType is the type of the Secret, e.g. `kubernetes.io/basic-auth`
+	// for consumers requiring it, or a custom type. It defaults to `Opaque`.
+	// Secrets of type `kubernetes.io/basic-auth` hold the client ID under
+	// `username` and the client secret under `password` by default. Changing
+	// the type replaces the Secret, as Kubernetes doesn't allow updating it.
+	SecretType apiv1.SecretType `json:"secretType,omitempty"`
+
+	// SecretConflictPolicy defines what happens when a Secret named SecretName
+	// exists which the controller didn't create and can't use as the provided
+	// credentials of the client. With `Fail` (the default) the reconciliation
+	// fails until SecretName is changed, with `Adopt` the Secret is taken over,
+	// keeping its labels and annotations, unless another object controls it,
+	// and with `Overwrite` it is replaced.
+	SecretConflictPolicy SecretConflictPolicy `json:"secretConflictPolicy,omitempty"`
+
+	// SecretReplicationNamespaces are the namespaces, or regular expressions
+	// matching them, the Secret is replicated to by replication controllers
+	// such as kubernetes-replicator or Reflector, so that workloads in several
+	// namespaces can use the credentials of the client
+	SecretReplicationNamespaces []string `json:"secretReplicationNamespaces,omitempty"`
+
+	// SecretTargets are namespaces the controller keeps copies of the Secret
+	// in, for applications split across namespaces sharing the client. A
+	// namespace only accepts copies if its
+	// `hydra-maester.ory.sh/accept-secrets-from` annotation lists the
+	// namespace of the client.
+	SecretTargets []SecretTarget `json:"secretTargets,omitempty"`
+
+	// ExistingSecretRef references a Secret in the namespace of the client
+	// holding the `client_id` and `client_secret` the client is registered
+	// with, instead of credentials generated by ORY Hydra, e.g. to migrate an
+	// application with fixed credentials. It is only read when the client is
+	// registered and its Secret doesn't exist yet.
+	ExistingSecretRef *apiv1.LocalObjectReference `json:"existingSecretRef,omitempty"`
+
+	// SecretLabels are set on the Secret, e.g. for policy engines, backup
+	// tools or reloaders to match it. They don't override the labels set by
+	// the controller.
+	SecretLabels map[string]string `json:"secretLabels,omitempty"`
+
+	// SecretAnnotations are set on the Secret. They don't override the
+	// annotations set by the controller.
+	SecretAnnotations map[string]string `json:"secretAnnotations,omitempty"`
+
+	// SecretPush, if set, pushes the Secret to an external secret store with
+	// a PushSecret of the External Secrets Operator, once the client is
+	// registered in ORY Hydra
+	SecretPush *SecretPush `json:"secretPush,omitempty"`
+
+	// TemplateRef references an OAuth2ClientTemplate in the namespace of the
+	// client. Settings which are not set in this spec are taken from it.
+	TemplateRef *apiv1.LocalObjectReference `json:"templateRef,omitempty"`
+}
+
+// SecretTarget is a namespace a copy of the Secret of a client is kept in
+type SecretTarget struct {
+	// +kubebuilder:validation:MinLength=1
+	//
+	// Namespace is the namespace of the copy
+	Namespace string `json:"namespace"`
+
+	// Name is the name of the copy, the name of the Secret by default
+	Name string `json:"name,omitempty"`
+}
+
+// SecretPush defines how the Secret of a client is pushed to an external
+// secret store by the External Secrets Operator
+type SecretPush struct {
+	// SecretStoreRef references the store the Secret is pushed to
+	SecretStoreRef SecretStoreRef `json:"secretStoreRef"`
+
+	// RemoteKey is the key of the credentials in the store, the namespace
+	// and name of the client, e.g. `default/my-client`, by default
+	RemoteKey string `json:"remoteKey,omitempty"`
+}
+
+// SecretStoreRef references a SecretStore of the External Secrets Operator
+type SecretStoreRef struct {
+	// +kubebuilder:validation:MinLength=1
+	//
+	// Name is the name of the store
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Enum=SecretStore;ClusterSecretStore
+	//
+	// Kind is `SecretStore` (the default), a store in the namespace of the
+	// client, or `ClusterSecretStore`
+	Kind string `json:"kind,omitempty"`
+}
+
+// RotationPolicy defines when the client secret is rotated by the controller
+type RotationPolicy struct {
+	// MaxAge is the age of the client secret past which it is replaced by a
+	// new one in ORY Hydra and in the Secret
+	MaxAge metav1.Duration `json:"maxAge"`
+
+	// Overlap is how long the previous client secret is kept in the Secret
+	// after a rotation, under the key of the client secret suffixed with
+	// `_previous`. It isn't kept by default.
+	Overlap metav1.Duration `json:"overlap,omitempty"`
+}
+
+// SecretProjection defines the layout of the Secret holding the credentials
+// of a client
+type SecretProjection struct {
+	// Format is `Keys` (the default) to write each property under its own
+	// key, or `JSON` to write all properties as a single JSON object
+	Format SecretProjectionFormat `json:"format,omitempty"`
+
+	// JSONKey is the key of the JSON object with the `JSON` format,
+	// `client.json` by default
+	JSONKey string `json:"jsonKey,omitempty"`
+
+	// +kubebuilder:validation:MinItems=1
+	//
+	// Items are the properties written to the Secret. The client ID, and the
+	// client secret unless the token endpoint auth method is `none`, must be
+	// included.
+	Items []SecretProjectionItem `json:"items"`
+
+	// Templates are additional keys rendered from Go templates, e.g. a
+	// configuration file ready to be mounted by applications
+	Templates []SecretTemplate `json:"templates,omitempty"`
+}
+
+// SecretTemplate renders a key of the Secret from a Go template. The
+// template is executed with the client properties by their name, e.g.
+// `{{ .client_id }}`; properties without a value are empty. The `json`
+// function quotes a value as a JSON string.
+type SecretTemplate struct {
+	// +kubebuilder:validation:MinLength=1
+	//
+	// Key is the Secret key the template is rendered under
+	Key string `json:"key"`
+
+	// Template is the Go template rendered under Key
+	Template string `json:"template"`
+}
+
+// SecretProjectionItem maps a client property to a key
+type SecretProjectionItem struct {
+	// Property is the client property to write
+	Property SecretProperty `json:"property"`
+
+	// Key is the Secret key with the `Keys` format, or the field name of the
+	// JSON object with the `JSON` format. It defaults to the property name.
+	Key string `json:"key,omitempty"`
+
+	// Encoding is how the value is written, `Raw` (the default) as it is,
+	// `Hex` as lowercase hexadecimal or `Base64URL` as unpadded base64url,
+	// for consumers requiring a specific encoding of e.g. the client secret
+	Encoding SecretValueEncoding `json:"encoding,omitempty"`
+}
+
+// GetKey returns the key the property is written under
+func (i SecretProjectionItem) GetKey() string {
+	if i.Key != "" {
+		return i.Key
+	}
+	return string(i.Property)
+}
+
+// secretTemplateFuncs are the functions available to SecretTemplates
+var secretTemplateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		raw, err := json.Marshal(v)
+		return string(raw), err
+	},
+}
+
+// Parse parses the template of t
+func (t SecretTemplate) Parse() (*template.Template, error) {
+	return template.New(t.Key).Option("missingkey=zero").Funcs(secretTemplateFuncs).Parse(t.Template)
+}
+
+// +kubebuilder:validation:Enum=Keys;JSON
+// SecretProjectionFormat represents the layout of a Secret
+type SecretProjectionFormat string
+
+const (
+	SecretProjectionFormatKeys SecretProjectionFormat = "Keys"
+	SecretProjectionFormatJSON SecretProjectionFormat = "JSON"
+
+	// DefaultSecretProjectionJSONKey is the key of the JSON object written
+	// with the JSON format if the projection does not specify one
+	DefaultSecretProjectionJSONKey = "client.json"
+)
+
+// +kubebuilder:validation:Enum=Raw;Hex;Base64URL
+// SecretValueEncoding represents how a client property is encoded in a Secret
+type SecretValueEncoding string
+
+const (
+	SecretValueEncodingRaw       SecretValueEncoding = "Raw"
+	SecretValueEncodingHex       SecretValueEncoding = "Hex"
+	SecretValueEncodingBase64URL SecretValueEncoding = "Base64URL"
+)
+
+// +kubebuilder:validation:Enum=client_id;client_secret;scope;audience;issuer;authorization_endpoint;token_endpoint
+// SecretProperty represents a client property that can be written to a Secret
+type SecretProperty string
+
+const (
+	SecretPropertyClientID              SecretProperty = "client_id"
+	SecretPropertyClientSecret          SecretProperty = "client_secret"
+	SecretPropertyScope                 SecretProperty = "scope"
+	SecretPropertyAudience              SecretProperty = "audience"
+	SecretPropertyIssuer                SecretProperty = "issuer"
+	SecretPropertyAuthorizationEndpoint SecretProperty = "authorization_endpoint"
+	SecretPropertyTokenEndpoint         SecretProperty = "token_endpoint"
+)
+
+// ConsentHints describe how login and consent apps should present a client.
+// They are a stable contract, serialized into the client metadata as a JSON
+// object with the field names below.
+type ConsentHints struct {
+	// DisplayName is the name shown to the end-user instead of the client name
+	DisplayName string `json:"displayName,omitempty"`
+
+	// LogoURI is the URL of a logo shown to the end-user
+	LogoURI string `json:"logoUri,omitempty"`
+
+	// FirstParty marks the client as operated by the same party as the
+	// consent app, which may then skip or simplify the consent screen
+	FirstParty bool `json:"firstParty,omitempty"`
+
+	// RememberConsent hints that granted consent should be remembered
+	RememberConsent bool `json:"rememberConsent,omitempty"`
+
+	// ScopeDescriptions maps scopes to human-readable descriptions shown on
+	// the consent screen
+	ScopeDescriptions map[string]string `json:"scopeDescriptions,omitempty"`
 }
 
-// +kubebuilder:validation:Enum=client_credentials;authorization_code;implicit;refresh_token
+// +kubebuilder:validation:Enum=client_credentials;authorization_code;implicit;refresh_token;urn:ietf:params:oauth:grant-type:device_code;urn:ietf:params:oauth:grant-type:token-exchange
 // GrantType represents an OAuth 2.0 grant type
 type GrantType string
 
+const (
+	// GrantTypeDeviceCode is the OAuth 2.0 device authorization grant type (RFC 8628)
+	GrantTypeDeviceCode GrantType = "urn:ietf:params:oauth:grant-type:device_code"
+	// GrantTypeTokenExchange is the OAuth 2.0 token exchange grant type (RFC 8693)
+	GrantTypeTokenExchange GrantType = "urn:ietf:params:oauth:grant-type:token-exchange"
+)
+
+// +kubebuilder:validation:Enum=machine
+// ClientProfile represents a preset of client settings. It is superseded by
+// ClientArchetype.
+type ClientProfile string
+
+const (
+	// ClientProfileMachine is the profile of machine-to-machine clients
+	ClientProfileMachine ClientProfile = "machine"
+)
+
 // +kubebuilder:validation:Enum=id_token;code;token
 // ResponseType represents an OAuth 2.0 response type strings
 type ResponseType string
@@ -140,11 +536,122 @@ type RedirectURI string
 // TokenEndpointAuthMethod represents an authentication method for token endpoint
 type TokenEndpointAuthMethod string
 
+// +kubebuilder:validation:Enum=Replace;Merge
+// UpdateStrategy represents how a registered client is updated in ORY Hydra
+type UpdateStrategy string
+
+const (
+	UpdateStrategyReplace UpdateStrategy = "Replace"
+	UpdateStrategyMerge   UpdateStrategy = "Merge"
+)
+
+// +kubebuilder:validation:Enum=Overwrite;Hold
+// ConflictPolicy represents how changes made to a client outside of the
+// controller are resolved
+type ConflictPolicy string
+
+const (
+	ConflictPolicyOverwrite ConflictPolicy = "Overwrite"
+	ConflictPolicyHold      ConflictPolicy = "Hold"
+)
+
+// +kubebuilder:validation:Enum=Fail;Adopt;Overwrite
+// SecretConflictPolicy represents how a Secret which exists before the
+// controller writes the credentials of a client is handled
+type SecretConflictPolicy string
+
+const (
+	SecretConflictPolicyFail      SecretConflictPolicy = "Fail"
+	SecretConflictPolicyAdopt     SecretConflictPolicy = "Adopt"
+	SecretConflictPolicyOverwrite SecretConflictPolicy = "Overwrite"
+)
+
+// +kubebuilder:validation:Enum=opaque;jwt
+// AccessTokenStrategy represents the format of the access tokens of a client
+type AccessTokenStrategy string
+
+const (
+	AccessTokenStrategyOpaque AccessTokenStrategy = "opaque"
+	AccessTokenStrategyJWT    AccessTokenStrategy = "jwt"
+)
+
+// +kubebuilder:validation:Enum=Disable;Delete
+// ExpiryAction represents what happens to a client once it has expired
+type ExpiryAction string
+
+const (
+	ExpiryActionDisable ExpiryAction = "Disable"
+	ExpiryActionDelete  ExpiryAction = "Delete"
+)
+
+// +kubebuilder:validation:Enum=Delete;Orphan
+// DeletionPolicy represents what happens to a client once its resource is
+// deleted
+type DeletionPolicy string
+
+const (
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+)
+
 // OAuth2ClientStatus defines the observed state of OAuth2Client
 type OAuth2ClientStatus struct {
-	// ObservedGeneration represents the most recent generation observed by the daemon set controller.
+	// ObservedGeneration is the most recent generation of the spec that has
+	// been successfully applied to ORY Hydra
 	ObservedGeneration  int64               `json:"observedGeneration,omitempty"`
 	ReconciliationError ReconciliationError `json:"reconciliationError,omitempty"`
+
+	// ClientID is the ID of the client registered in ORY Hydra
+	ClientID string `json:"clientID,omitempty"`
+
+	// DeviceAuthorization holds the endpoints a device flow client needs to
+	// bootstrap. It is only set for clients allowed to use the device code grant
+	// when the controller knows ORY Hydra's public URL.
+	DeviceAuthorization *DeviceAuthorization `json:"deviceAuthorization,omitempty"`
+
+	// ClientSecretExpiresAt is the time the client secret expires at, if the
+	// client has a SecretTTL
+	ClientSecretExpiresAt *metav1.Time `json:"clientSecretExpiresAt,omitempty"`
+
+	// ClientSecretIssuedAt is the time the current client secret was issued
+	// at, if the client has a rotation policy
+	ClientSecretIssuedAt *metav1.Time `json:"clientSecretIssuedAt,omitempty"`
+
+	// PreviousClientSecretExpiresAt is the time the previous client secret
+	// is removed from the Secret at, while it is kept after a rotation
+	PreviousClientSecretExpiresAt *metav1.Time `json:"previousClientSecretExpiresAt,omitempty"`
+
+	// HydraUpdatedAt is the time ORY Hydra reported the client as updated at
+	// after the last write by the controller
+	HydraUpdatedAt string `json:"hydraUpdatedAt,omitempty"`
+
+	// ManualSecretVersion is the resource version of the manually set client
+	// secret last pushed to ORY Hydra
+	ManualSecretVersion string `json:"manualSecretVersion,omitempty"`
+
+	// RotationRequest is the value of the rotate annotation the client
+	// secret was last rotated for
+	RotationRequest string `json:"rotationRequest,omitempty"`
+
+	// SecretChecksum is the checksum of the data of the Secret generated for
+	// the client, as in its checksum annotation, which changes whenever the
+	// credentials do
+	SecretChecksum string `json:"secretChecksum,omitempty"`
+
+	// TemplateGeneration is the generation of the OAuth2ClientTemplate last
+	// applied to ORY Hydra
+	TemplateGeneration int64 `json:"templateGeneration,omitempty"`
+
+	// Conditions represent the latest available observations of the client's state
+	Conditions []OAuth2ClientCondition `json:"conditions,omitempty"`
+}
+
+// DeviceAuthorization represents the device flow endpoints exposed by ORY Hydra
+type DeviceAuthorization struct {
+	// DeviceAuthorizationEndpoint is the URL devices request a device code from
+	DeviceAuthorizationEndpoint string `json:"deviceAuthorizationEndpoint,omitempty"`
+	// VerificationURI is the URL users visit to enter the user code
+	VerificationURI string `json:"verificationUri,omitempty"`
 }
 
 // ReconciliationError represents an error that occurred during the reconciliation process
@@ -156,7 +663,14 @@ type ReconciliationError struct {
 }
 
 // +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=oac;oauth2c,categories=ory;all
 // +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Client ID",type=string,JSONPath=`.status.clientID`
+// +kubebuilder:printcolumn:name="Secret",type=string,JSONPath=`.spec.secretName`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Error",type=string,JSONPath=`.status.reconciliationError.statusCode`,priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // OAuth2Client is the Schema for the oauth2clients API
 type OAuth2Client struct {
@@ -180,21 +694,230 @@ func init() {
 	SchemeBuilder.Register(&OAuth2Client{}, &OAuth2ClientList{})
 }
 
+// GetScope returns the space-separated list of scope values of the client
+func (c *OAuth2Client) GetScope() string {
+	if len(c.Spec.ScopeArray) > 0 {
+		return strings.Join(c.Spec.ScopeArray, " ")
+	}
+	return c.Spec.Scope
+}
+
+// GetExpiresAt returns the time the client expires at, given by ExpiresAfter
+// or NotAfter, whichever comes first, and whether the client expires at all
+func (c *OAuth2Client) GetExpiresAt() (time.Time, bool) {
+	var expiresAt time.Time
+	if c.Spec.ExpiresAfter != nil {
+		expiresAt = c.CreationTimestamp.Add(c.Spec.ExpiresAfter.Duration)
+	}
+	if c.Spec.NotAfter != nil && (expiresAt.IsZero() || c.Spec.NotAfter.Time.Before(expiresAt)) {
+		expiresAt = c.Spec.NotAfter.Time
+	}
+	return expiresAt, !expiresAt.IsZero()
+}
+
+// GetExpiryAction returns what happens to the client once it has expired
+func (c *OAuth2Client) GetExpiryAction() ExpiryAction {
+	if c.Spec.ExpiryAction != "" {
+		return c.Spec.ExpiryAction
+	}
+	return ExpiryActionDisable
+}
+
+// GetDeletionPolicy returns what happens to the client once this resource is
+// deleted
+func (c *OAuth2Client) GetDeletionPolicy() DeletionPolicy {
+	if c.Spec.DeletionPolicy != "" {
+		return c.Spec.DeletionPolicy
+	}
+	return DeletionPolicyDelete
+}
+
+// Validate checks the constraints on the spec which can't be expressed in the
+// CRD validation schema
+func (c *OAuth2Client) Validate() error {
+	if c.Spec.Scope != "" && len(c.Spec.ScopeArray) > 0 {
+		return errors.New("scope and scopeArray are mutually exclusive")
+	}
+	if c.GetScope() == "" {
+		return errors.New("either scope or scopeArray must be set")
+	}
+	if c.Spec.HydraInstanceRef != nil && c.Spec.HydraAdmin != (HydraAdmin{}) {
+		return errors.New("hydraAdmin and hydraInstanceRef are mutually exclusive")
+	}
+	if len(c.GetGrantTypes()) == 0 {
+		return errors.New("grantTypes must be set unless an archetype is set")
+	}
+	if c.Spec.RotationPolicy != nil && c.Spec.RotationPolicy.MaxAge.Duration <= 0 {
+		return errors.New("rotationPolicy.maxAge must be positive")
+	}
+	if p := c.Spec.RotationPolicy; p != nil && (p.Overlap.Duration < 0 || p.Overlap.Duration >= p.MaxAge.Duration) {
+		return errors.New("rotationPolicy.overlap must not be negative and must be shorter than rotationPolicy.maxAge")
+	}
+	if err := c.validateArchetype(); err != nil {
+		return err
+	}
+	if err := c.validateSecretType(); err != nil {
+		return err
+	}
+	return c.validateSecretProjection()
+}
+
+// validateSecretType rejects the built-in Secret types other than
+// `kubernetes.io/basic-auth`, whose keys can't hold the credentials, and
+// SecretProjections without the keys required by `kubernetes.io/basic-auth`
+func (c *OAuth2Client) validateSecretType() error {
+	switch t := c.Spec.SecretType; {
+	case t == "" || t == apiv1.SecretTypeOpaque:
+		return nil
+	case t == apiv1.SecretTypeBasicAuth:
+	case strings.HasPrefix(string(t), "kubernetes.io/"):
+		return fmt.Errorf("secretType %s is not supported, use Opaque, %s or a custom type", t, apiv1.SecretTypeBasicAuth)
+	default:
+		return nil
+	}
+
+	p := c.Spec.SecretProjection
+	if p == nil {
+		return nil
+	}
+	if p.Format == SecretProjectionFormatJSON {
+		return fmt.Errorf("the secret projection of a %s secret must use the Keys format", apiv1.SecretTypeBasicAuth)
+	}
+	for _, item := range p.Items {
+		if key := item.GetKey(); key == apiv1.BasicAuthUsernameKey || key == apiv1.BasicAuthPasswordKey {
+			return nil
+		}
+	}
+	return fmt.Errorf("the secret projection of a %s secret must use the %s or %s key", apiv1.SecretTypeBasicAuth, apiv1.BasicAuthUsernameKey, apiv1.BasicAuthPasswordKey)
+}
+
+func (c *OAuth2Client) validateSecretProjection() error {
+	p := c.Spec.SecretProjection
+	if p == nil {
+		return nil
+	}
+
+	properties, keys := map[SecretProperty]bool{}, map[string]bool{}
+	for _, item := range p.Items {
+		if properties[item.Property] {
+			return fmt.Errorf("property %s is projected more than once", item.Property)
+		}
+		if keys[item.GetKey()] {
+			return fmt.Errorf("key %s is used more than once in the secret projection", item.GetKey())
+		}
+		properties[item.Property], keys[item.GetKey()] = true, true
+	}
+
+	if !properties[SecretPropertyClientID] {
+		return errors.New("the secret projection must include client_id")
+	}
+	if !properties[SecretPropertyClientSecret] && c.GetTokenEndpointAuthMethod() != "none" {
+		return errors.New("the secret projection must include client_secret")
+	}
+
+	if p.Format == SecretProjectionFormatJSON {
+		keys = map[string]bool{p.JSONKey: true}
+		if p.JSONKey == "" {
+			keys = map[string]bool{DefaultSecretProjectionJSONKey: true}
+		}
+	}
+	for _, t := range p.Templates {
+		if keys[t.Key] {
+			return fmt.Errorf("key %s is used more than once in the secret projection", t.Key)
+		}
+		keys[t.Key] = true
+		if _, err := t.Parse(); err != nil {
+			return fmt.Errorf("the template of key %s is invalid: %w", t.Key, err)
+		}
+	}
+	return nil
+}
+
+// HasGrantType reports whether the client is allowed to use the given grant type
+func (c *OAuth2Client) HasGrantType(gt GrantType) bool {
+	for _, elem := range c.GetGrantTypes() {
+		if elem == gt {
+			return true
+		}
+	}
+	return false
+}
+
 // ToOAuth2ClientJSON converts an OAuth2Client into a OAuth2ClientJSON object that represents an OAuth2 client digestible by ORY Hydra
-func (c *OAuth2Client) ToOAuth2ClientJSON() *hydra.OAuth2ClientJSON {
-	return &hydra.OAuth2ClientJSON{
-		ClientName:              c.Spec.ClientName,
-		GrantTypes:              grantToStringSlice(c.Spec.GrantTypes),
-		ResponseTypes:           responseToStringSlice(c.Spec.ResponseTypes),
-		RedirectURIs:            redirectToStringSlice(c.Spec.RedirectURIs),
-		PostLogoutRedirectURIs:  redirectToStringSlice(c.Spec.PostLogoutRedirectURIs),
-		AllowedCorsOrigins:      redirectToStringSlice(c.Spec.AllowedCorsOrigins),
-		Audience:                c.Spec.Audience,
-		Scope:                   c.Spec.Scope,
-		Owner:                   fmt.Sprintf("%s/%s", c.Name, c.Namespace),
-		TokenEndpointAuthMethod: string(c.Spec.TokenEndpointAuthMethod),
-		Metadata:                c.Spec.Metadata,
+func (c *OAuth2Client) ToOAuth2ClientJSON() *hydraclient.OAuth2ClientJSON {
+	var clientID *string
+	if c.Spec.ClientID != "" {
+		clientID = &c.Spec.ClientID
+	}
+
+	return &hydraclient.OAuth2ClientJSON{
+		ClientID:                    clientID,
+		ClientName:                  c.Spec.ClientName,
+		GrantTypes:                  grantToStringSlice(c.GetGrantTypes()),
+		ResponseTypes:               responseToStringSlice(c.GetResponseTypes()),
+		RedirectURIs:                redirectToStringSlice(c.Spec.RedirectURIs),
+		PostLogoutRedirectURIs:      redirectToStringSlice(c.Spec.PostLogoutRedirectURIs),
+		AllowedCorsOrigins:          redirectToStringSlice(c.Spec.AllowedCorsOrigins),
+		Audience:                    c.Spec.Audience,
+		Scope:                       c.GetScope(),
+		Owner:                       fmt.Sprintf("%s/%s", c.Name, c.Namespace),
+		TokenEndpointAuthMethod:     string(c.GetTokenEndpointAuthMethod()),
+		TokenEndpointAuthSigningAlg: c.Spec.TokenEndpointAuthSigningAlg,
+		AccessTokenStrategy:         string(c.Spec.AccessTokenStrategy),
+		Metadata:                    c.metadata(),
+		SkipConsent:                 c.Spec.SkipConsent,
+		SkipLogoutConsent:           c.Spec.SkipLogoutConsent,
+		ClientSecretExpiresAt:       c.clientSecretExpiresAt(),
+		Extra:                       c.extra(),
+	}
+}
+
+func (c *OAuth2Client) extra() map[string]json.RawMessage {
+	if len(c.Spec.Extra) == 0 {
+		return nil
+	}
+	extra := make(map[string]json.RawMessage, len(c.Spec.Extra))
+	for name, value := range c.Spec.Extra {
+		extra[name] = json.RawMessage(value.Raw)
+	}
+	return extra
+}
+
+func (c *OAuth2Client) clientSecretExpiresAt() int64 {
+	if c.Spec.SecretTTL == nil || c.Status.ClientSecretExpiresAt == nil {
+		return 0
+	}
+	return c.Status.ClientSecretExpiresAt.Unix()
+}
+
+// metadata returns the client metadata sent to ORY Hydra, which is the
+// user-provided metadata extended with the markers set by the controller
+func (c *OAuth2Client) metadata() json.RawMessage {
+	markers := map[string]interface{}{
+		hydraclient.MetadataManagedKey: true,
+	}
+	if c.Spec.GCExempt {
+		markers[hydraclient.MetadataGCExemptKey] = true
+	}
+	if c.Spec.ConsentHints != nil {
+		markers[hydraclient.MetadataConsentHintsKey] = c.Spec.ConsentHints
+	}
+
+	metadata := map[string]interface{}{}
+	if len(c.Spec.Metadata) > 0 {
+		if err := json.Unmarshal(c.Spec.Metadata, &metadata); err != nil {
+			return c.Spec.Metadata
+		}
+	}
+	for key, value := range markers {
+		metadata[key] = value
+	}
+
+	raw, err := json.Marshal(metadata)
+	if err != nil {
+		return c.Spec.Metadata
 	}
+	return raw
 }
 
 func responseToStringSlice(rt []ResponseType) []string {
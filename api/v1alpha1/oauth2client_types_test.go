@@ -19,10 +19,12 @@ import (
 	"fmt"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/net/context"
+	apiv1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -98,12 +100,76 @@ func TestCreateAPI(t *testing.T) {
 			require.Error(t, getErr)
 		})
 
+		t.Run("by creating an API object with the token exchange grant type", func(t *testing.T) {
+
+			resetTestClient()
+
+			created.Spec.GrantTypes = []GrantType{"client_credentials", GrantTypeTokenExchange}
+
+			createErr = k8sClient.Create(context.TODO(), created)
+			require.NoError(t, createErr)
+
+			fetched = &OAuth2Client{}
+			getErr = k8sClient.Get(context.TODO(), key, fetched)
+			require.NoError(t, getErr)
+			assert.Equal(t, created, fetched)
+
+			deleteErr = k8sClient.Delete(context.TODO(), created)
+			require.NoError(t, deleteErr)
+		})
+
+		t.Run("by creating an API object with the machine profile", func(t *testing.T) {
+
+			resetTestClient()
+
+			created.Spec.Profile = ClientProfileMachine
+			created.Spec.GrantTypes = nil
+			created.Spec.ResponseTypes = nil
+
+			createErr = k8sClient.Create(context.TODO(), created)
+			require.NoError(t, createErr)
+
+			fetched = &OAuth2Client{}
+			getErr = k8sClient.Get(context.TODO(), key, fetched)
+			require.NoError(t, getErr)
+			assert.Equal(t, created, fetched)
+			require.NoError(t, fetched.Validate())
+			assert.Equal(t, []string{"client_credentials"}, fetched.ToOAuth2ClientJSON().GrantTypes)
+			assert.Equal(t, "client_secret_basic", fetched.ToOAuth2ClientJSON().TokenEndpointAuthMethod)
+
+			deleteErr = k8sClient.Delete(context.TODO(), created)
+			require.NoError(t, deleteErr)
+		})
+
+		t.Run("by creating an API object with scopes given as an array", func(t *testing.T) {
+
+			resetTestClient()
+
+			created.Spec.Scope = ""
+			created.Spec.ScopeArray = []string{"read", "write"}
+
+			createErr = k8sClient.Create(context.TODO(), created)
+			require.NoError(t, createErr)
+
+			fetched = &OAuth2Client{}
+			getErr = k8sClient.Get(context.TODO(), key, fetched)
+			require.NoError(t, getErr)
+			assert.Equal(t, created, fetched)
+			assert.Equal(t, "read write", fetched.GetScope())
+
+			deleteErr = k8sClient.Delete(context.TODO(), created)
+			require.NoError(t, deleteErr)
+		})
+
 		t.Run("by failing if the requested object doesn't meet CRD requirements", func(t *testing.T) {
 
 			for desc, modifyClient := range map[string]func(){
 				"invalid grant type":            func() { created.Spec.GrantTypes = []GrantType{"invalid"} },
+				"invalid profile":               func() { created.Spec.Profile = "invalid" },
+				"invalid archetype":             func() { created.Spec.Archetype = "invalid" },
 				"invalid response type":         func() { created.Spec.ResponseTypes = []ResponseType{"invalid"} },
 				"invalid scope":                 func() { created.Spec.Scope = "" },
+				"scope and scope array":         func() { created.Spec.ScopeArray = []string{"read"} },
 				"missing secret name":           func() { created.Spec.SecretName = "" },
 				"invalid redirect URI":          func() { created.Spec.RedirectURIs = []RedirectURI{"invalid"} },
 				"invalid logout redirect URI":   func() { created.Spec.PostLogoutRedirectURIs = []RedirectURI{"invalid"} },
@@ -111,6 +177,10 @@ func TestCreateAPI(t *testing.T) {
 				"invalid hydra port high":       func() { created.Spec.HydraAdmin.Port = 65536 },
 				"invalid hydra endpoint":        func() { created.Spec.HydraAdmin.Endpoint = "invalid" },
 				"invalid hydra forwarded proto": func() { created.Spec.HydraAdmin.Endpoint = "invalid" },
+				"invalid signing algorithm":     func() { created.Spec.TokenEndpointAuthSigningAlg = "none" },
+				"invalid secret projection": func() {
+					created.Spec.SecretProjection = &SecretProjection{Items: []SecretProjectionItem{{Property: "invalid"}}}
+				},
 			} {
 				t.Run(fmt.Sprintf("case=%s", desc), func(t *testing.T) {
 
@@ -162,3 +232,122 @@ func resetTestClient() {
 		},
 	}
 }
+
+func TestGetExpiresAt(t *testing.T) {
+
+	created := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	notAfter := metav1.NewTime(created.Add(48 * time.Hour))
+
+	for name, tc := range map[string]struct {
+		spec      OAuth2ClientSpec
+		expiresAt time.Time
+	}{
+		"no expiry":     {OAuth2ClientSpec{}, time.Time{}},
+		"expires after": {OAuth2ClientSpec{ExpiresAfter: &metav1.Duration{Duration: 24 * time.Hour}}, created.Add(24 * time.Hour)},
+		"not after":     {OAuth2ClientSpec{NotAfter: &notAfter}, notAfter.Time},
+		"earliest of both": {OAuth2ClientSpec{
+			ExpiresAfter: &metav1.Duration{Duration: 72 * time.Hour},
+			NotAfter:     &notAfter,
+		}, notAfter.Time},
+	} {
+		t.Run("case="+name, func(t *testing.T) {
+			c := &OAuth2Client{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)}, Spec: tc.spec}
+			expiresAt, ok := c.GetExpiresAt()
+			assert.Equal(t, !tc.expiresAt.IsZero(), ok)
+			assert.True(t, tc.expiresAt.Equal(expiresAt))
+		})
+	}
+}
+
+func TestValidateSecretType(t *testing.T) {
+
+	keys := func(keys ...string) *SecretProjection {
+		p := &SecretProjection{Format: SecretProjectionFormatKeys}
+		for i, key := range keys {
+			p.Items = append(p.Items, SecretProjectionItem{Property: []SecretProperty{SecretPropertyClientID, SecretPropertyClientSecret}[i], Key: key})
+		}
+		return p
+	}
+
+	for name, tc := range map[string]struct {
+		secretType apiv1.SecretType
+		projection *SecretProjection
+		valid      bool
+	}{
+		"default":                       {"", nil, true},
+		"opaque":                        {apiv1.SecretTypeOpaque, nil, true},
+		"custom":                        {"example.com/oauth2-client", nil, true},
+		"basic-auth":                    {apiv1.SecretTypeBasicAuth, nil, true},
+		"basic-auth with its keys":      {apiv1.SecretTypeBasicAuth, keys("username", "password"), true},
+		"basic-auth without its keys":   {apiv1.SecretTypeBasicAuth, keys("id", "secret"), false},
+		"basic-auth with a JSON secret": {apiv1.SecretTypeBasicAuth, &SecretProjection{Format: SecretProjectionFormatJSON, Items: keys("username", "password").Items}, false},
+		"other built-in type":           {apiv1.SecretTypeDockerConfigJson, nil, false},
+	} {
+		t.Run("case="+name, func(t *testing.T) {
+			c := &OAuth2Client{Spec: OAuth2ClientSpec{
+				GrantTypes:       []GrantType{"client_credentials"},
+				Scope:            "read",
+				SecretName:       "foo",
+				SecretType:       tc.secretType,
+				SecretProjection: tc.projection,
+			}}
+			assert.Equal(t, tc.valid, c.Validate() == nil)
+		})
+	}
+}
+
+func TestValidateSecretTemplates(t *testing.T) {
+
+	for name, tc := range map[string]struct {
+		format    SecretProjectionFormat
+		templates []SecretTemplate
+		valid     bool
+	}{
+		"template":               {SecretProjectionFormatKeys, []SecretTemplate{{Key: "config.json", Template: `{"client_id": {{ json .client_id }}}`}}, true},
+		"invalid template":       {SecretProjectionFormatKeys, []SecretTemplate{{Key: "config.json", Template: "{{ .client_id"}}, false},
+		"undefined function":     {SecretProjectionFormatKeys, []SecretTemplate{{Key: "config.json", Template: "{{ yaml .client_id }}"}}, false},
+		"key of an item":         {SecretProjectionFormatKeys, []SecretTemplate{{Key: "client_id", Template: "{{ .client_id }}"}}, false},
+		"key of another":         {SecretProjectionFormatKeys, []SecretTemplate{{Key: "a", Template: ""}, {Key: "a", Template: ""}}, false},
+		"field of the JSON":      {SecretProjectionFormatJSON, []SecretTemplate{{Key: "client_id", Template: "{{ .client_id }}"}}, true},
+		"key of the JSON object": {SecretProjectionFormatJSON, []SecretTemplate{{Key: DefaultSecretProjectionJSONKey, Template: ""}}, false},
+	} {
+		t.Run("case="+name, func(t *testing.T) {
+			c := &OAuth2Client{Spec: OAuth2ClientSpec{
+				GrantTypes: []GrantType{"client_credentials"},
+				Scope:      "read",
+				SecretName: "foo",
+				SecretProjection: &SecretProjection{
+					Format:    tc.format,
+					Items:     []SecretProjectionItem{{Property: SecretPropertyClientID}, {Property: SecretPropertyClientSecret}},
+					Templates: tc.templates,
+				},
+			}}
+			assert.Equal(t, tc.valid, c.Validate() == nil)
+		})
+	}
+}
+
+func TestValidateRotationPolicy(t *testing.T) {
+
+	for name, tc := range map[string]struct {
+		policy RotationPolicy
+		valid  bool
+	}{
+		"max age":              {RotationPolicy{MaxAge: metav1.Duration{Duration: time.Hour}}, true},
+		"overlap":              {RotationPolicy{MaxAge: metav1.Duration{Duration: time.Hour}, Overlap: metav1.Duration{Duration: time.Minute}}, true},
+		"no max age":           {RotationPolicy{}, false},
+		"negative overlap":     {RotationPolicy{MaxAge: metav1.Duration{Duration: time.Hour}, Overlap: metav1.Duration{Duration: -time.Minute}}, false},
+		"overlap over max age": {RotationPolicy{MaxAge: metav1.Duration{Duration: time.Hour}, Overlap: metav1.Duration{Duration: time.Hour}}, false},
+	} {
+		t.Run("case="+name, func(t *testing.T) {
+			policy := tc.policy
+			c := &OAuth2Client{Spec: OAuth2ClientSpec{
+				GrantTypes:     []GrantType{"client_credentials"},
+				Scope:          "read",
+				SecretName:     "foo",
+				RotationPolicy: &policy,
+			}}
+			assert.Equal(t, tc.valid, c.Validate() == nil)
+		})
+	}
+}
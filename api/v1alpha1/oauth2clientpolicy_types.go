@@ -0,0 +1,204 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:validation:Enum=Deny;Warn
+// PolicyEnforcementAction represents what happens to clients violating a policy
+type PolicyEnforcementAction string
+
+const (
+	// PolicyEnforcementDeny fails the reconciliation of violating clients, so
+	// they are not registered or updated in ORY Hydra
+	PolicyEnforcementDeny PolicyEnforcementAction = "Deny"
+	// PolicyEnforcementWarn only reports the violation in the status and
+	// events of violating clients
+	PolicyEnforcementWarn PolicyEnforcementAction = "Warn"
+)
+
+// OAuth2ClientPolicySpec defines the constraints on the OAuth2Clients of the
+// namespaces a policy applies to. Constraints which are not set allow
+// anything.
+type OAuth2ClientPolicySpec struct {
+	// NamespaceSelector selects the namespaces the policy applies to, all
+	// namespaces if it is not set
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// AllowedGrantTypes are the grant types clients may use
+	AllowedGrantTypes []GrantType `json:"allowedGrantTypes,omitempty"`
+
+	// AllowedScopePatterns are regular expressions, each scope value of
+	// clients must match one of them as a whole
+	AllowedScopePatterns []string `json:"allowedScopePatterns,omitempty"`
+
+	// AllowedRedirectURIDomains are the hosts of the redirect URIs clients
+	// may use. `*.example.com` allows all subdomains of example.com.
+	AllowedRedirectURIDomains []string `json:"allowedRedirectURIDomains,omitempty"`
+
+	// EnforcementAction is what happens to clients violating the policy,
+	// `Deny` by default
+	EnforcementAction PolicyEnforcementAction `json:"enforcementAction,omitempty"`
+}
+
+// OAuth2ClientPolicyStatus defines the observed state of OAuth2ClientPolicy
+type OAuth2ClientPolicyStatus struct {
+	// ObservedGeneration is the last generation of the policy which has been
+	// checked
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// ReconciliationError is set if the policy is invalid
+	ReconciliationError ReconciliationError `json:"reconciliationError,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories=ory
+// +kubebuilder:printcolumn:name="Action",type="string",JSONPath=".spec.enforcementAction"
+
+// OAuth2ClientPolicy is the Schema for the oauth2clientpolicies API. Platform
+// admins use it to constrain the OAuth2Clients namespaces may request.
+type OAuth2ClientPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OAuth2ClientPolicySpec   `json:"spec,omitempty"`
+	Status OAuth2ClientPolicyStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OAuth2ClientPolicyList contains a list of OAuth2ClientPolicy
+type OAuth2ClientPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OAuth2ClientPolicy `json:"items"`
+}
+
+// GetEnforcementAction returns what happens to clients violating the policy
+func (p *OAuth2ClientPolicy) GetEnforcementAction() PolicyEnforcementAction {
+	if p.Spec.EnforcementAction != "" {
+		return p.Spec.EnforcementAction
+	}
+	return PolicyEnforcementDeny
+}
+
+// Validate checks the namespace selector and the scope patterns of the policy
+func (p *OAuth2ClientPolicy) Validate() error {
+	if _, err := metav1.LabelSelectorAsSelector(p.Spec.NamespaceSelector); err != nil {
+		return fmt.Errorf("invalid namespace selector: %w", err)
+	}
+	_, err := p.scopePatterns()
+	return err
+}
+
+func (p *OAuth2ClientPolicy) scopePatterns() ([]*regexp.Regexp, error) {
+	patterns := make([]*regexp.Regexp, 0, len(p.Spec.AllowedScopePatterns))
+	for _, pattern := range p.Spec.AllowedScopePatterns {
+		compiled, err := regexp.Compile(fmt.Sprintf("^(?:%s)$", pattern))
+		if err != nil {
+			return nil, fmt.Errorf("invalid scope pattern %s: %w", pattern, err)
+		}
+		patterns = append(patterns, compiled)
+	}
+	return patterns, nil
+}
+
+// Violations returns how c violates the policy, nothing if c complies with
+// it. The policy must be valid.
+func (p *OAuth2ClientPolicy) Violations(c *OAuth2Client) []string {
+	var violations []string
+
+	if allowed := p.Spec.AllowedGrantTypes; len(allowed) > 0 {
+		for _, gt := range c.GetGrantTypes() {
+			if !containsGrantType(allowed, gt) {
+				violations = append(violations, fmt.Sprintf("grant type %s is not allowed", gt))
+			}
+		}
+	}
+
+	if patterns, _ := p.scopePatterns(); len(patterns) > 0 {
+		for _, scope := range strings.Fields(c.GetScope()) {
+			if !matchesAny(patterns, scope) {
+				violations = append(violations, fmt.Sprintf("scope %s is not allowed", scope))
+			}
+		}
+	}
+
+	if domains := p.Spec.AllowedRedirectURIDomains; len(domains) > 0 {
+		for _, uris := range [][]RedirectURI{c.Spec.RedirectURIs, c.Spec.PostLogoutRedirectURIs} {
+			for _, uri := range uris {
+				if !redirectURIInDomains(string(uri), domains) {
+					violations = append(violations, fmt.Sprintf("redirect URI %s is not in an allowed domain", uri))
+				}
+			}
+		}
+	}
+
+	return violations
+}
+
+func containsGrantType(grantTypes []GrantType, gt GrantType) bool {
+	for _, elem := range grantTypes {
+		if elem == gt {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAny(patterns []*regexp.Regexp, s string) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// redirectURIInDomains reports whether the host of uri is one of domains, or
+// a subdomain of a `*.` domain
+func redirectURIInDomains(uri string, domains []string) bool {
+	u, err := url.Parse(uri)
+	if err != nil || u.Hostname() == "" {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+
+	for _, domain := range domains {
+		domain = strings.ToLower(domain)
+		if strings.HasPrefix(domain, "*.") {
+			if strings.HasSuffix(host, domain[1:]) {
+				return true
+			}
+			continue
+		}
+		if host == domain {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	SchemeBuilder.Register(&OAuth2ClientPolicy{}, &OAuth2ClientPolicyList{})
+}
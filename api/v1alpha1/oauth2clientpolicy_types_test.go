@@ -0,0 +1,90 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestOAuth2ClientPolicyViolations(t *testing.T) {
+
+	policy := &OAuth2ClientPolicy{Spec: OAuth2ClientPolicySpec{
+		AllowedGrantTypes:         []GrantType{"authorization_code", "refresh_token"},
+		AllowedScopePatterns:      []string{"openid|offline", `orders\.(read|write)`},
+		AllowedRedirectURIDomains: []string{"app.example.com", "*.apps.example.com"},
+	}}
+
+	for name, tc := range map[string]struct {
+		spec       OAuth2ClientSpec
+		violations []string
+	}{
+		"compliant": {OAuth2ClientSpec{
+			GrantTypes:             []GrantType{"authorization_code", "refresh_token"},
+			Scope:                  "openid orders.read",
+			RedirectURIs:           []RedirectURI{"https://app.example.com/callback", "https://team.apps.example.com/callback"},
+			PostLogoutRedirectURIs: []RedirectURI{"https://APP.example.com/"},
+		}, nil},
+		"disallowed grant type": {OAuth2ClientSpec{
+			GrantTypes: []GrantType{"client_credentials"},
+		}, []string{"grant type client_credentials is not allowed"}},
+		"disallowed scope": {OAuth2ClientSpec{
+			GrantTypes: []GrantType{"authorization_code"},
+			ScopeArray: []string{"openid", "orders.delete"},
+		}, []string{"scope orders.delete is not allowed"}},
+		"disallowed redirect URIs": {OAuth2ClientSpec{
+			GrantTypes:   []GrantType{"authorization_code"},
+			RedirectURIs: []RedirectURI{"https://apps.example.com/callback", "https://evil.com/app.example.com", "com.example.app:/callback"},
+		}, []string{
+			"redirect URI https://apps.example.com/callback is not in an allowed domain",
+			"redirect URI https://evil.com/app.example.com is not in an allowed domain",
+			"redirect URI com.example.app:/callback is not in an allowed domain",
+		}},
+	} {
+		t.Run("case="+name, func(t *testing.T) {
+			assert.Equal(t, tc.violations, policy.Violations(&OAuth2Client{Spec: tc.spec}))
+		})
+	}
+
+	t.Run("case=no constraints", func(t *testing.T) {
+		c := &OAuth2Client{Spec: OAuth2ClientSpec{GrantTypes: []GrantType{"implicit"}, Scope: "anything", RedirectURIs: []RedirectURI{"https://evil.com"}}}
+		assert.Empty(t, (&OAuth2ClientPolicy{}).Violations(c))
+	})
+}
+
+func TestValidateOAuth2ClientPolicy(t *testing.T) {
+
+	for name, tc := range map[string]struct {
+		spec  OAuth2ClientPolicySpec
+		valid bool
+	}{
+		"empty":                  {OAuth2ClientPolicySpec{}, true},
+		"valid":                  {OAuth2ClientPolicySpec{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}}, AllowedScopePatterns: []string{"openid"}}, true},
+		"invalid scope pattern":  {OAuth2ClientPolicySpec{AllowedScopePatterns: []string{"orders.("}}, false},
+		"invalid label selector": {OAuth2ClientPolicySpec{NamespaceSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "team", Operator: "Has"}}}}, false},
+	} {
+		t.Run("case="+name, func(t *testing.T) {
+			p := &OAuth2ClientPolicy{Spec: tc.spec}
+			if tc.valid {
+				assert.NoError(t, p.Validate())
+			} else {
+				assert.Error(t, p.Validate())
+			}
+		})
+	}
+}
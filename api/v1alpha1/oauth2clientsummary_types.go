@@ -0,0 +1,75 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OAuth2ClientSummaryName is the name of the OAuth2ClientSummary maintained
+// by the controller in each namespace with OAuth2Clients
+const OAuth2ClientSummaryName = "oauth2clients"
+
+// OAuth2ClientSummaryStatus aggregates the status of the OAuth2Clients in a namespace
+type OAuth2ClientSummaryStatus struct {
+	// Ready is the number of clients registered in ORY Hydra in their latest generation
+	Ready int32 `json:"ready"`
+	// Failed is the number of clients whose last reconciliation failed
+	Failed int32 `json:"failed"`
+	// Pending is the number of clients which have not been reconciled in their
+	// latest generation yet
+	Pending int32 `json:"pending"`
+
+	// Errors holds samples of the reconciliation errors of failed clients
+	Errors []OAuth2ClientErrorSample `json:"errors,omitempty"`
+}
+
+// OAuth2ClientErrorSample is the reconciliation error of a failed client
+type OAuth2ClientErrorSample struct {
+	// Name is the name of the failed client
+	Name string `json:"name"`
+	// ReconciliationError is the error its last reconciliation failed with
+	ReconciliationError ReconciliationError `json:"reconciliationError"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:categories=ory
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=integer,JSONPath=`.status.ready`
+// +kubebuilder:printcolumn:name="Failed",type=integer,JSONPath=`.status.failed`
+// +kubebuilder:printcolumn:name="Pending",type=integer,JSONPath=`.status.pending`
+
+// OAuth2ClientSummary is the Schema for the oauth2clientsummaries API. It is
+// maintained by the controller and summarizes the OAuth2Clients in its namespace.
+type OAuth2ClientSummary struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status OAuth2ClientSummaryStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OAuth2ClientSummaryList contains a list of OAuth2ClientSummary
+type OAuth2ClientSummaryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OAuth2ClientSummary `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OAuth2ClientSummary{}, &OAuth2ClientSummaryList{})
+}
@@ -0,0 +1,147 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OAuth2ClientTemplateSpec holds the defaults of the OAuth2Clients using a
+// template. Settings of an OAuth2Client take precedence over the template.
+type OAuth2ClientTemplateSpec struct {
+	// +kubebuilder:validation:MaxItems=6
+	//
+	// GrantTypes are the grant types of clients without grant types
+	GrantTypes []GrantType `json:"grantTypes,omitempty"`
+
+	// +kubebuilder:validation:MaxItems=3
+	//
+	// ResponseTypes are the response types of clients without response types
+	ResponseTypes []ResponseType `json:"responseTypes,omitempty"`
+
+	// ScopeArray are the scope values of clients without scope
+	ScopeArray []string `json:"scopeArray,omitempty"`
+
+	// Audience is the audience of clients without audience
+	Audience []string `json:"audience,omitempty"`
+
+	// +kubebuilder:validation:Enum=client_secret_basic;client_secret_post;private_key_jwt;none
+	//
+	// TokenEndpointAuthMethod is the token endpoint authentication method of
+	// clients without one
+	TokenEndpointAuthMethod TokenEndpointAuthMethod `json:"tokenEndpointAuthMethod,omitempty"`
+
+	// SecretTTL is the lifetime of the client secret of clients without one
+	SecretTTL *metav1.Duration `json:"secretTTL,omitempty"`
+
+	// Metadata holds properties added to the metadata of the clients, unless
+	// their metadata sets them
+	Metadata json.RawMessage `json:"metadata,omitempty"`
+
+	// Extra holds ORY Hydra client properties added to the clients unless
+	// their Extra sets them, e.g. token lifespans like
+	// `authorization_code_grant_access_token_lifespan`
+	Extra map[string]apiextensionsv1beta1.JSON `json:"extra,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=oact,categories=ory
+
+// OAuth2ClientTemplate is the Schema for the oauth2clienttemplates API. It
+// holds defaults shared by the OAuth2Clients of a namespace referencing it.
+type OAuth2ClientTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec OAuth2ClientTemplateSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OAuth2ClientTemplateList contains a list of OAuth2ClientTemplate
+type OAuth2ClientTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OAuth2ClientTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OAuth2ClientTemplate{}, &OAuth2ClientTemplateList{})
+}
+
+// ApplyTemplate fills the settings of c which are not set with the
+// defaults of t
+func (c *OAuth2Client) ApplyTemplate(t *OAuth2ClientTemplate) error {
+	defaults := t.Spec.DeepCopy()
+
+	if len(c.Spec.GrantTypes) == 0 {
+		c.Spec.GrantTypes = defaults.GrantTypes
+	}
+	if len(c.Spec.ResponseTypes) == 0 {
+		c.Spec.ResponseTypes = defaults.ResponseTypes
+	}
+	if c.GetScope() == "" {
+		c.Spec.ScopeArray = defaults.ScopeArray
+	}
+	if len(c.Spec.Audience) == 0 {
+		c.Spec.Audience = defaults.Audience
+	}
+	if c.Spec.TokenEndpointAuthMethod == "" {
+		c.Spec.TokenEndpointAuthMethod = defaults.TokenEndpointAuthMethod
+	}
+	if c.Spec.SecretTTL == nil {
+		c.Spec.SecretTTL = defaults.SecretTTL
+	}
+
+	for name, value := range defaults.Extra {
+		if _, ok := c.Spec.Extra[name]; !ok {
+			if c.Spec.Extra == nil {
+				c.Spec.Extra = map[string]apiextensionsv1beta1.JSON{}
+			}
+			c.Spec.Extra[name] = value
+		}
+	}
+
+	if len(defaults.Metadata) == 0 {
+		return nil
+	}
+	if len(c.Spec.Metadata) == 0 {
+		c.Spec.Metadata = defaults.Metadata
+		return nil
+	}
+	var metadata, templateMetadata map[string]json.RawMessage
+	if err := json.Unmarshal(c.Spec.Metadata, &metadata); err != nil {
+		// metadata which isn't an object can't be merged and wins
+		return nil
+	}
+	if err := json.Unmarshal(defaults.Metadata, &templateMetadata); err != nil {
+		return err
+	}
+	for name, value := range templateMetadata {
+		if _, ok := metadata[name]; !ok {
+			metadata[name] = value
+		}
+	}
+	merged, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+	c.Spec.Metadata = merged
+	return nil
+}
@@ -0,0 +1,84 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestApplyTemplate(t *testing.T) {
+
+	template := OAuth2ClientTemplate{
+		Spec: OAuth2ClientTemplateSpec{
+			GrantTypes:              []GrantType{"client_credentials"},
+			ScopeArray:              []string{"read"},
+			Audience:                []string{"https://api.example.com"},
+			TokenEndpointAuthMethod: "client_secret_post",
+			SecretTTL:               &metav1.Duration{Duration: time.Hour},
+			Metadata:                []byte(`{"team":"platform","tier":"gold"}`),
+			Extra: map[string]apiextensionsv1beta1.JSON{
+				"client_credentials_grant_access_token_lifespan": {Raw: []byte(`"15m0s"`)},
+			},
+		},
+	}
+
+	t.Run("case=unset settings are taken from the template", func(t *testing.T) {
+		c := OAuth2Client{Spec: OAuth2ClientSpec{SecretName: "foo"}}
+		require.NoError(t, c.ApplyTemplate(&template))
+
+		assert.Equal(t, []GrantType{"client_credentials"}, c.Spec.GrantTypes)
+		assert.Equal(t, "read", c.GetScope())
+		assert.Equal(t, []string{"https://api.example.com"}, c.Spec.Audience)
+		assert.Equal(t, TokenEndpointAuthMethod("client_secret_post"), c.Spec.TokenEndpointAuthMethod)
+		assert.Equal(t, time.Hour, c.Spec.SecretTTL.Duration)
+		assert.JSONEq(t, `{"team":"platform","tier":"gold"}`, string(c.Spec.Metadata))
+		assert.Equal(t, `"15m0s"`, string(c.Spec.Extra["client_credentials_grant_access_token_lifespan"].Raw))
+	})
+
+	t.Run("case=settings of the client take precedence", func(t *testing.T) {
+		c := OAuth2Client{Spec: OAuth2ClientSpec{
+			GrantTypes:              []GrantType{"authorization_code"},
+			Scope:                   "write",
+			TokenEndpointAuthMethod: "none",
+			Metadata:                []byte(`{"tier":"silver"}`),
+			Extra: map[string]apiextensionsv1beta1.JSON{
+				"client_credentials_grant_access_token_lifespan": {Raw: []byte(`"1h0m0s"`)},
+			},
+		}}
+		require.NoError(t, c.ApplyTemplate(&template))
+
+		assert.Equal(t, []GrantType{"authorization_code"}, c.Spec.GrantTypes)
+		assert.Equal(t, "write", c.GetScope())
+		assert.Empty(t, c.Spec.ScopeArray)
+		assert.Equal(t, TokenEndpointAuthMethod("none"), c.Spec.TokenEndpointAuthMethod)
+		assert.JSONEq(t, `{"team":"platform","tier":"silver"}`, string(c.Spec.Metadata))
+		assert.Equal(t, `"1h0m0s"`, string(c.Spec.Extra["client_credentials_grant_access_token_lifespan"].Raw))
+	})
+
+	t.Run("case=the template is not modified", func(t *testing.T) {
+		c := OAuth2Client{}
+		require.NoError(t, c.ApplyTemplate(&template))
+		c.Spec.GrantTypes[0] = "implicit"
+
+		assert.Equal(t, GrantType("client_credentials"), template.Spec.GrantTypes[0])
+	})
+}
@@ -0,0 +1,148 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TrustedJwtGrantIssuerSpec defines the desired state of TrustedJwtGrantIssuer
+type TrustedJwtGrantIssuerSpec struct {
+	// +kubebuilder:validation:MinLength=1
+	//
+	// Issuer is the issuer of the JWTs, matched against their `iss` claim
+	Issuer string `json:"issuer"`
+
+	// Subject is the subject the issuer may issue JWTs for, matched against
+	// their `sub` claim. Either subject or allowAnySubject must be set.
+	Subject string `json:"subject,omitempty"`
+
+	// AllowAnySubject allows the issuer to issue JWTs for any subject
+	AllowAnySubject bool `json:"allowAnySubject,omitempty"`
+
+	// Scope are the scopes the issuer may request
+	Scope []string `json:"scope,omitempty"`
+
+	// ExpiresAt is the time the trust relationship expires at
+	ExpiresAt metav1.Time `json:"expiresAt"`
+
+	// JSONWebKey is the public key the JWTs of the issuer are signed with
+	JSONWebKey TrustedJSONWebKey `json:"jwk"`
+}
+
+// TrustedJSONWebKey is the public JSON web key of a trusted issuer. Only the
+// parameters of RSA, EC and OKP public keys are supported.
+type TrustedJSONWebKey struct {
+	// +kubebuilder:validation:Enum=RSA;EC;OKP
+	//
+	// KeyType is the family of the key
+	KeyType string `json:"kty"`
+
+	// +kubebuilder:validation:MinLength=1
+	//
+	// KeyID is the ID of the key, matched against the `kid` header of the
+	// JWTs
+	KeyID string `json:"kid"`
+
+	// Algorithm is the algorithm the key is used with
+	Algorithm string `json:"alg,omitempty"`
+
+	// Use is the intended use of the key
+	Use JSONWebKeyUse `json:"use,omitempty"`
+
+	// N is the modulus of an RSA key
+	N string `json:"n,omitempty"`
+
+	// E is the exponent of an RSA key
+	E string `json:"e,omitempty"`
+
+	// Curve is the curve of an EC or OKP key
+	Curve string `json:"crv,omitempty"`
+
+	// X is the x coordinate of an EC key, or the public key of an OKP key
+	X string `json:"x,omitempty"`
+
+	// Y is the y coordinate of an EC key
+	Y string `json:"y,omitempty"`
+}
+
+// TrustedJwtGrantIssuerStatus defines the observed state of TrustedJwtGrantIssuer
+type TrustedJwtGrantIssuerStatus struct {
+	// ObservedGeneration is the last generation which has been applied to
+	// ORY Hydra
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// ID is the ID of the trust relationship in ORY Hydra
+	ID string `json:"id,omitempty"`
+	// ReconciliationError is the error the last reconciliation failed with
+	ReconciliationError ReconciliationError `json:"reconciliationError,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories=ory
+// +kubebuilder:printcolumn:name="Issuer",type="string",JSONPath=".spec.issuer"
+// +kubebuilder:printcolumn:name="Subject",type="string",JSONPath=".spec.subject"
+// +kubebuilder:printcolumn:name="Expires",type="date",JSONPath=".spec.expiresAt"
+
+// TrustedJwtGrantIssuer is the Schema for the trustedjwtgrantissuers API. It
+// describes an issuer whose JWTs ORY Hydra accepts as authorization grants.
+type TrustedJwtGrantIssuer struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TrustedJwtGrantIssuerSpec   `json:"spec,omitempty"`
+	Status TrustedJwtGrantIssuerStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TrustedJwtGrantIssuerList contains a list of TrustedJwtGrantIssuer
+type TrustedJwtGrantIssuerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TrustedJwtGrantIssuer `json:"items"`
+}
+
+// Validate checks the constraints of the spec the schema can't express
+func (i *TrustedJwtGrantIssuer) Validate() error {
+	if i.Spec.Subject == "" && !i.Spec.AllowAnySubject {
+		return fmt.Errorf("either subject or allowAnySubject must be set")
+	}
+	if i.Spec.Subject != "" && i.Spec.AllowAnySubject {
+		return fmt.Errorf("subject and allowAnySubject are mutually exclusive")
+	}
+	switch k := i.Spec.JSONWebKey; k.KeyType {
+	case "RSA":
+		if k.N == "" || k.E == "" {
+			return fmt.Errorf("the RSA key must have n and e")
+		}
+	case "EC":
+		if k.Curve == "" || k.X == "" || k.Y == "" {
+			return fmt.Errorf("the EC key must have crv, x and y")
+		}
+	case "OKP":
+		if k.Curve == "" || k.X == "" {
+			return fmt.Errorf("the OKP key must have crv and x")
+		}
+	}
+	return nil
+}
+
+func init() {
+	SchemeBuilder.Register(&TrustedJwtGrantIssuer{}, &TrustedJwtGrantIssuerList{})
+}
@@ -0,0 +1,49 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateTrustedJwtGrantIssuer(t *testing.T) {
+
+	rsa := TrustedJSONWebKey{KeyType: "RSA", KeyID: "key-1", N: "n", E: "AQAB"}
+
+	for name, tc := range map[string]struct {
+		spec  TrustedJwtGrantIssuerSpec
+		valid bool
+	}{
+		"subject":                     {TrustedJwtGrantIssuerSpec{Subject: "alice", JSONWebKey: rsa}, true},
+		"any subject":                 {TrustedJwtGrantIssuerSpec{AllowAnySubject: true, JSONWebKey: rsa}, true},
+		"EC key":                      {TrustedJwtGrantIssuerSpec{Subject: "alice", JSONWebKey: TrustedJSONWebKey{KeyType: "EC", KeyID: "key-1", Curve: "P-256", X: "x", Y: "y"}}, true},
+		"no subject":                  {TrustedJwtGrantIssuerSpec{JSONWebKey: rsa}, false},
+		"subject and any subject":     {TrustedJwtGrantIssuerSpec{Subject: "alice", AllowAnySubject: true, JSONWebKey: rsa}, false},
+		"RSA key without exponent":    {TrustedJwtGrantIssuerSpec{Subject: "alice", JSONWebKey: TrustedJSONWebKey{KeyType: "RSA", KeyID: "key-1", N: "n"}}, false},
+		"EC key without y coordinate": {TrustedJwtGrantIssuerSpec{Subject: "alice", JSONWebKey: TrustedJSONWebKey{KeyType: "EC", KeyID: "key-1", Curve: "P-256", X: "x"}}, false},
+	} {
+		t.Run("case="+name, func(t *testing.T) {
+			i := &TrustedJwtGrantIssuer{Spec: tc.spec}
+			if tc.valid {
+				assert.NoError(t, i.Validate())
+			} else {
+				assert.Error(t, i.Validate())
+			}
+		})
+	}
+}
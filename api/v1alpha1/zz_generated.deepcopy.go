@@ -21,9 +21,154 @@ package v1alpha1
 
 import (
 	"encoding/json"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigurationDefaults) DeepCopyInto(out *ConfigurationDefaults) {
+	*out = *in
+	if in.RedirectURIAllowPattern != nil {
+		in, out := &in.RedirectURIAllowPattern, &out.RedirectURIAllowPattern
+		*out = new(string)
+		**out = **in
+	}
+	if in.JWTAudiences != nil {
+		in, out := &in.JWTAudiences, &out.JWTAudiences
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigurationDefaults.
+func (in *ConfigurationDefaults) DeepCopy() *ConfigurationDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigurationDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigurationGarbageCollection) DeepCopyInto(out *ConfigurationGarbageCollection) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigurationGarbageCollection.
+func (in *ConfigurationGarbageCollection) DeepCopy() *ConfigurationGarbageCollection {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigurationGarbageCollection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigurationPolicies) DeepCopyInto(out *ConfigurationPolicies) {
+	*out = *in
+	if in.StrictFields != nil {
+		in, out := &in.StrictFields, &out.StrictFields
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DriftDetectionInterval != nil {
+		in, out := &in.DriftDetectionInterval, &out.DriftDetectionInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigurationPolicies.
+func (in *ConfigurationPolicies) DeepCopy() *ConfigurationPolicies {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigurationPolicies)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigurationRotation) DeepCopyInto(out *ConfigurationRotation) {
+	*out = *in
+	if in.SecretTTL != nil {
+		in, out := &in.SecretTTL, &out.SecretTTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigurationRotation.
+func (in *ConfigurationRotation) DeepCopy() *ConfigurationRotation {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigurationRotation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConsentHints) DeepCopyInto(out *ConsentHints) {
+	*out = *in
+	if in.ScopeDescriptions != nil {
+		in, out := &in.ScopeDescriptions, &out.ScopeDescriptions
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsentHints.
+func (in *ConsentHints) DeepCopy() *ConsentHints {
+	if in == nil {
+		return nil
+	}
+	out := new(ConsentHints)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceAuthorization) DeepCopyInto(out *DeviceAuthorization) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceAuthorization.
+func (in *DeviceAuthorization) DeepCopy() *DeviceAuthorization {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceAuthorization)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HydraAdmin) DeepCopyInto(out *HydraAdmin) {
 	*out = *in
@@ -40,26 +185,748 @@ func (in *HydraAdmin) DeepCopy() *HydraAdmin {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OAuth2Client) DeepCopyInto(out *OAuth2Client) {
+func (in *HydraInstance) DeepCopyInto(out *HydraInstance) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HydraInstance.
+func (in *HydraInstance) DeepCopy() *HydraInstance {
+	if in == nil {
+		return nil
+	}
+	out := new(HydraInstance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HydraInstance) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HydraInstanceAuth) DeepCopyInto(out *HydraInstanceAuth) {
+	*out = *in
+	out.SecretRef = in.SecretRef
+	if in.Scopes != nil {
+		in, out := &in.Scopes, &out.Scopes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HydraInstanceAuth.
+func (in *HydraInstanceAuth) DeepCopy() *HydraInstanceAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(HydraInstanceAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HydraInstanceList) DeepCopyInto(out *HydraInstanceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HydraInstance, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HydraInstanceList.
+func (in *HydraInstanceList) DeepCopy() *HydraInstanceList {
+	if in == nil {
+		return nil
+	}
+	out := new(HydraInstanceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HydraInstanceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HydraInstanceSpec) DeepCopyInto(out *HydraInstanceSpec) {
+	*out = *in
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(HydraInstanceTLS)
+		**out = **in
+	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(HydraInstanceAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HydraInstanceSpec.
+func (in *HydraInstanceSpec) DeepCopy() *HydraInstanceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HydraInstanceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HydraInstanceTLS) DeepCopyInto(out *HydraInstanceTLS) {
+	*out = *in
+	out.SecretRef = in.SecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HydraInstanceTLS.
+func (in *HydraInstanceTLS) DeepCopy() *HydraInstanceTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(HydraInstanceTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HydraMaesterConfiguration) DeepCopyInto(out *HydraMaesterConfiguration) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HydraMaesterConfiguration.
+func (in *HydraMaesterConfiguration) DeepCopy() *HydraMaesterConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(HydraMaesterConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HydraMaesterConfiguration) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HydraMaesterConfigurationList) DeepCopyInto(out *HydraMaesterConfigurationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HydraMaesterConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HydraMaesterConfigurationList.
+func (in *HydraMaesterConfigurationList) DeepCopy() *HydraMaesterConfigurationList {
+	if in == nil {
+		return nil
+	}
+	out := new(HydraMaesterConfigurationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HydraMaesterConfigurationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HydraMaesterConfigurationSpec) DeepCopyInto(out *HydraMaesterConfigurationSpec) {
+	*out = *in
+	if in.Defaults != nil {
+		in, out := &in.Defaults, &out.Defaults
+		*out = new(ConfigurationDefaults)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Policies != nil {
+		in, out := &in.Policies, &out.Policies
+		*out = new(ConfigurationPolicies)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rotation != nil {
+		in, out := &in.Rotation, &out.Rotation
+		*out = new(ConfigurationRotation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GarbageCollection != nil {
+		in, out := &in.GarbageCollection, &out.GarbageCollection
+		*out = new(ConfigurationGarbageCollection)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HydraMaesterConfigurationSpec.
+func (in *HydraMaesterConfigurationSpec) DeepCopy() *HydraMaesterConfigurationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HydraMaesterConfigurationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HydraMaesterConfigurationStatus) DeepCopyInto(out *HydraMaesterConfigurationStatus) {
+	*out = *in
+	out.ReconciliationError = in.ReconciliationError
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HydraMaesterConfigurationStatus.
+func (in *HydraMaesterConfigurationStatus) DeepCopy() *HydraMaesterConfigurationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HydraMaesterConfigurationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JsonWebKeySet) DeepCopyInto(out *JsonWebKeySet) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JsonWebKeySet.
+func (in *JsonWebKeySet) DeepCopy() *JsonWebKeySet {
+	if in == nil {
+		return nil
+	}
+	out := new(JsonWebKeySet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *JsonWebKeySet) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JsonWebKeySetList) DeepCopyInto(out *JsonWebKeySetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]JsonWebKeySet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JsonWebKeySetList.
+func (in *JsonWebKeySetList) DeepCopy() *JsonWebKeySetList {
+	if in == nil {
+		return nil
+	}
+	out := new(JsonWebKeySetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *JsonWebKeySetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JsonWebKeySetSpec) DeepCopyInto(out *JsonWebKeySetSpec) {
+	*out = *in
+	if in.RotationInterval != nil {
+		in, out := &in.RotationInterval, &out.RotationInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JsonWebKeySetSpec.
+func (in *JsonWebKeySetSpec) DeepCopy() *JsonWebKeySetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(JsonWebKeySetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JsonWebKeySetStatus) DeepCopyInto(out *JsonWebKeySetStatus) {
+	*out = *in
+	if in.KeyIDs != nil {
+		in, out := &in.KeyIDs, &out.KeyIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RotatedAt != nil {
+		in, out := &in.RotatedAt, &out.RotatedAt
+		*out = (*in).DeepCopy()
+	}
+	out.ReconciliationError = in.ReconciliationError
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JsonWebKeySetStatus.
+func (in *JsonWebKeySetStatus) DeepCopy() *JsonWebKeySetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(JsonWebKeySetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OAuth2Client) DeepCopyInto(out *OAuth2Client) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OAuth2Client.
+func (in *OAuth2Client) DeepCopy() *OAuth2Client {
+	if in == nil {
+		return nil
+	}
+	out := new(OAuth2Client)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OAuth2Client) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OAuth2ClientCondition) DeepCopyInto(out *OAuth2ClientCondition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OAuth2ClientCondition.
+func (in *OAuth2ClientCondition) DeepCopy() *OAuth2ClientCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(OAuth2ClientCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OAuth2ClientErrorSample) DeepCopyInto(out *OAuth2ClientErrorSample) {
+	*out = *in
+	out.ReconciliationError = in.ReconciliationError
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OAuth2ClientErrorSample.
+func (in *OAuth2ClientErrorSample) DeepCopy() *OAuth2ClientErrorSample {
+	if in == nil {
+		return nil
+	}
+	out := new(OAuth2ClientErrorSample)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OAuth2ClientList) DeepCopyInto(out *OAuth2ClientList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OAuth2Client, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OAuth2ClientList.
+func (in *OAuth2ClientList) DeepCopy() *OAuth2ClientList {
+	if in == nil {
+		return nil
+	}
+	out := new(OAuth2ClientList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OAuth2ClientList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OAuth2ClientPolicy) DeepCopyInto(out *OAuth2ClientPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OAuth2ClientPolicy.
+func (in *OAuth2ClientPolicy) DeepCopy() *OAuth2ClientPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(OAuth2ClientPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OAuth2ClientPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OAuth2ClientPolicyList) DeepCopyInto(out *OAuth2ClientPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OAuth2ClientPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OAuth2ClientPolicyList.
+func (in *OAuth2ClientPolicyList) DeepCopy() *OAuth2ClientPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(OAuth2ClientPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OAuth2ClientPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OAuth2ClientPolicySpec) DeepCopyInto(out *OAuth2ClientPolicySpec) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AllowedGrantTypes != nil {
+		in, out := &in.AllowedGrantTypes, &out.AllowedGrantTypes
+		*out = make([]GrantType, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedScopePatterns != nil {
+		in, out := &in.AllowedScopePatterns, &out.AllowedScopePatterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedRedirectURIDomains != nil {
+		in, out := &in.AllowedRedirectURIDomains, &out.AllowedRedirectURIDomains
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OAuth2ClientPolicySpec.
+func (in *OAuth2ClientPolicySpec) DeepCopy() *OAuth2ClientPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OAuth2ClientPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OAuth2ClientPolicyStatus) DeepCopyInto(out *OAuth2ClientPolicyStatus) {
+	*out = *in
+	out.ReconciliationError = in.ReconciliationError
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OAuth2ClientPolicyStatus.
+func (in *OAuth2ClientPolicyStatus) DeepCopy() *OAuth2ClientPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OAuth2ClientPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OAuth2ClientSpec) DeepCopyInto(out *OAuth2ClientSpec) {
+	*out = *in
+	if in.GrantTypes != nil {
+		in, out := &in.GrantTypes, &out.GrantTypes
+		*out = make([]GrantType, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResponseTypes != nil {
+		in, out := &in.ResponseTypes, &out.ResponseTypes
+		*out = make([]ResponseType, len(*in))
+		copy(*out, *in)
+	}
+	if in.RedirectURIs != nil {
+		in, out := &in.RedirectURIs, &out.RedirectURIs
+		*out = make([]RedirectURI, len(*in))
+		copy(*out, *in)
+	}
+	if in.PostLogoutRedirectURIs != nil {
+		in, out := &in.PostLogoutRedirectURIs, &out.PostLogoutRedirectURIs
+		*out = make([]RedirectURI, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedCorsOrigins != nil {
+		in, out := &in.AllowedCorsOrigins, &out.AllowedCorsOrigins
+		*out = make([]RedirectURI, len(*in))
+		copy(*out, *in)
+	}
+	if in.Audience != nil {
+		in, out := &in.Audience, &out.Audience
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ScopeArray != nil {
+		in, out := &in.ScopeArray, &out.ScopeArray
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.HydraAdmin = in.HydraAdmin
+	if in.HydraInstanceRef != nil {
+		in, out := &in.HydraInstanceRef, &out.HydraInstanceRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = make(json.RawMessage, len(*in))
+		copy(*out, *in)
+	}
+	if in.UnmanagedFields != nil {
+		in, out := &in.UnmanagedFields, &out.UnmanagedFields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SecretTTL != nil {
+		in, out := &in.SecretTTL, &out.SecretTTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.RotationPolicy != nil {
+		in, out := &in.RotationPolicy, &out.RotationPolicy
+		*out = new(RotationPolicy)
+		**out = **in
+	}
+	if in.ExpiresAfter != nil {
+		in, out := &in.ExpiresAfter, &out.ExpiresAfter
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.NotAfter != nil {
+		in, out := &in.NotAfter, &out.NotAfter
+		*out = (*in).DeepCopy()
+	}
+	if in.ConsentHints != nil {
+		in, out := &in.ConsentHints, &out.ConsentHints
+		*out = new(ConsentHints)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Extra != nil {
+		in, out := &in.Extra, &out.Extra
+		*out = make(map[string]v1beta1.JSON, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.SecretProjection != nil {
+		in, out := &in.SecretProjection, &out.SecretProjection
+		*out = new(SecretProjection)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretReplicationNamespaces != nil {
+		in, out := &in.SecretReplicationNamespaces, &out.SecretReplicationNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SecretTargets != nil {
+		in, out := &in.SecretTargets, &out.SecretTargets
+		*out = make([]SecretTarget, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExistingSecretRef != nil {
+		in, out := &in.ExistingSecretRef, &out.ExistingSecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.SecretLabels != nil {
+		in, out := &in.SecretLabels, &out.SecretLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SecretAnnotations != nil {
+		in, out := &in.SecretAnnotations, &out.SecretAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SecretPush != nil {
+		in, out := &in.SecretPush, &out.SecretPush
+		*out = new(SecretPush)
+		**out = **in
+	}
+	if in.TemplateRef != nil {
+		in, out := &in.TemplateRef, &out.TemplateRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OAuth2ClientSpec.
+func (in *OAuth2ClientSpec) DeepCopy() *OAuth2ClientSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OAuth2ClientSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OAuth2ClientStatus) DeepCopyInto(out *OAuth2ClientStatus) {
+	*out = *in
+	out.ReconciliationError = in.ReconciliationError
+	if in.DeviceAuthorization != nil {
+		in, out := &in.DeviceAuthorization, &out.DeviceAuthorization
+		*out = new(DeviceAuthorization)
+		**out = **in
+	}
+	if in.ClientSecretExpiresAt != nil {
+		in, out := &in.ClientSecretExpiresAt, &out.ClientSecretExpiresAt
+		*out = (*in).DeepCopy()
+	}
+	if in.ClientSecretIssuedAt != nil {
+		in, out := &in.ClientSecretIssuedAt, &out.ClientSecretIssuedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.PreviousClientSecretExpiresAt != nil {
+		in, out := &in.PreviousClientSecretExpiresAt, &out.PreviousClientSecretExpiresAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]OAuth2ClientCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OAuth2ClientStatus.
+func (in *OAuth2ClientStatus) DeepCopy() *OAuth2ClientStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OAuth2ClientStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OAuth2ClientTemplate) DeepCopyInto(out *OAuth2ClientTemplate) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OAuth2Client.
-func (in *OAuth2Client) DeepCopy() *OAuth2Client {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OAuth2ClientTemplate.
+func (in *OAuth2ClientTemplate) DeepCopy() *OAuth2ClientTemplate {
 	if in == nil {
 		return nil
 	}
-	out := new(OAuth2Client)
+	out := new(OAuth2ClientTemplate)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OAuth2Client) DeepCopyObject() runtime.Object {
+func (in *OAuth2ClientTemplate) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -67,31 +934,31 @@ func (in *OAuth2Client) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OAuth2ClientList) DeepCopyInto(out *OAuth2ClientList) {
+func (in *OAuth2ClientTemplateList) DeepCopyInto(out *OAuth2ClientTemplateList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	out.ListMeta = in.ListMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]OAuth2Client, len(*in))
+		*out = make([]OAuth2ClientTemplate, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OAuth2ClientList.
-func (in *OAuth2ClientList) DeepCopy() *OAuth2ClientList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OAuth2ClientTemplateList.
+func (in *OAuth2ClientTemplateList) DeepCopy() *OAuth2ClientTemplateList {
 	if in == nil {
 		return nil
 	}
-	out := new(OAuth2ClientList)
+	out := new(OAuth2ClientTemplateList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OAuth2ClientList) DeepCopyObject() runtime.Object {
+func (in *OAuth2ClientTemplateList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -99,7 +966,7 @@ func (in *OAuth2ClientList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OAuth2ClientSpec) DeepCopyInto(out *OAuth2ClientSpec) {
+func (in *OAuth2ClientTemplateSpec) DeepCopyInto(out *OAuth2ClientTemplateSpec) {
 	*out = *in
 	if in.GrantTypes != nil {
 		in, out := &in.GrantTypes, &out.GrantTypes
@@ -111,19 +978,9 @@ func (in *OAuth2ClientSpec) DeepCopyInto(out *OAuth2ClientSpec) {
 		*out = make([]ResponseType, len(*in))
 		copy(*out, *in)
 	}
-	if in.RedirectURIs != nil {
-		in, out := &in.RedirectURIs, &out.RedirectURIs
-		*out = make([]RedirectURI, len(*in))
-		copy(*out, *in)
-	}
-	if in.PostLogoutRedirectURIs != nil {
-		in, out := &in.PostLogoutRedirectURIs, &out.PostLogoutRedirectURIs
-		*out = make([]RedirectURI, len(*in))
-		copy(*out, *in)
-	}
-	if in.AllowedCorsOrigins != nil {
-		in, out := &in.AllowedCorsOrigins, &out.AllowedCorsOrigins
-		*out = make([]RedirectURI, len(*in))
+	if in.ScopeArray != nil {
+		in, out := &in.ScopeArray, &out.ScopeArray
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
 	if in.Audience != nil {
@@ -131,36 +988,109 @@ func (in *OAuth2ClientSpec) DeepCopyInto(out *OAuth2ClientSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	out.HydraAdmin = in.HydraAdmin
+	if in.SecretTTL != nil {
+		in, out := &in.SecretTTL, &out.SecretTTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
 	if in.Metadata != nil {
 		in, out := &in.Metadata, &out.Metadata
 		*out = make(json.RawMessage, len(*in))
 		copy(*out, *in)
 	}
+	if in.Extra != nil {
+		in, out := &in.Extra, &out.Extra
+		*out = make(map[string]v1beta1.JSON, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OAuth2ClientSpec.
-func (in *OAuth2ClientSpec) DeepCopy() *OAuth2ClientSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OAuth2ClientTemplateSpec.
+func (in *OAuth2ClientTemplateSpec) DeepCopy() *OAuth2ClientTemplateSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(OAuth2ClientSpec)
+	out := new(OAuth2ClientTemplateSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OAuth2ClientStatus) DeepCopyInto(out *OAuth2ClientStatus) {
+func (in *OAuth2ClientSummary) DeepCopyInto(out *OAuth2ClientSummary) {
 	*out = *in
-	out.ReconciliationError = in.ReconciliationError
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OAuth2ClientStatus.
-func (in *OAuth2ClientStatus) DeepCopy() *OAuth2ClientStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OAuth2ClientSummary.
+func (in *OAuth2ClientSummary) DeepCopy() *OAuth2ClientSummary {
 	if in == nil {
 		return nil
 	}
-	out := new(OAuth2ClientStatus)
+	out := new(OAuth2ClientSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OAuth2ClientSummary) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OAuth2ClientSummaryList) DeepCopyInto(out *OAuth2ClientSummaryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OAuth2ClientSummary, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OAuth2ClientSummaryList.
+func (in *OAuth2ClientSummaryList) DeepCopy() *OAuth2ClientSummaryList {
+	if in == nil {
+		return nil
+	}
+	out := new(OAuth2ClientSummaryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OAuth2ClientSummaryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OAuth2ClientSummaryStatus) DeepCopyInto(out *OAuth2ClientSummaryStatus) {
+	*out = *in
+	if in.Errors != nil {
+		in, out := &in.Errors, &out.Errors
+		*out = make([]OAuth2ClientErrorSample, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OAuth2ClientSummaryStatus.
+func (in *OAuth2ClientSummaryStatus) DeepCopy() *OAuth2ClientSummaryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OAuth2ClientSummaryStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -179,3 +1109,233 @@ func (in *ReconciliationError) DeepCopy() *ReconciliationError {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RotationPolicy) DeepCopyInto(out *RotationPolicy) {
+	*out = *in
+	out.MaxAge = in.MaxAge
+	out.Overlap = in.Overlap
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RotationPolicy.
+func (in *RotationPolicy) DeepCopy() *RotationPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RotationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretTemplate) DeepCopyInto(out *SecretTemplate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretTemplate.
+func (in *SecretTemplate) DeepCopy() *SecretTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretTarget) DeepCopyInto(out *SecretTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretTarget.
+func (in *SecretTarget) DeepCopy() *SecretTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretPush) DeepCopyInto(out *SecretPush) {
+	*out = *in
+	out.SecretStoreRef = in.SecretStoreRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretPush.
+func (in *SecretPush) DeepCopy() *SecretPush {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretPush)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretStoreRef) DeepCopyInto(out *SecretStoreRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStoreRef.
+func (in *SecretStoreRef) DeepCopy() *SecretStoreRef {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretStoreRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretProjection) DeepCopyInto(out *SecretProjection) {
+	*out = *in
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SecretProjectionItem, len(*in))
+		copy(*out, *in)
+	}
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = make([]SecretTemplate, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretProjection.
+func (in *SecretProjection) DeepCopy() *SecretProjection {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretProjection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretProjectionItem) DeepCopyInto(out *SecretProjectionItem) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretProjectionItem.
+func (in *SecretProjectionItem) DeepCopy() *SecretProjectionItem {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretProjectionItem)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrustedJSONWebKey) DeepCopyInto(out *TrustedJSONWebKey) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrustedJSONWebKey.
+func (in *TrustedJSONWebKey) DeepCopy() *TrustedJSONWebKey {
+	if in == nil {
+		return nil
+	}
+	out := new(TrustedJSONWebKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrustedJwtGrantIssuer) DeepCopyInto(out *TrustedJwtGrantIssuer) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrustedJwtGrantIssuer.
+func (in *TrustedJwtGrantIssuer) DeepCopy() *TrustedJwtGrantIssuer {
+	if in == nil {
+		return nil
+	}
+	out := new(TrustedJwtGrantIssuer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TrustedJwtGrantIssuer) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrustedJwtGrantIssuerList) DeepCopyInto(out *TrustedJwtGrantIssuerList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TrustedJwtGrantIssuer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrustedJwtGrantIssuerList.
+func (in *TrustedJwtGrantIssuerList) DeepCopy() *TrustedJwtGrantIssuerList {
+	if in == nil {
+		return nil
+	}
+	out := new(TrustedJwtGrantIssuerList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TrustedJwtGrantIssuerList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrustedJwtGrantIssuerSpec) DeepCopyInto(out *TrustedJwtGrantIssuerSpec) {
+	*out = *in
+	if in.Scope != nil {
+		in, out := &in.Scope, &out.Scope
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.ExpiresAt.DeepCopyInto(&out.ExpiresAt)
+	out.JSONWebKey = in.JSONWebKey
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrustedJwtGrantIssuerSpec.
+func (in *TrustedJwtGrantIssuerSpec) DeepCopy() *TrustedJwtGrantIssuerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TrustedJwtGrantIssuerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrustedJwtGrantIssuerStatus) DeepCopyInto(out *TrustedJwtGrantIssuerStatus) {
+	*out = *in
+	out.ReconciliationError = in.ReconciliationError
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrustedJwtGrantIssuerStatus.
+func (in *TrustedJwtGrantIssuerStatus) DeepCopy() *TrustedJwtGrantIssuerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TrustedJwtGrantIssuerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,359 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/ory/hydra-maester/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// ScopeAnnotation holds the `scope` string of an OAuth2Client converted from
+// v1alpha1, so that it is restored instead of `scopeArray` when the
+// OAuth2Client is converted back with unchanged scopes
+const ScopeAnnotation = "hydra-maester.ory.sh/v1alpha1-scope"
+
+var _ conversion.Convertible = &OAuth2Client{}
+
+// ConvertTo converts c to the v1alpha1 hub version
+func (c *OAuth2Client) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.OAuth2Client)
+
+	dst.ObjectMeta = *c.ObjectMeta.DeepCopy()
+	scope, fromScope := dst.Annotations[ScopeAnnotation]
+	delete(dst.Annotations, ScopeAnnotation)
+	if len(dst.Annotations) == 0 {
+		dst.Annotations = nil
+	}
+
+	in := c.Spec.DeepCopy()
+	dst.Spec = v1alpha1.OAuth2ClientSpec{
+		ClientID:                    in.ClientID,
+		ClientName:                  in.ClientName,
+		Archetype:                   v1alpha1.ClientArchetype(in.Archetype),
+		Profile:                     v1alpha1.ClientProfile(in.Profile),
+		GrantTypes:                  grantTypesTo(in.GrantTypes),
+		ResponseTypes:               responseTypesTo(in.ResponseTypes),
+		RedirectURIs:                redirectURIsTo(in.RedirectURIs),
+		PostLogoutRedirectURIs:      redirectURIsTo(in.PostLogoutRedirectURIs),
+		AllowedCorsOrigins:          redirectURIsTo(in.AllowedCorsOrigins),
+		Audience:                    in.Audience,
+		SecretName:                  in.Secret.Name,
+		HydraAdmin:                  v1alpha1.HydraAdmin(in.HydraAdmin),
+		HydraInstanceRef:            in.HydraInstanceRef,
+		TokenEndpointAuthMethod:     v1alpha1.TokenEndpointAuthMethod(in.TokenEndpointAuthMethod),
+		TokenEndpointAuthSigningAlg: in.TokenEndpointAuthSigningAlg,
+		Metadata:                    in.Metadata,
+		AccessTokenStrategy:         v1alpha1.AccessTokenStrategy(in.AccessTokenStrategy),
+		SkipConsent:                 in.SkipConsent,
+		SkipLogoutConsent:           in.SkipLogoutConsent,
+		UnmanagedFields:             in.UnmanagedFields,
+		GCExempt:                    in.GCExempt,
+		UpdateStrategy:              v1alpha1.UpdateStrategy(in.UpdateStrategy),
+		ConflictPolicy:              v1alpha1.ConflictPolicy(in.ConflictPolicy),
+		ExpiresAfter:                in.ExpiresAfter,
+		NotAfter:                    in.NotAfter,
+		ExpiryAction:                v1alpha1.ExpiryAction(in.ExpiryAction),
+		Suspend:                     in.Suspend,
+		DeletionPolicy:              v1alpha1.DeletionPolicy(in.DeletionPolicy),
+		SecretTTL:                   in.Secret.TTL,
+		RotationPolicy:              (*v1alpha1.RotationPolicy)(in.Secret.RotationPolicy),
+		SecretType:                  in.Secret.Type,
+		SecretConflictPolicy:        v1alpha1.SecretConflictPolicy(in.Secret.ConflictPolicy),
+		RevokeTokensOnRotation:      in.Secret.RevokeTokensOnRotation,
+		SecretReplicationNamespaces: in.Secret.ReplicationNamespaces,
+		SecretTargets:               secretTargetsTo(in.Secret.Targets),
+		ExistingSecretRef:           in.Secret.ExistingRef,
+		SecretLabels:                in.Secret.Labels,
+		SecretAnnotations:           in.Secret.Annotations,
+		Extra:                       in.Extra,
+		TemplateRef:                 in.TemplateRef,
+	}
+	if fromScope && reflect.DeepEqual(strings.Fields(scope), in.Scopes) {
+		dst.Spec.Scope = scope
+	} else {
+		dst.Spec.ScopeArray = in.Scopes
+	}
+	if in.ConsentHints != nil {
+		hints := v1alpha1.ConsentHints(*in.ConsentHints)
+		dst.Spec.ConsentHints = &hints
+	}
+	if p := in.Secret.Projection; p != nil {
+		dst.Spec.SecretProjection = &v1alpha1.SecretProjection{
+			Format:  v1alpha1.SecretProjectionFormat(p.Format),
+			JSONKey: p.JSONKey,
+		}
+		for _, item := range p.Items {
+			dst.Spec.SecretProjection.Items = append(dst.Spec.SecretProjection.Items, v1alpha1.SecretProjectionItem{
+				Property: v1alpha1.SecretProperty(item.Property),
+				Key:      item.Key,
+				Encoding: v1alpha1.SecretValueEncoding(item.Encoding),
+			})
+		}
+		for _, t := range p.Templates {
+			dst.Spec.SecretProjection.Templates = append(dst.Spec.SecretProjection.Templates, v1alpha1.SecretTemplate(t))
+		}
+	}
+	if p := in.Secret.Push; p != nil {
+		dst.Spec.SecretPush = &v1alpha1.SecretPush{
+			SecretStoreRef: v1alpha1.SecretStoreRef(p.SecretStoreRef),
+			RemoteKey:      p.RemoteKey,
+		}
+	}
+
+	status := c.Status.DeepCopy()
+	dst.Status = v1alpha1.OAuth2ClientStatus{
+		ObservedGeneration: status.ObservedGeneration,
+		ReconciliationError: v1alpha1.ReconciliationError{
+			Code:        v1alpha1.StatusCode(status.ReconciliationError.Code),
+			Description: status.ReconciliationError.Description,
+		},
+		ClientID:                      status.ClientID,
+		ClientSecretExpiresAt:         status.ClientSecretExpiresAt,
+		ClientSecretIssuedAt:          status.ClientSecretIssuedAt,
+		PreviousClientSecretExpiresAt: status.PreviousClientSecretExpiresAt,
+		HydraUpdatedAt:                status.HydraUpdatedAt,
+		ManualSecretVersion:           status.ManualSecretVersion,
+		RotationRequest:               status.RotationRequest,
+		SecretChecksum:                status.SecretChecksum,
+		TemplateGeneration:            status.TemplateGeneration,
+	}
+	if status.DeviceAuthorization != nil {
+		deviceAuthorization := v1alpha1.DeviceAuthorization(*status.DeviceAuthorization)
+		dst.Status.DeviceAuthorization = &deviceAuthorization
+	}
+	for _, condition := range status.Conditions {
+		dst.Status.Conditions = append(dst.Status.Conditions, v1alpha1.OAuth2ClientCondition{
+			Type:               v1alpha1.OAuth2ClientConditionType(condition.Type),
+			Status:             condition.Status,
+			LastTransitionTime: condition.LastTransitionTime,
+			Reason:             condition.Reason,
+			Message:            condition.Message,
+		})
+	}
+	return nil
+}
+
+// ConvertFrom converts the v1alpha1 hub version to c
+func (c *OAuth2Client) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.OAuth2Client)
+
+	c.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	if src.Spec.Scope != "" {
+		if c.Annotations == nil {
+			c.Annotations = map[string]string{}
+		}
+		c.Annotations[ScopeAnnotation] = src.Spec.Scope
+	}
+
+	in := src.Spec.DeepCopy()
+	c.Spec = OAuth2ClientSpec{
+		ClientID:               in.ClientID,
+		ClientName:             in.ClientName,
+		Archetype:              ClientArchetype(in.Archetype),
+		Profile:                ClientProfile(in.Profile),
+		GrantTypes:             grantTypesFrom(in.GrantTypes),
+		ResponseTypes:          responseTypesFrom(in.ResponseTypes),
+		RedirectURIs:           redirectURIsFrom(in.RedirectURIs),
+		PostLogoutRedirectURIs: redirectURIsFrom(in.PostLogoutRedirectURIs),
+		AllowedCorsOrigins:     redirectURIsFrom(in.AllowedCorsOrigins),
+		Audience:               in.Audience,
+		Scopes:                 in.ScopeArray,
+		Secret: ClientSecret{
+			Name:                   in.SecretName,
+			TTL:                    in.SecretTTL,
+			RotationPolicy:         (*RotationPolicy)(in.RotationPolicy),
+			Type:                   in.SecretType,
+			ConflictPolicy:         SecretConflictPolicy(in.SecretConflictPolicy),
+			RevokeTokensOnRotation: in.RevokeTokensOnRotation,
+			ReplicationNamespaces:  in.SecretReplicationNamespaces,
+			Targets:                secretTargetsFrom(in.SecretTargets),
+			ExistingRef:            in.ExistingSecretRef,
+			Labels:                 in.SecretLabels,
+			Annotations:            in.SecretAnnotations,
+		},
+		HydraAdmin:                  HydraAdmin(in.HydraAdmin),
+		HydraInstanceRef:            in.HydraInstanceRef,
+		TokenEndpointAuthMethod:     TokenEndpointAuthMethod(in.TokenEndpointAuthMethod),
+		TokenEndpointAuthSigningAlg: in.TokenEndpointAuthSigningAlg,
+		Metadata:                    in.Metadata,
+		AccessTokenStrategy:         AccessTokenStrategy(in.AccessTokenStrategy),
+		SkipConsent:                 in.SkipConsent,
+		SkipLogoutConsent:           in.SkipLogoutConsent,
+		UnmanagedFields:             in.UnmanagedFields,
+		GCExempt:                    in.GCExempt,
+		UpdateStrategy:              UpdateStrategy(in.UpdateStrategy),
+		ConflictPolicy:              ConflictPolicy(in.ConflictPolicy),
+		ExpiresAfter:                in.ExpiresAfter,
+		NotAfter:                    in.NotAfter,
+		ExpiryAction:                ExpiryAction(in.ExpiryAction),
+		Suspend:                     in.Suspend,
+		DeletionPolicy:              DeletionPolicy(in.DeletionPolicy),
+		Extra:                       in.Extra,
+		TemplateRef:                 in.TemplateRef,
+	}
+	if in.Scope != "" {
+		c.Spec.Scopes = strings.Fields(in.Scope)
+	}
+	if in.ConsentHints != nil {
+		hints := ConsentHints(*in.ConsentHints)
+		c.Spec.ConsentHints = &hints
+	}
+	if p := in.SecretProjection; p != nil {
+		c.Spec.Secret.Projection = &SecretProjection{
+			Format:  SecretProjectionFormat(p.Format),
+			JSONKey: p.JSONKey,
+		}
+		for _, item := range p.Items {
+			c.Spec.Secret.Projection.Items = append(c.Spec.Secret.Projection.Items, SecretProjectionItem{
+				Property: SecretProperty(item.Property),
+				Key:      item.Key,
+				Encoding: SecretValueEncoding(item.Encoding),
+			})
+		}
+		for _, t := range p.Templates {
+			c.Spec.Secret.Projection.Templates = append(c.Spec.Secret.Projection.Templates, SecretTemplate(t))
+		}
+	}
+	if p := in.SecretPush; p != nil {
+		c.Spec.Secret.Push = &SecretPush{
+			SecretStoreRef: SecretStoreRef(p.SecretStoreRef),
+			RemoteKey:      p.RemoteKey,
+		}
+	}
+
+	status := src.Status.DeepCopy()
+	c.Status = OAuth2ClientStatus{
+		ObservedGeneration: status.ObservedGeneration,
+		ReconciliationError: ReconciliationError{
+			Code:        StatusCode(status.ReconciliationError.Code),
+			Description: status.ReconciliationError.Description,
+		},
+		ClientID:                      status.ClientID,
+		ClientSecretExpiresAt:         status.ClientSecretExpiresAt,
+		ClientSecretIssuedAt:          status.ClientSecretIssuedAt,
+		PreviousClientSecretExpiresAt: status.PreviousClientSecretExpiresAt,
+		HydraUpdatedAt:                status.HydraUpdatedAt,
+		ManualSecretVersion:           status.ManualSecretVersion,
+		RotationRequest:               status.RotationRequest,
+		SecretChecksum:                status.SecretChecksum,
+		TemplateGeneration:            status.TemplateGeneration,
+	}
+	if status.DeviceAuthorization != nil {
+		deviceAuthorization := DeviceAuthorization(*status.DeviceAuthorization)
+		c.Status.DeviceAuthorization = &deviceAuthorization
+	}
+	for _, condition := range status.Conditions {
+		c.Status.Conditions = append(c.Status.Conditions, OAuth2ClientCondition{
+			Type:               OAuth2ClientConditionType(condition.Type),
+			Status:             condition.Status,
+			LastTransitionTime: condition.LastTransitionTime,
+			Reason:             condition.Reason,
+			Message:            condition.Message,
+		})
+	}
+	return nil
+}
+
+func grantTypesTo(in []GrantType) []v1alpha1.GrantType {
+	if in == nil {
+		return nil
+	}
+	out := make([]v1alpha1.GrantType, len(in))
+	for i, v := range in {
+		out[i] = v1alpha1.GrantType(v)
+	}
+	return out
+}
+
+func grantTypesFrom(in []v1alpha1.GrantType) []GrantType {
+	if in == nil {
+		return nil
+	}
+	out := make([]GrantType, len(in))
+	for i, v := range in {
+		out[i] = GrantType(v)
+	}
+	return out
+}
+
+func responseTypesTo(in []ResponseType) []v1alpha1.ResponseType {
+	if in == nil {
+		return nil
+	}
+	out := make([]v1alpha1.ResponseType, len(in))
+	for i, v := range in {
+		out[i] = v1alpha1.ResponseType(v)
+	}
+	return out
+}
+
+func responseTypesFrom(in []v1alpha1.ResponseType) []ResponseType {
+	if in == nil {
+		return nil
+	}
+	out := make([]ResponseType, len(in))
+	for i, v := range in {
+		out[i] = ResponseType(v)
+	}
+	return out
+}
+
+func redirectURIsTo(in []RedirectURI) []v1alpha1.RedirectURI {
+	if in == nil {
+		return nil
+	}
+	out := make([]v1alpha1.RedirectURI, len(in))
+	for i, v := range in {
+		out[i] = v1alpha1.RedirectURI(v)
+	}
+	return out
+}
+
+func redirectURIsFrom(in []v1alpha1.RedirectURI) []RedirectURI {
+	if in == nil {
+		return nil
+	}
+	out := make([]RedirectURI, len(in))
+	for i, v := range in {
+		out[i] = RedirectURI(v)
+	}
+	return out
+}
+
+func secretTargetsTo(in []SecretTarget) []v1alpha1.SecretTarget {
+	if in == nil {
+		return nil
+	}
+	out := make([]v1alpha1.SecretTarget, len(in))
+	for i, v := range in {
+		out[i] = v1alpha1.SecretTarget(v)
+	}
+	return out
+}
+
+func secretTargetsFrom(in []v1alpha1.SecretTarget) []SecretTarget {
+	if in == nil {
+		return nil
+	}
+	out := make([]SecretTarget, len(in))
+	for i, v := range in {
+		out[i] = SecretTarget(v)
+	}
+	return out
+}
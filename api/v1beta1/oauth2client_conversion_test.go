@@ -0,0 +1,155 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ory/hydra-maester/api/v1alpha1"
+	"github.com/ory/hydra-maester/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestOAuth2ClientConversion(t *testing.T) {
+	issuedAt := metav1.NewTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	hub := func() *v1alpha1.OAuth2Client {
+		return &v1alpha1.OAuth2Client{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+			},
+			Spec: v1alpha1.OAuth2ClientSpec{
+				Archetype:           v1alpha1.ClientArchetypeServerWeb,
+				GrantTypes:          []v1alpha1.GrantType{"client_credentials"},
+				ResponseTypes:       []v1alpha1.ResponseType{"token"},
+				RedirectURIs:        []v1alpha1.RedirectURI{"https://example.com/callback"},
+				Scope:               "read write",
+				SecretName:          "foo-secret",
+				SecretTTL:           &metav1.Duration{Duration: time.Hour},
+				RotationPolicy:      &v1alpha1.RotationPolicy{MaxAge: metav1.Duration{Duration: 30 * 24 * time.Hour}, Overlap: metav1.Duration{Duration: time.Hour}},
+				ExpiresAfter:        &metav1.Duration{Duration: 24 * time.Hour},
+				ExpiryAction:        v1alpha1.ExpiryActionDelete,
+				Suspend:             true,
+				DeletionPolicy:      v1alpha1.DeletionPolicyOrphan,
+				AccessTokenStrategy: v1alpha1.AccessTokenStrategyJWT,
+				SecretProjection: &v1alpha1.SecretProjection{
+					Format: v1alpha1.SecretProjectionFormatKeys,
+					Items: []v1alpha1.SecretProjectionItem{
+						{Property: v1alpha1.SecretPropertyClientID, Key: "ID"},
+						{Property: v1alpha1.SecretPropertyClientSecret, Encoding: v1alpha1.SecretValueEncodingHex},
+					},
+					Templates: []v1alpha1.SecretTemplate{{Key: "config.json", Template: `{"client_id": {{ json .client_id }}}`}},
+				},
+				SecretType:                  apiv1.SecretTypeBasicAuth,
+				SecretConflictPolicy:        v1alpha1.SecretConflictPolicyAdopt,
+				RevokeTokensOnRotation:      true,
+				SecretReplicationNamespaces: []string{"apps", "team-.*"},
+				SecretTargets:               []v1alpha1.SecretTarget{{Namespace: "frontend"}, {Namespace: "backend", Name: "shared-client"}},
+				ExistingSecretRef:           &apiv1.LocalObjectReference{Name: "legacy-credentials"},
+				SecretLabels:                map[string]string{"backup": "daily"},
+				SecretAnnotations:           map[string]string{"reloader.stakater.com/match": "true"},
+				SecretPush: &v1alpha1.SecretPush{
+					SecretStoreRef: v1alpha1.SecretStoreRef{Name: "vault", Kind: "ClusterSecretStore"},
+					RemoteKey:      "clients/my-client",
+				},
+				ConsentHints: &v1alpha1.ConsentHints{DisplayName: "Foo"},
+				TemplateRef:  &apiv1.LocalObjectReference{Name: "defaults"},
+			},
+			Status: v1alpha1.OAuth2ClientStatus{
+				ObservedGeneration:            2,
+				ClientID:                      "foo-id",
+				ClientSecretIssuedAt:          &issuedAt,
+				PreviousClientSecretExpiresAt: &issuedAt,
+				RotationRequest:               "2020-01-01T00:00:00Z",
+				SecretChecksum:                "abc",
+				TemplateGeneration:            3,
+				ReconciliationError: v1alpha1.ReconciliationError{
+					Code:        v1alpha1.StatusUpdateFailed,
+					Description: "error",
+				},
+				Conditions: []v1alpha1.OAuth2ClientCondition{
+					{Type: v1alpha1.OAuth2ClientConditionReady, Status: apiv1.ConditionFalse, Reason: "ClientUpdateFailed"},
+				},
+			},
+		}
+	}
+
+	t.Run("case=convert from v1alpha1", func(t *testing.T) {
+		var converted v1beta1.OAuth2Client
+		require.NoError(t, converted.ConvertFrom(hub()))
+
+		assert.Equal(t, []string{"read", "write"}, converted.Spec.Scopes)
+		assert.Equal(t, v1beta1.ClientArchetype("server-web"), converted.Spec.Archetype)
+		assert.Equal(t, "foo-secret", converted.Spec.Secret.Name)
+		assert.Equal(t, time.Hour, converted.Spec.Secret.TTL.Duration)
+		assert.Equal(t, 30*24*time.Hour, converted.Spec.Secret.RotationPolicy.MaxAge.Duration)
+		assert.Equal(t, time.Hour, converted.Spec.Secret.RotationPolicy.Overlap.Duration)
+		require.NotNil(t, converted.Spec.Secret.Projection)
+		assert.Equal(t, v1beta1.SecretProperty("client_id"), converted.Spec.Secret.Projection.Items[0].Property)
+		assert.Equal(t, v1beta1.SecretValueEncoding("Hex"), converted.Spec.Secret.Projection.Items[1].Encoding)
+		assert.Equal(t, []v1beta1.SecretTemplate{{Key: "config.json", Template: `{"client_id": {{ json .client_id }}}`}}, converted.Spec.Secret.Projection.Templates)
+		assert.Equal(t, []string{"apps", "team-.*"}, converted.Spec.Secret.ReplicationNamespaces)
+		assert.Equal(t, apiv1.SecretTypeBasicAuth, converted.Spec.Secret.Type)
+		assert.Equal(t, v1beta1.SecretConflictPolicy("Adopt"), converted.Spec.Secret.ConflictPolicy)
+		assert.True(t, converted.Spec.Secret.RevokeTokensOnRotation)
+		assert.Equal(t, []v1beta1.SecretTarget{{Namespace: "frontend"}, {Namespace: "backend", Name: "shared-client"}}, converted.Spec.Secret.Targets)
+		require.NotNil(t, converted.Spec.Secret.Push)
+		assert.Equal(t, "ClusterSecretStore", converted.Spec.Secret.Push.SecretStoreRef.Kind)
+		assert.Equal(t, "Foo", converted.Spec.ConsentHints.DisplayName)
+		assert.Equal(t, v1beta1.StatusCode("CLIENT_UPDATE_FAILED"), converted.Status.ReconciliationError.Code)
+		assert.Equal(t, "foo-id", converted.Status.ClientID)
+		assert.Equal(t, "defaults", converted.Spec.TemplateRef.Name)
+	})
+
+	t.Run("case=round trip through v1beta1", func(t *testing.T) {
+		var converted v1beta1.OAuth2Client
+		require.NoError(t, converted.ConvertFrom(hub()))
+
+		var restored v1alpha1.OAuth2Client
+		require.NoError(t, converted.ConvertTo(&restored))
+		assert.Equal(t, hub(), &restored)
+	})
+
+	t.Run("case=round trip with scopes given as an array", func(t *testing.T) {
+		original := hub()
+		original.Spec.Scope = ""
+		original.Spec.ScopeArray = []string{"read", "write"}
+
+		var converted v1beta1.OAuth2Client
+		require.NoError(t, converted.ConvertFrom(original))
+
+		var restored v1alpha1.OAuth2Client
+		require.NoError(t, converted.ConvertTo(&restored))
+		assert.Equal(t, original, &restored)
+	})
+
+	t.Run("case=changed scopes are converted to an array", func(t *testing.T) {
+		var converted v1beta1.OAuth2Client
+		require.NoError(t, converted.ConvertFrom(hub()))
+		converted.Spec.Scopes = []string{"read"}
+
+		var restored v1alpha1.OAuth2Client
+		require.NoError(t, converted.ConvertTo(&restored))
+		assert.Empty(t, restored.Spec.Scope)
+		assert.Equal(t, []string{"read"}, restored.Spec.ScopeArray)
+		assert.NotContains(t, restored.Annotations, v1beta1.ScopeAnnotation)
+	})
+}
@@ -0,0 +1,603 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"encoding/json"
+
+	apiv1 "k8s.io/api/core/v1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StatusCode is the code of a reconciliation error
+type StatusCode string
+
+// HydraAdmin defines the desired hydra admin instance to use for OAuth2Client
+type HydraAdmin struct {
+	// +kubebuilder:validation:MaxLength=64
+	// +kubebuilder:validation:Pattern=(^$|^https?://.*)
+	//
+	// URL is the URL for the hydra instance on
+	// which to set up the client. This value will override the value
+	// provided to `--hydra-url`
+	URL string `json:"url,omitempty"`
+
+	// +kubebuilder:validation:Maximum=65535
+	//
+	// Port is the port for the hydra instance on
+	// which to set up the client. This value will override the value
+	// provided to `--hydra-port`
+	Port int `json:"port,omitempty"`
+
+	// +kubebuilder:validation:Pattern=(^$|^/.*)
+	//
+	// Endpoint is the endpoint for the hydra instance on which
+	// to set up the client. This value will override the value
+	// provided to `--endpoint` (defaults to `"/clients"` in the
+	// application)
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// +kubebuilder:validation:Pattern=(^$|https?|off)
+	//
+	// ForwardedProto overrides the `--forwarded-proto` flag. The
+	// value "off" will force this to be off even if
+	// `--forwarded-proto` is specified
+	ForwardedProto string `json:"forwardedProto,omitempty"`
+}
+
+// OAuth2ClientSpec defines the desired state of OAuth2Client
+type OAuth2ClientSpec struct {
+
+	// ClientID is the ID of the client in ORY Hydra. If not set, ORY Hydra
+	// generates one when the client is registered.
+	ClientID string `json:"clientID,omitempty"`
+
+	// ClientName is the human-readable string name of the client to be presented to the end-user during authorization.
+	ClientName string `json:"clientName,omitempty"`
+
+	// +kubebuilder:validation:Enum=spa;mobile;server-web;machine
+	//
+	// Archetype expands into the settings of a common kind of client and
+	// enforces the security best practices for it: `spa` and `mobile` are
+	// public clients using the authorization code grant with PKCE,
+	// `server-web` is a confidential client using the authorization code
+	// grant and `machine` a client using the `client_credentials` grant.
+	// Settings given explicitly take precedence over the defaults of the
+	// archetype, but must satisfy its constraints.
+	Archetype ClientArchetype `json:"archetype,omitempty"`
+
+	// +kubebuilder:validation:Enum=machine
+	//
+	// Profile is the deprecated predecessor of Archetype. `machine` is the
+	// same as the `machine` archetype.
+	Profile ClientProfile `json:"profile,omitempty"`
+
+	// +kubebuilder:validation:MaxItems=6
+	// +kubebuilder:validation:MinItems=1
+	//
+	// GrantTypes is an array of grant types the client is allowed to use. It
+	// is required unless a profile is set.
+	GrantTypes []GrantType `json:"grantTypes,omitempty"`
+
+	// +kubebuilder:validation:MaxItems=3
+	// +kubebuilder:validation:MinItems=1
+	//
+	// ResponseTypes is an array of the OAuth 2.0 response type strings that the client can
+	// use at the authorization endpoint.
+	ResponseTypes []ResponseType `json:"responseTypes,omitempty"`
+
+	// RedirectURIs is an array of the redirect URIs allowed for the application
+	RedirectURIs []RedirectURI `json:"redirectUris,omitempty"`
+
+	// PostLogoutRedirectURIs is an array of the post logout redirect URIs allowed for the application
+	PostLogoutRedirectURIs []RedirectURI `json:"postLogoutRedirectUris,omitempty"`
+
+	// AllowedCorsOrigins is an array of allowed CORS origins
+	AllowedCorsOrigins []RedirectURI `json:"allowedCorsOrigins,omitempty"`
+
+	// Audience is a whitelist defining the audiences this client is allowed to request tokens for
+	Audience []string `json:"audience,omitempty"`
+
+	// +kubebuilder:validation:MinItems=1
+	//
+	// Scopes are the scope values (as described in Section 3.3 of OAuth 2.0
+	// [RFC6749]) that the client can use when requesting access tokens
+	Scopes []string `json:"scopes"`
+
+	// Secret defines the Secret holding the credentials of the client
+	Secret ClientSecret `json:"secret"`
+
+	// HydraAdmin is the optional configuration to use for managing
+	// this client
+	HydraAdmin HydraAdmin `json:"hydraAdmin,omitempty"`
+
+	// HydraInstanceRef references the HydraInstance to manage this client in.
+	// It is mutually exclusive with HydraAdmin.
+	HydraInstanceRef *apiv1.LocalObjectReference `json:"hydraInstanceRef,omitempty"`
+
+	// +kubebuilder:validation:Enum=client_secret_basic;client_secret_post;private_key_jwt;none
+	//
+	// Indication which authentication method shoud be used for the token endpoint
+	TokenEndpointAuthMethod TokenEndpointAuthMethod `json:"tokenEndpointAuthMethod,omitempty"`
+
+	// +kubebuilder:validation:Enum=RS256;RS384;RS512;PS256;PS384;PS512;ES256;ES384;ES512;HS256;HS384;HS512
+	//
+	// TokenEndpointAuthSigningAlg is the algorithm that must be used for
+	// signing the JWT used to authenticate the client at the token endpoint
+	// with the `private_key_jwt` or `client_secret_jwt` methods
+	TokenEndpointAuthSigningAlg string `json:"tokenEndpointAuthSigningAlg,omitempty"`
+
+	// +kubebuilder:validation:Enum=opaque;jwt
+	//
+	// AccessTokenStrategy is the strategy ORY Hydra issues the access tokens
+	// of this client with, overriding its global setting. With `jwt` and no
+	// audience set, the audience defaults to the one the
+	// HydraMaesterConfiguration maps the namespace of the client to.
+	AccessTokenStrategy AccessTokenStrategy `json:"accessTokenStrategy,omitempty"`
+
+	// Metadata is abritrary data
+	Metadata json.RawMessage `json:"metadata,omitempty"`
+
+	// SkipConsent skips the consent screen for this client. It should only
+	// be set for trusted first-party clients.
+	SkipConsent bool `json:"skipConsent,omitempty"`
+
+	// SkipLogoutConsent skips the logout consent screen for this client. It
+	// should only be set for trusted first-party clients.
+	SkipLogoutConsent bool `json:"skipLogoutConsent,omitempty"`
+
+	// UnmanagedFields lists ORY Hydra client properties, by their JSON name
+	// (e.g. `redirect_uris`), that are managed outside of this resource. Their
+	// current values in ORY Hydra are preserved when the client is updated.
+	UnmanagedFields []string `json:"unmanagedFields,omitempty"`
+
+	// GCExempt marks the client in ORY Hydra as exempt from garbage
+	// collection, so it is only removed from ORY Hydra when this resource is
+	// deleted.
+	GCExempt bool `json:"gcExempt,omitempty"`
+
+	// UpdateStrategy defines how the client is updated in ORY Hydra. With
+	// `Replace` (the default) all properties are asserted, with `Merge` only
+	// properties set in this spec are asserted and all others are left untouched.
+	UpdateStrategy UpdateStrategy `json:"updateStrategy,omitempty"`
+
+	// ConflictPolicy defines what happens when the client has been changed in
+	// ORY Hydra since the controller last wrote it. With `Overwrite` (the
+	// default) the changes are overwritten, with `Hold` the client is left
+	// untouched until the policy is changed to `Overwrite`. Either way, the
+	// ConflictDetected condition is set.
+	ConflictPolicy ConflictPolicy `json:"conflictPolicy,omitempty"`
+
+	// ExpiresAfter is the lifetime of the client, counted from the creation
+	// of this resource. Once it has passed the client expires.
+	ExpiresAfter *metav1.Duration `json:"expiresAfter,omitempty"`
+
+	// NotAfter is the time the client expires at. If ExpiresAfter is set as
+	// well, the client expires at whichever comes first.
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+
+	// ExpiryAction defines what happens once the client has expired. With
+	// `Disable` (the default) the client is deleted from ORY Hydra, this
+	// resource and its Secret are kept and the Expired condition is set. With
+	// `Delete` this resource is deleted as well.
+	ExpiryAction ExpiryAction `json:"expiryAction,omitempty"`
+
+	// Suspend stops the reconciliation of the client, e.g. to freeze it
+	// during an incident. The client in ORY Hydra and the Secret are left as
+	// they are until it is unset, but deleting this resource still deletes
+	// the client.
+	Suspend bool `json:"suspend,omitempty"`
+
+	// DeletionPolicy defines what happens to the client in ORY Hydra and the
+	// Secret when this resource is deleted. With `Delete` (the default) both
+	// are deleted, with `Orphan` both are kept and the client is exempted from
+	// garbage collection.
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// ConsentHints are hints for login and consent apps. They are added to
+	// the client metadata under the `hydra-maester.ory.sh/consent-hints` key.
+	ConsentHints *ConsentHints `json:"consentHints,omitempty"`
+
+	// Extra holds ORY Hydra client properties, by their JSON name, that are
+	// not modeled by this spec yet. They are sent to ORY Hydra as they are,
+	// but never override the properties modeled by this spec.
+	Extra map[string]apiextensionsv1beta1.JSON `json:"extra,omitempty"`
+
+	// TemplateRef references an OAuth2ClientTemplate in the namespace of the
+	// client. Settings which are not set in this spec are taken from it.
+	TemplateRef *apiv1.LocalObjectReference `json:"templateRef,omitempty"`
+}
+
+// ClientSecret defines the Secret holding the credentials of a client
+type ClientSecret struct {
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*
+	//
+	// Name is the name of the Secret
+	Name string `json:"name"`
+
+	// TTL is the lifetime of the client secret. Once it has passed ORY Hydra
+	// rejects the secret and the SecretExpired condition is set.
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+
+	// RotationPolicy, if set, makes the controller rotate the client secret
+	// once it is older than the policy allows
+	RotationPolicy *RotationPolicy `json:"rotationPolicy,omitempty"`
+
+	// RevokeTokensOnRotation deletes the access tokens issued to the client from
+	// ORY Hydra once its client secret has been rotated, so that tokens obtained
+	// with leaked credentials can't be used any longer
+	RevokeTokensOnRotation bool `json:"revokeTokensOnRotation,omitempty"`
+
+	// Projection defines which client properties are written to the Secret
+	// and under which keys. By default the Secret holds the client ID under
+	// `client_id` and the client secret under `client_secret`.
+	Projection *SecretProjection `json:"projection,omitempty"`
+
+	// Type is the type of the Secret, e.g. `kubernetes.io/basic-auth` for
+	// consumers requiring it, or a custom type. It defaults to `Opaque`.
+	// Secrets of type `kubernetes.io/basic-auth` hold the client ID under
+	// `username` and the client secret under `password` by default. Changing
+	// the type replaces the Secret, as Kubernetes doesn't allow updating it.
+	Type apiv1.SecretType `json:"type,omitempty"`
+
+	// ConflictPolicy defines what happens when a Secret named Name exists
+	// which the controller didn't create and can't use as the provided
+	// credentials of the client. With `Fail` (the default) the reconciliation
+	// fails until Name is changed, with `Adopt` the Secret is taken over,
+	// keeping its labels and annotations, unless another object controls it,
+	// and with `Overwrite` it is replaced.
+	ConflictPolicy SecretConflictPolicy `json:"conflictPolicy,omitempty"`
+
+	// ReplicationNamespaces are the namespaces, or regular expressions
+	// matching them, the Secret is replicated to by replication controllers
+	// such as kubernetes-replicator or Reflector, so that workloads in several
+	// namespaces can use the credentials of the client
+	ReplicationNamespaces []string `json:"replicationNamespaces,omitempty"`
+
+	// Targets are namespaces the controller keeps copies of the Secret in,
+	// for applications split across namespaces sharing the client. A
+	// namespace only accepts copies if its
+	// `hydra-maester.ory.sh/accept-secrets-from` annotation lists the
+	// namespace of the client.
+	Targets []SecretTarget `json:"targets,omitempty"`
+
+	// ExistingRef references a Secret in the namespace of the client holding
+	// the `client_id` and `client_secret` the client is registered with,
+	// instead of credentials generated by ORY Hydra, e.g. to migrate an
+	// application with fixed credentials. It is only read when the client is
+	// registered and its Secret doesn't exist yet.
+	ExistingRef *apiv1.LocalObjectReference `json:"existingRef,omitempty"`
+
+	// Labels are set on the Secret, e.g. for policy engines, backup tools or
+	// reloaders to match it. They don't override the labels set by the
+	// controller.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations are set on the Secret. They don't override the annotations
+	// set by the controller.
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Push, if set, pushes the Secret to an external secret store with a
+	// PushSecret of the External Secrets Operator, once the client is
+	// registered in ORY Hydra
+	Push *SecretPush `json:"push,omitempty"`
+}
+
+// SecretTarget is a namespace a copy of the Secret of a client is kept in
+type SecretTarget struct {
+	// +kubebuilder:validation:MinLength=1
+	//
+	// Namespace is the namespace of the copy
+	Namespace string `json:"namespace"`
+
+	// Name is the name of the copy, the name of the Secret by default
+	Name string `json:"name,omitempty"`
+}
+
+// SecretPush defines how the Secret of a client is pushed to an external
+// secret store by the External Secrets Operator
+type SecretPush struct {
+	// SecretStoreRef references the store the Secret is pushed to
+	SecretStoreRef SecretStoreRef `json:"secretStoreRef"`
+
+	// RemoteKey is the key of the credentials in the store, the namespace
+	// and name of the client, e.g. `default/my-client`, by default
+	RemoteKey string `json:"remoteKey,omitempty"`
+}
+
+// SecretStoreRef references a SecretStore of the External Secrets Operator
+type SecretStoreRef struct {
+	// +kubebuilder:validation:MinLength=1
+	//
+	// Name is the name of the store
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Enum=SecretStore;ClusterSecretStore
+	//
+	// Kind is `SecretStore` (the default), a store in the namespace of the
+	// client, or `ClusterSecretStore`
+	Kind string `json:"kind,omitempty"`
+}
+
+// RotationPolicy defines when the client secret is rotated by the controller
+type RotationPolicy struct {
+	// MaxAge is the age of the client secret past which it is replaced by a
+	// new one in ORY Hydra and in the Secret
+	MaxAge metav1.Duration `json:"maxAge"`
+
+	// Overlap is how long the previous client secret is kept in the Secret
+	// after a rotation, under the key of the client secret suffixed with
+	// `_previous`. It isn't kept by default.
+	Overlap metav1.Duration `json:"overlap,omitempty"`
+}
+
+// SecretProjection defines the layout of the Secret holding the credentials
+// of a client
+type SecretProjection struct {
+	// Format is `Keys` (the default) to write each property under its own
+	// key, or `JSON` to write all properties as a single JSON object
+	Format SecretProjectionFormat `json:"format,omitempty"`
+
+	// JSONKey is the key of the JSON object with the `JSON` format,
+	// `client.json` by default
+	JSONKey string `json:"jsonKey,omitempty"`
+
+	// +kubebuilder:validation:MinItems=1
+	//
+	// Items are the properties written to the Secret. The client ID, and the
+	// client secret unless the token endpoint auth method is `none`, must be
+	// included.
+	Items []SecretProjectionItem `json:"items"`
+
+	// Templates are additional keys rendered from Go templates, e.g. a
+	// configuration file ready to be mounted by applications
+	Templates []SecretTemplate `json:"templates,omitempty"`
+}
+
+// SecretTemplate renders a key of the Secret from a Go template. The
+// template is executed with the client properties by their name, e.g.
+// `{{ .client_id }}`; properties without a value are empty. The `json`
+// function quotes a value as a JSON string.
+type SecretTemplate struct {
+	// +kubebuilder:validation:MinLength=1
+	//
+	// Key is the Secret key the template is rendered under
+	Key string `json:"key"`
+
+	// Template is the Go template rendered under Key
+	Template string `json:"template"`
+}
+
+// SecretProjectionItem maps a client property to a key
+type SecretProjectionItem struct {
+	// Property is the client property to write
+	Property SecretProperty `json:"property"`
+
+	// Key is the Secret key with the `Keys` format, or the field name of the
+	// JSON object with the `JSON` format. It defaults to the property name.
+	Key string `json:"key,omitempty"`
+
+	// Encoding is how the value is written, `Raw` (the default) as it is,
+	// `Hex` as lowercase hexadecimal or `Base64URL` as unpadded base64url,
+	// for consumers requiring a specific encoding of e.g. the client secret
+	Encoding SecretValueEncoding `json:"encoding,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=Keys;JSON
+// SecretProjectionFormat represents how client properties are written to a Secret
+type SecretProjectionFormat string
+
+// +kubebuilder:validation:Enum=Fail;Adopt;Overwrite
+// SecretConflictPolicy represents how a Secret which exists before the
+// controller writes the credentials of a client is handled
+type SecretConflictPolicy string
+
+// +kubebuilder:validation:Enum=Raw;Hex;Base64URL
+// SecretValueEncoding represents how a client property is encoded in a Secret
+type SecretValueEncoding string
+
+// +kubebuilder:validation:Enum=client_id;client_secret;scope;audience;issuer;authorization_endpoint;token_endpoint
+// SecretProperty represents a client property that can be written to a Secret
+type SecretProperty string
+
+// ConsentHints describe how login and consent apps should present a client.
+// They are a stable contract, serialized into the client metadata as a JSON
+// object with the field names below.
+type ConsentHints struct {
+	// DisplayName is the name shown to the end-user instead of the client name
+	DisplayName string `json:"displayName,omitempty"`
+
+	// LogoURI is the URL of a logo shown to the end-user
+	LogoURI string `json:"logoUri,omitempty"`
+
+	// FirstParty marks the client as operated by the same party as the
+	// consent app, which may then skip or simplify the consent screen
+	FirstParty bool `json:"firstParty,omitempty"`
+
+	// RememberConsent hints that granted consent should be remembered
+	RememberConsent bool `json:"rememberConsent,omitempty"`
+
+	// ScopeDescriptions maps scopes to human-readable descriptions shown on
+	// the consent screen
+	ScopeDescriptions map[string]string `json:"scopeDescriptions,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=spa;mobile;server-web;machine
+// ClientArchetype represents a preset of client settings encoding the
+// security best practices of a common kind of client
+type ClientArchetype string
+
+// +kubebuilder:validation:Enum=machine
+// ClientProfile represents a preset of client settings. It is superseded by
+// ClientArchetype.
+type ClientProfile string
+
+// +kubebuilder:validation:Enum=client_credentials;authorization_code;implicit;refresh_token;urn:ietf:params:oauth:grant-type:device_code;urn:ietf:params:oauth:grant-type:token-exchange
+// GrantType represents an OAuth 2.0 grant type
+type GrantType string
+
+// +kubebuilder:validation:Enum=id_token;code;token
+// ResponseType represents an OAuth 2.0 response type strings
+type ResponseType string
+
+// +kubebuilder:validation:Pattern=\w+:/?/?[^\s]+
+// RedirectURI represents a redirect URI for the client
+type RedirectURI string
+
+// +kubebuilder:validation:Enum=client_secret_basic;client_secret_post;private_key_jwt;none
+// TokenEndpointAuthMethod represents an authentication method for token endpoint
+type TokenEndpointAuthMethod string
+
+// +kubebuilder:validation:Enum=Replace;Merge
+// UpdateStrategy represents how a registered client is updated in ORY Hydra
+type UpdateStrategy string
+
+// +kubebuilder:validation:Enum=Overwrite;Hold
+// ConflictPolicy represents how changes made to a client outside of the
+// controller are resolved
+type ConflictPolicy string
+
+// +kubebuilder:validation:Enum=opaque;jwt
+// AccessTokenStrategy represents the format of the access tokens of a client
+type AccessTokenStrategy string
+
+// +kubebuilder:validation:Enum=Disable;Delete
+// ExpiryAction represents what happens to a client once it has expired
+type ExpiryAction string
+
+// +kubebuilder:validation:Enum=Delete;Orphan
+// DeletionPolicy represents what happens to a client once its resource is
+// deleted
+type DeletionPolicy string
+
+// OAuth2ClientStatus defines the observed state of OAuth2Client
+type OAuth2ClientStatus struct {
+	// ObservedGeneration is the most recent generation of the spec that has
+	// been successfully applied to ORY Hydra
+	ObservedGeneration  int64               `json:"observedGeneration,omitempty"`
+	ReconciliationError ReconciliationError `json:"reconciliationError,omitempty"`
+
+	// ClientID is the ID of the client registered in ORY Hydra
+	ClientID string `json:"clientID,omitempty"`
+
+	// DeviceAuthorization holds the endpoints a device flow client needs to
+	// bootstrap. It is only set for clients allowed to use the device code grant
+	// when the controller knows ORY Hydra's public URL.
+	DeviceAuthorization *DeviceAuthorization `json:"deviceAuthorization,omitempty"`
+
+	// ClientSecretExpiresAt is the time the client secret expires at, if the
+	// client has a secret TTL
+	ClientSecretExpiresAt *metav1.Time `json:"clientSecretExpiresAt,omitempty"`
+
+	// ClientSecretIssuedAt is the time the current client secret was issued
+	// at, if the client has a rotation policy
+	ClientSecretIssuedAt *metav1.Time `json:"clientSecretIssuedAt,omitempty"`
+
+	// PreviousClientSecretExpiresAt is the time the previous client secret
+	// is removed from the Secret at, while it is kept after a rotation
+	PreviousClientSecretExpiresAt *metav1.Time `json:"previousClientSecretExpiresAt,omitempty"`
+
+	// HydraUpdatedAt is the time ORY Hydra reported the client as updated at
+	// after the last write by the controller
+	HydraUpdatedAt string `json:"hydraUpdatedAt,omitempty"`
+
+	// ManualSecretVersion is the resource version of the manually set client
+	// secret last pushed to ORY Hydra
+	ManualSecretVersion string `json:"manualSecretVersion,omitempty"`
+
+	// RotationRequest is the value of the rotate annotation the client
+	// secret was last rotated for
+	RotationRequest string `json:"rotationRequest,omitempty"`
+
+	// SecretChecksum is the checksum of the data of the Secret generated for
+	// the client, as in its checksum annotation, which changes whenever the
+	// credentials do
+	SecretChecksum string `json:"secretChecksum,omitempty"`
+
+	// TemplateGeneration is the generation of the OAuth2ClientTemplate last
+	// applied to ORY Hydra
+	TemplateGeneration int64 `json:"templateGeneration,omitempty"`
+
+	// Conditions represent the latest available observations of the client's state
+	Conditions []OAuth2ClientCondition `json:"conditions,omitempty"`
+}
+
+// DeviceAuthorization represents the device flow endpoints exposed by ORY Hydra
+type DeviceAuthorization struct {
+	// DeviceAuthorizationEndpoint is the URL devices request a device code from
+	DeviceAuthorizationEndpoint string `json:"deviceAuthorizationEndpoint,omitempty"`
+	// VerificationURI is the URL users visit to enter the user code
+	VerificationURI string `json:"verificationUri,omitempty"`
+}
+
+// ReconciliationError represents an error that occurred during the reconciliation process
+type ReconciliationError struct {
+	// Code is the status code of the reconciliation error
+	Code StatusCode `json:"statusCode,omitempty"`
+	// Description is the description of the reconciliation error
+	Description string `json:"description,omitempty"`
+}
+
+// OAuth2ClientConditionType represents the type of an OAuth2Client condition
+type OAuth2ClientConditionType string
+
+// OAuth2ClientCondition contains details about the state of an OAuth2Client
+type OAuth2ClientCondition struct {
+	// Type is the type of the condition
+	Type OAuth2ClientConditionType `json:"type"`
+	// Status is the status of the condition, one of True, False or Unknown
+	Status apiv1.ConditionStatus `json:"status"`
+	// LastTransitionTime is the last time the condition changed its status
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// Reason is a machine-readable explanation of the condition's last transition
+	Reason string `json:"reason,omitempty"`
+	// Message is a human-readable explanation of the condition's last transition
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=oac;oauth2c,categories=ory;all
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Client ID",type=string,JSONPath=`.status.clientID`
+// +kubebuilder:printcolumn:name="Secret",type=string,JSONPath=`.spec.secret.name`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Error",type=string,JSONPath=`.status.reconciliationError.statusCode`,priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// OAuth2Client is the Schema for the oauth2clients API
+type OAuth2Client struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OAuth2ClientSpec   `json:"spec,omitempty"`
+	Status OAuth2ClientStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OAuth2ClientList contains a list of OAuth2Client
+type OAuth2ClientList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OAuth2Client `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OAuth2Client{}, &OAuth2ClientList{})
+}
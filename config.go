@@ -0,0 +1,175 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// configFlag is the flag naming the configuration file, which can't be
+	// set in the file itself
+	configFlag = "config"
+	// envPrefix prefixes the environment variables setting flags
+	envPrefix = "HYDRA_MAESTER_"
+)
+
+// envAliases are the environment variables setting the flags of ORY Hydra's
+// admin API besides their prefixed names
+var envAliases = map[string]string{
+	"hydra-url":       "HYDRA_ADMIN_URL",
+	"hydra-port":      "HYDRA_ADMIN_PORT",
+	"endpoint":        "HYDRA_ADMIN_ENDPOINT",
+	"forwarded-proto": "HYDRA_ADMIN_FORWARDED_PROTO",
+}
+
+// envName returns the environment variable setting the flag with the given
+// name, e.g. HYDRA_MAESTER_SYNC_PERIOD for --sync-period
+func envName(name string) string {
+	return envPrefix + strings.ToUpper(strings.Replace(name, "-", "_", -1))
+}
+
+// applyEnv sets the flags of fs which were not set on the command line from
+// the environment variables named by envName, or envAliases, as read by
+// lookup
+func applyEnv(fs *flag.FlagSet, lookup func(string) (string, bool)) error {
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+
+	var err error
+	fs.VisitAll(func(f *flag.Flag) {
+		if err != nil || set[f.Name] {
+			return
+		}
+		env := envName(f.Name)
+		value, ok := lookup(env)
+		if alias, hasAlias := envAliases[f.Name]; !ok && hasAlias {
+			env = alias
+			value, ok = lookup(alias)
+		}
+		if !ok {
+			return
+		}
+		if setErr := fs.Set(f.Name, value); setErr != nil {
+			err = fmt.Errorf("invalid value %q of %s: %w", value, env, setErr)
+		}
+	})
+	return err
+}
+
+// applyConfigFile sets the flags of fs which were not set on the command
+// line, or by environment variables, from the YAML file at path. Its keys
+// are the names of the flags, nested mappings are joined with dashes, e.g.
+// `hydra: {url: ...}` sets --hydra-url, and lists are joined with commas.
+func applyConfigFile(fs *flag.FlagSet, path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("unable to read the configuration file: %w", err)
+	}
+	var config map[string]interface{}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf("unable to parse the configuration file %s: %w", path, err)
+	}
+
+	values := map[string]string{}
+	if err := flattenConfig("", config, values); err != nil {
+		return fmt.Errorf("invalid configuration file %s: %w", path, err)
+	}
+
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if name == configFlag || fs.Lookup(name) == nil {
+			return fmt.Errorf("invalid configuration file %s: unknown option %q", path, name)
+		}
+		// flags set on the command line or by the environment override the
+		// file
+		if set[name] {
+			continue
+		}
+		if err := fs.Set(name, values[name]); err != nil {
+			return fmt.Errorf("invalid configuration file %s: invalid value %q for %s: %w", path, values[name], name, err)
+		}
+	}
+	return nil
+}
+
+// flattenConfig adds the values of config to values, by the names of the
+// flags they set
+func flattenConfig(prefix string, config map[string]interface{}, values map[string]string) error {
+	for key, value := range config {
+		name := key
+		if prefix != "" {
+			name = prefix + "-" + key
+		}
+		switch v := value.(type) {
+		case nil:
+		case map[string]interface{}:
+			if err := flattenConfig(name, v, values); err != nil {
+				return err
+			}
+		case []interface{}:
+			items := make([]string, 0, len(v))
+			for _, item := range v {
+				s, err := configScalar(name, item)
+				if err != nil {
+					return err
+				}
+				items = append(items, s)
+			}
+			values[name] = strings.Join(items, ",")
+		default:
+			s, err := configScalar(name, v)
+			if err != nil {
+				return err
+			}
+			values[name] = s
+		}
+	}
+	return nil
+}
+
+// configScalar formats a scalar value of the configuration file as a flag
+// value
+func configScalar(name string, value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	default:
+		return "", fmt.Errorf("unsupported value of %s: %v", name, value)
+	}
+}
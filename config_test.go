@@ -0,0 +1,271 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+)
+
+// testFlagSet returns a flag set with a few of the controller's flags
+func testFlagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	fs.String(configFlag, "", "")
+	fs.String("hydra-url", "", "")
+	fs.Int("hydra-port", 4445, "")
+	fs.String("endpoint", "/clients", "")
+	fs.String("forwarded-proto", "", "")
+	fs.Bool("leader-elect", false, "")
+	fs.String("sync-period", "10h", "")
+	fs.String("namespaces", "", "")
+	return fs
+}
+
+func TestFlattenConfig(t *testing.T) {
+
+	for name, tc := range map[string]struct {
+		config   string
+		expected map[string]string
+		err      string
+	}{
+		"scalars": {
+			config:   "hydra-url: http://hydra\nhydra-port: 4445\nleader-elect: true\n",
+			expected: map[string]string{"hydra-url": "http://hydra", "hydra-port": "4445", "leader-elect": "true"},
+		},
+		"nested mappings": {
+			config:   "hydra:\n  url: http://hydra\n  port: 4445\nsync:\n  period: 1h\n",
+			expected: map[string]string{"hydra-url": "http://hydra", "hydra-port": "4445", "sync-period": "1h"},
+		},
+		"deeply nested mappings": {
+			config:   "a:\n  b:\n    c: d\n",
+			expected: map[string]string{"a-b-c": "d"},
+		},
+		"lists": {
+			config:   "namespaces: [foo, bar]\n",
+			expected: map[string]string{"namespaces": "foo,bar"},
+		},
+		"null values": {
+			config:   "hydra-url:\n",
+			expected: map[string]string{},
+		},
+		"fractional numbers": {
+			config:   "ratio: 0.5\n",
+			expected: map[string]string{"ratio": "0.5"},
+		},
+		"nested lists": {
+			config: "namespaces: [[foo]]\n",
+			err:    "unsupported value of namespaces",
+		},
+		"mappings in lists": {
+			config: "namespaces: [{foo: bar}]\n",
+			err:    "unsupported value of namespaces",
+		},
+	} {
+		t.Run("case="+name, func(t *testing.T) {
+			var config map[string]interface{}
+			require.NoError(t, yaml.Unmarshal([]byte(tc.config), &config))
+
+			values := map[string]string{}
+			err := flattenConfig("", config, values)
+			if tc.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, values)
+		})
+	}
+}
+
+func TestApplyConfigFile(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "config")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	for name, tc := range map[string]struct {
+		config   string
+		args     []string
+		expected map[string]string
+		err      string
+	}{
+		"file sets flags": {
+			config:   "hydra:\n  url: http://hydra\n  port: 4444\nleader-elect: true\n",
+			expected: map[string]string{"hydra-url": "http://hydra", "hydra-port": "4444", "leader-elect": "true", "endpoint": "/clients"},
+		},
+		"command line overrides the file": {
+			config:   "hydra:\n  url: http://hydra\n  port: 4444\n",
+			args:     []string{"--hydra-port=4446"},
+			expected: map[string]string{"hydra-url": "http://hydra", "hydra-port": "4446"},
+		},
+		"lists": {
+			config:   "namespaces:\n- foo\n- bar\n",
+			expected: map[string]string{"namespaces": "foo,bar"},
+		},
+		"empty file": {
+			config:   "",
+			expected: map[string]string{"hydra-url": "", "hydra-port": "4445"},
+		},
+		"unknown option": {
+			config: "hydra:\n  uri: http://hydra\n",
+			err:    `unknown option "hydra-uri"`,
+		},
+		"config file option": {
+			config: "config: other.yaml\n",
+			err:    `unknown option "config"`,
+		},
+		"invalid value": {
+			config: "hydra-port: foo\n",
+			err:    `invalid value "foo" for hydra-port`,
+		},
+		"invalid YAML": {
+			config: "hydra: [\n",
+			err:    "unable to parse the configuration file",
+		},
+		"not a mapping": {
+			config: "- hydra-url\n",
+			err:    "unable to parse the configuration file",
+		},
+	} {
+		t.Run("case="+name, func(t *testing.T) {
+			path := filepath.Join(dir, "config.yaml")
+			require.NoError(t, ioutil.WriteFile(path, []byte(tc.config), 0600))
+
+			fs := testFlagSet()
+			require.NoError(t, fs.Parse(tc.args))
+			err := applyConfigFile(fs, path)
+			if tc.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+			for name, value := range tc.expected {
+				assert.Equal(t, value, fs.Lookup(name).Value.String(), name)
+			}
+		})
+	}
+
+	t.Run("case=missing file", func(t *testing.T) {
+		err := applyConfigFile(testFlagSet(), filepath.Join(dir, "missing.yaml"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unable to read the configuration file")
+	})
+}
+
+func TestEnvName(t *testing.T) {
+	assert.Equal(t, "HYDRA_MAESTER_SYNC_PERIOD", envName("sync-period"))
+	assert.Equal(t, "HYDRA_MAESTER_HYDRA_URL", envName("hydra-url"))
+}
+
+func TestApplyEnv(t *testing.T) {
+
+	for name, tc := range map[string]struct {
+		env      map[string]string
+		args     []string
+		expected map[string]string
+		err      string
+	}{
+		"prefixed names": {
+			env:      map[string]string{"HYDRA_MAESTER_SYNC_PERIOD": "1h", "HYDRA_MAESTER_LEADER_ELECT": "true"},
+			expected: map[string]string{"sync-period": "1h", "leader-elect": "true"},
+		},
+		"aliases": {
+			env: map[string]string{
+				"HYDRA_ADMIN_URL":             "http://hydra",
+				"HYDRA_ADMIN_PORT":            "4444",
+				"HYDRA_ADMIN_ENDPOINT":        "/admin/clients",
+				"HYDRA_ADMIN_FORWARDED_PROTO": "https",
+			},
+			expected: map[string]string{"hydra-url": "http://hydra", "hydra-port": "4444", "endpoint": "/admin/clients", "forwarded-proto": "https"},
+		},
+		"prefixed names override aliases": {
+			env:      map[string]string{"HYDRA_MAESTER_HYDRA_URL": "http://prefixed", "HYDRA_ADMIN_URL": "http://alias"},
+			expected: map[string]string{"hydra-url": "http://prefixed"},
+		},
+		"command line overrides the environment": {
+			env:      map[string]string{"HYDRA_MAESTER_HYDRA_PORT": "4444", "HYDRA_ADMIN_URL": "http://alias", "HYDRA_MAESTER_SYNC_PERIOD": "1h"},
+			args:     []string{"--hydra-port=4446", "--hydra-url=http://flag"},
+			expected: map[string]string{"hydra-port": "4446", "hydra-url": "http://flag", "sync-period": "1h"},
+		},
+		"empty values are set": {
+			env:      map[string]string{"HYDRA_MAESTER_ENDPOINT": ""},
+			expected: map[string]string{"endpoint": ""},
+		},
+		"unrelated variables": {
+			env:      map[string]string{"SYNC_PERIOD": "1h", "HYDRA_URL": "http://hydra"},
+			expected: map[string]string{"sync-period": "10h", "hydra-url": ""},
+		},
+		"invalid value": {
+			env: map[string]string{"HYDRA_MAESTER_HYDRA_PORT": "foo"},
+			err: `invalid value "foo" of HYDRA_MAESTER_HYDRA_PORT`,
+		},
+		"invalid alias value": {
+			env: map[string]string{"HYDRA_ADMIN_PORT": "foo"},
+			err: `invalid value "foo" of HYDRA_ADMIN_PORT`,
+		},
+	} {
+		t.Run("case="+name, func(t *testing.T) {
+			fs := testFlagSet()
+			require.NoError(t, fs.Parse(tc.args))
+			err := applyEnv(fs, func(name string) (string, bool) {
+				value, ok := tc.env[name]
+				return value, ok
+			})
+			if tc.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+			for name, value := range tc.expected {
+				assert.Equal(t, value, fs.Lookup(name).Value.String(), name)
+			}
+		})
+	}
+}
+
+func TestConfigPrecedence(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "config")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.yaml")
+	require.NoError(t, ioutil.WriteFile(path, []byte("hydra:\n  url: http://file\n  port: 4444\nsync-period: 2h\n"), 0600))
+
+	fs := testFlagSet()
+	require.NoError(t, fs.Parse([]string{"--hydra-port=4446"}))
+	env := map[string]string{"HYDRA_ADMIN_URL": "http://env", "HYDRA_MAESTER_HYDRA_PORT": "4447"}
+	require.NoError(t, applyEnv(fs, func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}))
+	require.NoError(t, applyConfigFile(fs, path))
+
+	// the command line wins over the environment, which wins over the file
+	assert.Equal(t, "4446", fs.Lookup("hydra-port").Value.String())
+	assert.Equal(t, "http://env", fs.Lookup("hydra-url").Value.String())
+	assert.Equal(t, "2h", fs.Lookup("sync-period").Value.String())
+}
@@ -0,0 +1,74 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	"github.com/ory/hydra-maester/pkg/hydraclient"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+// auditDrift describes how the client fetched from ORY Hydra differs from c,
+// nothing if it matches. fetched is nil if the client is not registered.
+func auditDrift(c *hydrav1alpha1.OAuth2Client, credentials *hydraclient.Oauth2ClientCredentials, fetched *hydraclient.OAuth2ClientJSON) (string, error) {
+	if credentials == nil && c.IsPublic() {
+		return "public client is not registered in ORY Hydra", nil
+	}
+	if credentials == nil {
+		return fmt.Sprintf("secret %s/%s does not exist", c.Namespace, c.Spec.SecretName), nil
+	}
+	if fetched == nil {
+		return fmt.Sprintf("client %s is not registered in ORY Hydra", credentials.ID), nil
+	}
+	if owner := fmt.Sprintf("%s/%s", c.Name, c.Namespace); fetched.Owner != owner {
+		return fmt.Sprintf("client %s is owned by %s in ORY Hydra", credentials.ID, fetched.Owner), nil
+	}
+	drifted, err := hasDrifted(c, credentials, fetched)
+	if err != nil || !drifted {
+		return "", err
+	}
+	return fmt.Sprintf("client %s differs from the spec in ORY Hydra", credentials.ID), nil
+}
+
+// auditOAuth2Client reports whether c matches the client registered in ORY
+// Hydra, through its InSync condition, an event and the drift metric, without
+// changing ORY Hydra. conditionsChanged forces a status update for the
+// conditions set earlier in the reconciliation.
+func (r *OAuth2ClientReconciler) auditOAuth2Client(ctx context.Context, c *hydrav1alpha1.OAuth2Client, credentials *hydraclient.Oauth2ClientCredentials, fetched *hydraclient.OAuth2ClientJSON, conditionsChanged bool) error {
+	message, err := auditDrift(c, credentials, fetched)
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	if message != "" {
+		clientDrift.WithLabelValues(c.Namespace, c.Name).Set(1)
+		if changed = c.Status.SetCondition(hydrav1alpha1.OAuth2ClientConditionInSync, apiv1.ConditionFalse, "Drifted", message); changed {
+			r.recordEvent(c, apiv1.EventTypeWarning, EventReasonDriftDetected, message)
+		}
+	} else {
+		clientDrift.WithLabelValues(c.Namespace, c.Name).Set(0)
+		changed = c.Status.SetCondition(hydrav1alpha1.OAuth2ClientConditionInSync, apiv1.ConditionTrue, "InSync", "")
+	}
+
+	if !changed && !conditionsChanged {
+		return nil
+	}
+	return r.updateClientStatus(ctx, c)
+}
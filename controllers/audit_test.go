@@ -0,0 +1,146 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"testing"
+	"time"
+
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	"github.com/ory/hydra-maester/controllers"
+	"github.com/ory/hydra-maester/pkg/hydraclient"
+	"github.com/stretchr/testify/require"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// readOnlyRegistrar serves the clients registered in ORY Hydra and fails the
+// test on any request writing to it
+type readOnlyRegistrar struct {
+	t       *testing.T
+	clients map[string]*hydraclient.OAuth2ClientJSON
+}
+
+func (r *readOnlyRegistrar) GetOAuth2Client(id string) (*hydraclient.OAuth2ClientJSON, bool, error) {
+	c, ok := r.clients[id]
+	return c, ok, nil
+}
+
+func (r *readOnlyRegistrar) ListOAuth2Client() ([]*hydraclient.OAuth2ClientJSON, error) {
+	var clients []*hydraclient.OAuth2ClientJSON
+	for _, c := range r.clients {
+		clients = append(clients, c)
+	}
+	return clients, nil
+}
+
+func (r *readOnlyRegistrar) PostOAuth2Client(o *hydraclient.OAuth2ClientJSON) (*hydraclient.OAuth2ClientJSON, error) {
+	r.t.Errorf("client of %s was registered in audit mode", o.Owner)
+	return o, nil
+}
+
+func (r *readOnlyRegistrar) PutOAuth2Client(o *hydraclient.OAuth2ClientJSON) (*hydraclient.OAuth2ClientJSON, error) {
+	r.t.Errorf("client of %s was updated in audit mode", o.Owner)
+	return o, nil
+}
+
+func (r *readOnlyRegistrar) DeleteOAuth2Client(id string) error {
+	r.t.Errorf("client %s was deleted in audit mode", id)
+	return nil
+}
+
+func auditedClient(name string) (*hydrav1alpha1.OAuth2Client, *apiv1.Secret) {
+	c := &hydrav1alpha1.OAuth2Client{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Finalizers: []string{controllers.FinalizerName}},
+		Spec: hydrav1alpha1.OAuth2ClientSpec{
+			GrantTypes: []hydrav1alpha1.GrantType{"client_credentials"},
+			Scope:      "read write",
+			SecretName: name + "-secret",
+		},
+		Status: hydrav1alpha1.OAuth2ClientStatus{ClientID: name + "-id"},
+	}
+	secret := &apiv1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name + "-secret", Namespace: "default"},
+		Data: map[string][]byte{
+			controllers.ClientIDKey:     []byte(name + "-id"),
+			controllers.ClientSecretKey: []byte("secret"),
+		},
+	}
+	return c, secret
+}
+
+func TestAuditModeWritesNothing(t *testing.T) {
+
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	require.NoError(t, hydrav1alpha1.AddToScheme(s))
+
+	drifted, driftedSecret := auditedClient("drifted")
+	missing, missingSecret := auditedClient("missing")
+	deleting, deletingSecret := auditedClient("deleting")
+	now := metav1.NewTime(time.Now())
+	deleting.DeletionTimestamp = &now
+	expired, expiredSecret := auditedClient("expired")
+	expired.Spec.NotAfter = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+
+	registered := func(name, scope string) *hydraclient.OAuth2ClientJSON {
+		id := name + "-id"
+		return &hydraclient.OAuth2ClientJSON{
+			ClientID:   &id,
+			GrantTypes: []string{"client_credentials"},
+			Scope:      scope,
+			Owner:      name + "/default",
+		}
+	}
+	hydra := &readOnlyRegistrar{t: t, clients: map[string]*hydraclient.OAuth2ClientJSON{
+		"drifted-id":  registered("drifted", "read"),
+		"deleting-id": registered("deleting", "read write"),
+		"expired-id":  registered("expired", "read write"),
+		"orphan-id":   registeredClient("orphan-id", "deleted/default", true, false),
+	}}
+
+	r := &controllers.OAuth2ClientReconciler{
+		Client: fake.NewFakeClientWithScheme(s, &apiv1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+			drifted, driftedSecret, missing, missingSecret, deleting, deletingSecret, expired, expiredSecret),
+		HydraClient: hydra,
+		AuditMode:   true,
+		Log:         ctrl.Log.WithName("test"),
+	}
+
+	for _, name := range []string{"drifted", "missing", "deleting", "expired", "deleted"} {
+		t.Run("case="+name, func(t *testing.T) {
+			hydra.t = t
+			_, err := r.Reconcile(ctrl.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: "default"}})
+			require.NoError(t, err)
+		})
+	}
+
+	t.Run("case=startup sync", func(t *testing.T) {
+		hydra.t = t
+		sync := &controllers.StartupSync{
+			Reader:      r.Client,
+			HydraClient: hydra,
+			AuditMode:   true,
+			Log:         ctrl.Log.WithName("test"),
+		}
+		require.NoError(t, sync.Start(make(chan struct{})))
+	})
+}
@@ -0,0 +1,77 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ory/hydra-maester/pkg/hydraclient"
+)
+
+// minOperationBudget is the time left in the budget of a reconciliation
+// below which no further operation is started
+const minOperationBudget = time.Second
+
+// budgetExhaustedError is returned when a reconciliation has too little time
+// left to start an operation
+type budgetExhaustedError struct {
+	operation string
+	remaining time.Duration
+}
+
+func (e *budgetExhaustedError) Error() string {
+	return fmt.Sprintf("%s left of the reconcile budget is not enough for %s", e.remaining, e.operation)
+}
+
+// reconcileContext returns the context of a reconciliation, which expires
+// once its budget is spent
+func (r *OAuth2ClientReconciler) reconcileContext() (context.Context, context.CancelFunc) {
+	if r.ReconcileTimeout <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), r.ReconcileTimeout)
+}
+
+// checkBudget returns an error if too little time is left before the
+// deadline of ctx to start operation
+func checkBudget(ctx context.Context, operation string) error {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return nil
+	}
+	if remaining := time.Until(deadline); remaining < minOperationBudget {
+		return &budgetExhaustedError{operation: operation, remaining: remaining.Truncate(time.Millisecond)}
+	}
+	return nil
+}
+
+// isBudgetExhausted reports whether err is caused by a reconciliation
+// running out of time
+func isBudgetExhausted(err error) bool {
+	var exhausted *budgetExhaustedError
+	return errors.As(err, &exhausted) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// withDeadline binds the requests of c to the deadline of ctx, if c supports it
+func withDeadline(ctx context.Context, c ClientRegistrar) ClientRegistrar {
+	if hc, ok := c.(*hydraclient.Client); ok {
+		return hc.WithContext(ctx)
+	}
+	return c
+}
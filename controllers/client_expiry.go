@@ -0,0 +1,74 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	apiv1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// checkClientExpiry sets the Expired condition of c as long as it has not
+// expired. It reports whether the condition has changed, how long it takes
+// for c to expire, and whether it has expired.
+func checkClientExpiry(c *hydrav1alpha1.OAuth2Client) (bool, time.Duration, bool) {
+	expiresAt, ok := c.GetExpiresAt()
+	if !ok {
+		if c.Status.GetCondition(hydrav1alpha1.OAuth2ClientConditionExpired) == nil {
+			return false, 0, false
+		}
+		return c.Status.SetCondition(hydrav1alpha1.OAuth2ClientConditionExpired, apiv1.ConditionFalse, "NoExpiry", ""), 0, false
+	}
+
+	remaining := time.Until(expiresAt)
+	if remaining <= 0 {
+		return false, 0, true
+	}
+	message := fmt.Sprintf("client expires at %s", expiresAt.Format(time.RFC3339))
+	return c.Status.SetCondition(hydrav1alpha1.OAuth2ClientConditionExpired, apiv1.ConditionFalse, "ClientValid", message), remaining, false
+}
+
+// expireOAuth2Client carries out the ExpiryAction of c, which has expired.
+// A disabled client is deleted from ORY Hydra once, it is registered again
+// if its expiry is extended. A client to delete is removed by the finalizer.
+func (r *OAuth2ClientReconciler) expireOAuth2Client(ctx context.Context, c *hydrav1alpha1.OAuth2Client) (ctrl.Result, error) {
+	expiresAt, _ := c.GetExpiresAt()
+	message := fmt.Sprintf("client expired at %s", expiresAt.Format(time.RFC3339))
+
+	// a client protected from deletion is disabled instead
+	if c.GetExpiryAction() == hydrav1alpha1.ExpiryActionDelete && !isDeletionProtected(c) {
+		r.recordEvent(c, apiv1.EventTypeNormal, EventReasonClientExpired, message+", deleting it")
+		return ctrl.Result{}, client.IgnoreNotFound(r.Delete(ctx, c))
+	}
+
+	if c.Status.IsConditionTrue(hydrav1alpha1.OAuth2ClientConditionExpired) {
+		return ctrl.Result{}, nil
+	}
+	if err := r.unregisterOAuth2Clients(ctx, c, false); err != nil {
+		r.recordEvent(c, apiv1.EventTypeWarning, EventReasonClientDeletionFailed, err.Error())
+		return ctrl.Result{}, err
+	}
+	r.recordEvent(c, apiv1.EventTypeNormal, EventReasonClientExpired, message+", deleted it from ORY Hydra")
+
+	c.Status.SetCondition(hydrav1alpha1.OAuth2ClientConditionExpired, apiv1.ConditionTrue, "ClientExpired", message)
+	c.Status.SetCondition(hydrav1alpha1.OAuth2ClientConditionReady, apiv1.ConditionFalse, "ClientExpired", message)
+	return ctrl.Result{}, r.updateClientStatus(ctx, c)
+}
@@ -0,0 +1,49 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	"github.com/ory/hydra-maester/pkg/hydraclient"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+// detectConflict sets the ConflictDetected condition of c. It reports whether
+// fetched has been updated in ORY Hydra since the controller last wrote it.
+func detectConflict(c *hydrav1alpha1.OAuth2Client, fetched *hydraclient.OAuth2ClientJSON) bool {
+	if c.Status.HydraUpdatedAt == "" || fetched == nil || fetched.UpdatedAt == "" || fetched.UpdatedAt == c.Status.HydraUpdatedAt {
+		c.Status.SetCondition(hydrav1alpha1.OAuth2ClientConditionConflictDetected, apiv1.ConditionFalse, "NoConflict", "")
+		return false
+	}
+
+	message := fmt.Sprintf("client was updated in ORY Hydra at %s, after the last update by the controller at %s", fetched.UpdatedAt, c.Status.HydraUpdatedAt)
+	c.Status.SetCondition(hydrav1alpha1.OAuth2ClientConditionConflictDetected, apiv1.ConditionTrue, "UpdatedOutsideController", message)
+	return true
+}
+
+// recordWrite remembers the ID of the client written by the controller and
+// when ORY Hydra updated it, to detect later changes made outside of the
+// controller
+func recordWrite(c *hydrav1alpha1.OAuth2Client, written *hydraclient.OAuth2ClientJSON) {
+	if written != nil {
+		c.Status.HydraUpdatedAt = written.UpdatedAt
+		if written.ClientID != nil {
+			c.Status.ClientID = *written.ClientID
+		}
+	}
+}
@@ -0,0 +1,156 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	"github.com/ory/hydra-maester/pkg/hydraclient"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+// CredentialStore keeps the credentials of clients outside of Kubernetes,
+// e.g. in HashiCorp Vault, in addition to or instead of their Secrets
+type CredentialStore interface {
+	// Read returns the data stored at path, and whether there is any
+	Read(ctx context.Context, path string) (map[string]string, bool, error)
+	Write(ctx context.Context, path string, data map[string]string) error
+	Delete(ctx context.Context, path string) error
+}
+
+// DefaultCredentialStorePath is the template of the paths the credentials
+// of clients are stored at by default
+const DefaultCredentialStorePath = "hydra-maester/{{ .Namespace }}/{{ .Name }}"
+
+var defaultCredentialStorePath = template.Must(template.New("path").Parse(DefaultCredentialStorePath))
+
+// credentialStorePath returns the path the credentials of c are stored at
+func (r *OAuth2ClientReconciler) credentialStorePath(c *hydrav1alpha1.OAuth2Client) (string, error) {
+	pathTemplate := r.CredentialStorePath
+	if pathTemplate == nil {
+		pathTemplate = defaultCredentialStorePath
+	}
+	var path strings.Builder
+	if err := pathTemplate.Execute(&path, c); err != nil {
+		return "", fmt.Errorf("unable to render the credential store path of client %s/%s: %w", c.Namespace, c.Name, err)
+	}
+	return path.String(), nil
+}
+
+// storedSecret returns a Secret holding the credentials of c read from the
+// credential store, if the controller keeps them there only and has stored
+// any. The Secret has no name, as it doesn't exist in Kubernetes.
+func (r *OAuth2ClientReconciler) storedSecret(ctx context.Context, c *hydrav1alpha1.OAuth2Client) (*apiv1.Secret, error) {
+	if !r.CredentialStoreOnly {
+		return nil, nil
+	}
+	return r.readStoredSecret(ctx, c)
+}
+
+// readStoredSecret returns a Secret holding the credentials of c read from
+// the credential store, if it holds any
+func (r *OAuth2ClientReconciler) readStoredSecret(ctx context.Context, c *hydrav1alpha1.OAuth2Client) (*apiv1.Secret, error) {
+	if r.CredentialStore == nil {
+		return nil, nil
+	}
+	path, err := r.credentialStorePath(c)
+	if err != nil {
+		return nil, err
+	}
+	stored, found, err := r.CredentialStore.Read(ctx, path)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	secret := &apiv1.Secret{Data: make(map[string][]byte, len(stored))}
+	secret.Namespace = c.Namespace
+	for key, value := range stored {
+		secret.Data[key] = []byte(value)
+	}
+	return secret, nil
+}
+
+// storeCredentials writes data, the content of the Secret of c, to the
+// credential store unless it is stored already
+func (r *OAuth2ClientReconciler) storeCredentials(ctx context.Context, c *hydrav1alpha1.OAuth2Client, data map[string][]byte) error {
+	if r.CredentialStore == nil {
+		return nil
+	}
+	path, err := r.credentialStorePath(c)
+	if err != nil {
+		return err
+	}
+	if err := checkBudget(ctx, "reading the credential store"); err != nil {
+		return err
+	}
+	stored, found, err := r.CredentialStore.Read(ctx, path)
+	if err != nil {
+		return err
+	}
+
+	desired := make(map[string]string, len(data))
+	for key, value := range data {
+		desired[key] = string(value)
+	}
+	if found && len(stored) == len(desired) {
+		upToDate := true
+		for key, value := range desired {
+			if stored[key] != value {
+				upToDate = false
+				break
+			}
+		}
+		if upToDate {
+			return nil
+		}
+	}
+
+	if err := checkBudget(ctx, "writing the credential store"); err != nil {
+		return err
+	}
+	return r.CredentialStore.Write(ctx, path, desired)
+}
+
+// ensureStoredCredentials keeps the credentials of c in the credential store
+// in line with its Secret. The credentials of Secrets provided by users are
+// not stored.
+func (r *OAuth2ClientReconciler) ensureStoredCredentials(ctx context.Context, c *hydrav1alpha1.OAuth2Client, secret *apiv1.Secret, credentials *hydraclient.Oauth2ClientCredentials) error {
+	if r.CredentialStore == nil || c.IsPublic() || secret.Name != "" && !isOwnedBy(secret, c) {
+		return nil
+	}
+	data, err := r.secretData(c, credentials)
+	if err != nil {
+		return err
+	}
+	return r.storeCredentials(ctx, c, data)
+}
+
+// deleteStoredCredentials deletes the credentials of c from the credential
+// store
+func (r *OAuth2ClientReconciler) deleteStoredCredentials(ctx context.Context, c *hydrav1alpha1.OAuth2Client) error {
+	if r.CredentialStore == nil {
+		return nil
+	}
+	path, err := r.credentialStorePath(c)
+	if err != nil {
+		return err
+	}
+	return r.CredentialStore.Delete(ctx, path)
+}
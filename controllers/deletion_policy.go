@@ -0,0 +1,85 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	apiv1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// orphanOAuth2Client keeps the client in ORY Hydra and the Secret of c, which
+// is being deleted with the Orphan deletion policy. The client is exempted
+// from garbage collection and the Secret is released by c, so that neither
+// is deleted along with it.
+func (r *OAuth2ClientReconciler) orphanOAuth2Client(ctx context.Context, c *hydrav1alpha1.OAuth2Client) error {
+	// as in unregisterOAuth2Clients, there is nothing to keep without it
+	if c.Spec.SecretName == "" {
+		return nil
+	}
+
+	hydra, err := r.getHydraClientForClient(ctx, *c)
+	if err != nil {
+		return err
+	}
+	clients, err := hydra.ListOAuth2Client()
+	if err != nil {
+		return err
+	}
+
+	for _, cJSON := range clients {
+		if cJSON.Owner != fmt.Sprintf("%s/%s", c.Name, c.Namespace) || cJSON.IsGCExempt() {
+			continue
+		}
+		if err := cJSON.SetGCExempt(); err != nil {
+			return err
+		}
+		if _, err := hydra.PutOAuth2Client(cJSON); err != nil {
+			return err
+		}
+	}
+
+	return r.releaseSecret(ctx, c)
+}
+
+// releaseSecret removes the owner reference to c from its Secret, so that
+// the Secret is not garbage collected once c is deleted
+func (r *OAuth2ClientReconciler) releaseSecret(ctx context.Context, c *hydrav1alpha1.OAuth2Client) error {
+	var secret apiv1.Secret
+	if err := r.Get(ctx, types.NamespacedName{Name: c.Spec.SecretName, Namespace: c.Namespace}, &secret); err != nil {
+		if apierrs.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !isOwnedBy(&secret, c) {
+		return nil
+	}
+
+	refs := make([]metav1.OwnerReference, 0, len(secret.OwnerReferences))
+	for _, ref := range secret.OwnerReferences {
+		if ref.UID != c.UID {
+			refs = append(refs, ref)
+		}
+	}
+	secret.OwnerReferences = refs
+	return r.Update(ctx, &secret)
+}
@@ -0,0 +1,68 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// PreventDeletionAnnotation protects the annotated OAuth2Client from being
+// deleted as long as it is set to "true"
+const PreventDeletionAnnotation = "hydra-maester.ory.sh/prevent-deletion"
+
+// DeletionProtectionPath is the path the deletion protection webhook is
+// served on
+const DeletionProtectionPath = "/validate-hydra-ory-sh-oauth2client-deletion"
+
+// isDeletionProtected reports whether the object with the given metadata is
+// protected from deletion
+func isDeletionProtected(meta metav1.Object) bool {
+	return meta.GetAnnotations()[PreventDeletionAnnotation] == "true"
+}
+
+// +kubebuilder:webhook:path=/validate-hydra-ory-sh-oauth2client-deletion,mutating=false,failurePolicy=fail,groups=hydra.ory.sh,resources=oauth2clients,verbs=delete,versions=v1alpha1;v1beta1,name=deletion-protection.oauth2clients.hydra.ory.sh
+
+// DeletionProtector is an admission handler denying the deletion of
+// OAuth2Clients protected by the PreventDeletionAnnotation. Only the metadata
+// of the deleted object is read, so it serves all versions of the API.
+type DeletionProtector struct{}
+
+// Handle denies the deletion of protected OAuth2Clients. Deletions are
+// allowed if the API server doesn't send the deleted object, the finalizer
+// still keeps the client then.
+func (p *DeletionProtector) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1beta1.Delete || len(req.OldObject.Raw) == 0 {
+		return admission.Allowed("")
+	}
+
+	var object struct {
+		Metadata metav1.ObjectMeta `json:"metadata"`
+	}
+	if err := json.Unmarshal(req.OldObject.Raw, &object); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if isDeletionProtected(&object.Metadata) {
+		return admission.Denied(fmt.Sprintf("OAuth2Client %s/%s is protected by the %s annotation, remove it to delete the client", req.Namespace, req.Name, PreventDeletionAnnotation))
+	}
+	return admission.Allowed("")
+}
@@ -0,0 +1,108 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/ory/hydra-maester/controllers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestDeletionProtector(t *testing.T) {
+
+	for name, tc := range map[string]struct {
+		operation admissionv1beta1.Operation
+		oldObject string
+		allowed   bool
+		code      int32
+	}{
+		"protected client": {
+			operation: admissionv1beta1.Delete,
+			oldObject: `{"metadata": {"name": "foo", "annotations": {"hydra-maester.ory.sh/prevent-deletion": "true"}}}`,
+			allowed:   false,
+		},
+		"unprotected client": {
+			operation: admissionv1beta1.Delete,
+			oldObject: `{"metadata": {"name": "foo", "annotations": {"hydra-maester.ory.sh/prevent-deletion": "false"}}}`,
+			allowed:   true,
+		},
+		"missing annotation": {
+			operation: admissionv1beta1.Delete,
+			oldObject: `{"metadata": {"name": "foo", "annotations": {"other": "true"}}}`,
+			allowed:   true,
+		},
+		"no annotations": {
+			operation: admissionv1beta1.Delete,
+			oldObject: `{"metadata": {"name": "foo"}}`,
+			allowed:   true,
+		},
+		"annotation other than true": {
+			operation: admissionv1beta1.Delete,
+			oldObject: `{"metadata": {"name": "foo", "annotations": {"hydra-maester.ory.sh/prevent-deletion": "yes"}}}`,
+			allowed:   true,
+		},
+		"no deleted object": {
+			operation: admissionv1beta1.Delete,
+			allowed:   true,
+		},
+		"unparsable object": {
+			operation: admissionv1beta1.Delete,
+			oldObject: `{"metadata": `,
+			allowed:   false,
+			code:      http.StatusBadRequest,
+		},
+		"unparsable annotations": {
+			operation: admissionv1beta1.Delete,
+			oldObject: `{"metadata": {"name": "foo", "annotations": {"hydra-maester.ory.sh/prevent-deletion": true}}}`,
+			allowed:   false,
+			code:      http.StatusBadRequest,
+		},
+		"update": {
+			operation: admissionv1beta1.Update,
+			oldObject: `{"metadata": {"name": "foo", "annotations": {"hydra-maester.ory.sh/prevent-deletion": "true"}}}`,
+			allowed:   true,
+		},
+	} {
+		t.Run("case="+name, func(t *testing.T) {
+			req := admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{
+				Operation: tc.operation,
+				Name:      "foo",
+				Namespace: "default",
+				OldObject: runtime.RawExtension{Raw: []byte(tc.oldObject)},
+			}}
+
+			resp := (&controllers.DeletionProtector{}).Handle(context.Background(), req)
+
+			assert.Equal(t, tc.allowed, resp.Allowed)
+			if tc.allowed {
+				return
+			}
+			require.NotNil(t, resp.Result)
+			if tc.code != 0 {
+				assert.Equal(t, tc.code, resp.Result.Code)
+			} else {
+				assert.Contains(t, resp.Result.Reason, controllers.PreventDeletionAnnotation)
+			}
+		})
+	}
+}
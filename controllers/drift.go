@@ -0,0 +1,58 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	"github.com/ory/hydra-maester/pkg/hydraclient"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+// hasDrifted reports whether the client fetched from ORY Hydra no longer
+// matches c
+func hasDrifted(c *hydrav1alpha1.OAuth2Client, credentials *hydraclient.Oauth2ClientCredentials, fetched *hydraclient.OAuth2ClientJSON) (bool, error) {
+	desired, err := desiredOAuth2Client(c, credentials, fetched)
+	if err != nil {
+		return false, err
+	}
+	return desired.DiffersFrom(fetched)
+}
+
+// wasRegistered reports whether c has been registered in ORY Hydra in its
+// current generation
+func wasRegistered(c *hydrav1alpha1.OAuth2Client) bool {
+	return c.Generation == c.Status.ObservedGeneration && c.Status.ReconciliationError.Code == ""
+}
+
+// requeueAfter returns the shortest non-zero duration
+func requeueAfter(durations ...time.Duration) time.Duration {
+	var shortest time.Duration
+	for _, d := range durations {
+		if d > 0 && (shortest == 0 || d < shortest) {
+			shortest = d
+		}
+	}
+	return shortest
+}
+
+// recordDriftCorrected emits an event if the drift of c has been corrected
+func (r *OAuth2ClientReconciler) recordDriftCorrected(c *hydrav1alpha1.OAuth2Client, message string) {
+	if c.Status.ReconciliationError.Code == "" {
+		r.recordEvent(c, apiv1.EventTypeNormal, EventReasonDriftCorrected, message)
+	}
+}
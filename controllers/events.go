@@ -0,0 +1,77 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"strings"
+
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Reasons of the events emitted for OAuth2Clients. Failed reconciliations
+// emit warnings with a reason derived from their status code, e.g.
+// `ClientRegistrationFailed` for CLIENT_REGISTRATION_FAILED.
+const (
+	EventReasonClientCreated     = "ClientCreated"
+	EventReasonClientUpdated     = "ClientUpdated"
+	EventReasonClientDeleted     = "ClientDeleted"
+	EventReasonSecretCreated     = "SecretCreated"
+	EventReasonDriftCorrected    = "DriftCorrected"
+	EventReasonSecretMigrated    = "SecretMigrated"
+	EventReasonClientExpired     = "ClientExpired"
+	EventReasonClientOrphaned    = "ClientOrphaned"
+	EventReasonSecretRotated     = "SecretRotated"
+	EventReasonSecretRestored    = "SecretRestored"
+	EventReasonSecretReplaced    = "SecretReplaced"
+	EventReasonSecretAdopted     = "SecretAdopted"
+	EventReasonSecretOverwritten = "SecretOverwritten"
+	EventReasonTokensRevoked     = "TokensRevoked"
+
+	EventReasonCredentialsVerified = "CredentialsVerified"
+	EventReasonCredentialsStored   = "CredentialsStored"
+
+	EventReasonClientDeletionFailed = "ClientDeletionFailed"
+	EventReasonHydraRequestFailed   = "HydraRequestFailed"
+	EventReasonSecretNotMigrated    = "SecretNotMigrated"
+
+	EventReasonCredentialsVerificationFailed = "CredentialsVerificationFailed"
+	EventReasonPolicyViolated                = "PolicyViolated"
+	EventReasonDriftDetected                 = "DriftDetected"
+	EventReasonDeletionPrevented             = "DeletionPrevented"
+	EventReasonSecretRotationSkipped         = "SecretRotationSkipped"
+	EventReasonSecretTargetDenied            = "SecretTargetDenied"
+	EventReasonTokenRevocationFailed         = "TokenRevocationFailed"
+)
+
+// recordEvent emits an event for object if the reconciler has a recorder
+func (r *OAuth2ClientReconciler) recordEvent(object runtime.Object, eventType, reason, message string) {
+	if r.Recorder != nil {
+		r.Recorder.Event(object, eventType, reason, message)
+	}
+}
+
+// eventReasonFor returns the reason of the events emitted for reconciliations
+// failing with code, which is the code in CamelCase
+func eventReasonFor(code hydrav1alpha1.StatusCode) string {
+	var reason strings.Builder
+	for _, word := range strings.Split(strings.ToLower(string(code)), "_") {
+		if word != "" {
+			reason.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return reason.String()
+}
@@ -0,0 +1,56 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	"github.com/ory/hydra-maester/pkg/hydraclient"
+	"github.com/pkg/errors"
+	apiv1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// existingCredentials reads the credentials c is to be registered with from
+// the Secret referenced by its ExistingSecretRef, nothing if it has none.
+// Errors other than those of the Kubernetes API mean that the Secret is
+// missing or invalid.
+func (r *OAuth2ClientReconciler) existingCredentials(ctx context.Context, c *hydrav1alpha1.OAuth2Client) (*hydraclient.Oauth2ClientCredentials, error) {
+	ref := c.Spec.ExistingSecretRef
+	if ref == nil {
+		return nil, nil
+	}
+
+	var secret apiv1.Secret
+	if err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: c.Namespace}, &secret); err != nil {
+		if apierrs.IsNotFound(err) {
+			return nil, errors.Errorf("existing secret %s/%s does not exist", c.Namespace, ref.Name)
+		}
+		return nil, err
+	}
+
+	id, found := secret.Data["client_id"]
+	if !found || len(id) == 0 {
+		return nil, errors.Errorf(`existing secret %s/%s has no "client_id" property`, c.Namespace, ref.Name)
+	}
+	password, found := secret.Data["client_secret"]
+	if (!found || len(password) == 0) && c.GetTokenEndpointAuthMethod() != "none" {
+		return nil, errors.Errorf(`existing secret %s/%s has no "client_secret" property`, c.Namespace, ref.Name)
+	}
+	return &hydraclient.Oauth2ClientCredentials{ID: id, Password: password}, nil
+}
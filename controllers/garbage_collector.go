@@ -0,0 +1,155 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	"github.com/ory/hydra-maester/pkg/hydraclient"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// defaultGCInterval is the interval of the garbage collection enabled by
+	// the HydraMaesterConfiguration without an interval of its own
+	defaultGCInterval = time.Hour
+	// gcConfigurationPollInterval is how often the HydraMaesterConfiguration
+	// is checked while the garbage collection is disabled
+	gcConfigurationPollInterval = time.Minute
+)
+
+// GarbageCollector periodically deletes clients created by the controller
+// from ORY Hydra whose OAuth2Client no longer exists, which happens if the
+// deletion of an OAuth2Client was missed. Clients marked as exempt from
+// garbage collection are kept.
+type GarbageCollector struct {
+	// Reader reads OAuth2Clients. It should not be backed by a cache, so
+	// recently created OAuth2Clients are not mistaken for deleted ones.
+	Reader      client.Reader
+	HydraClient ClientRegistrar
+	// Interval is the interval of the garbage collection, which is disabled
+	// if it is 0. The HydraMaesterConfiguration overrides it.
+	Interval time.Duration
+	Log      logr.Logger
+}
+
+// Start implements manager.Runnable
+func (g *GarbageCollector) Start(stop <-chan struct{}) error {
+	for {
+		wait := g.interval()
+		if wait <= 0 {
+			wait = gcConfigurationPollInterval
+		}
+
+		select {
+		case <-stop:
+			return nil
+		case <-time.After(wait):
+			if g.interval() <= 0 {
+				continue
+			}
+			if err := g.collect(context.Background()); err != nil {
+				g.Log.Error(err, "garbage collection of ORY Hydra clients failed")
+			}
+		}
+	}
+}
+
+// interval returns the interval of the garbage collection in effect, or 0
+// if it is disabled
+func (g *GarbageCollector) interval() time.Duration {
+	config, err := readConfiguration(context.Background(), g.Reader)
+	if err != nil {
+		g.Log.Error(err, "unable to read the garbage collection settings, using the defaults")
+		return g.Interval
+	}
+	if config == nil || config.Spec.GarbageCollection == nil || config.Validate() != nil {
+		return g.Interval
+	}
+
+	gc := config.Spec.GarbageCollection
+	interval := g.Interval
+	if gc.Interval != nil {
+		interval = gc.Interval.Duration
+	}
+	if gc.Enabled == nil {
+		return interval
+	}
+	if !*gc.Enabled {
+		return 0
+	}
+	if interval <= 0 {
+		return defaultGCInterval
+	}
+	return interval
+}
+
+func (g *GarbageCollector) collect(ctx context.Context) error {
+	clients, err := g.HydraClient.ListOAuth2Client()
+	if err != nil {
+		return err
+	}
+
+	for _, cJSON := range clients {
+		owner, ok := collectableOwner(cJSON)
+		if !ok {
+			continue
+		}
+
+		var c hydrav1alpha1.OAuth2Client
+		err := g.Reader.Get(ctx, owner, &c)
+		if err == nil {
+			continue
+		}
+		if !apierrs.IsNotFound(err) {
+			return err
+		}
+
+		g.Log.Info(fmt.Sprintf("deleting client %s of deleted OAuth2Client %s", *cJSON.ClientID, owner))
+		if err := g.HydraClient.DeleteOAuth2Client(*cJSON.ClientID); err != nil {
+			return err
+		}
+		garbageCollectedClients.Inc()
+	}
+
+	return nil
+}
+
+// collectableOwner returns the OAuth2Client of a client created by the
+// controller, unless the client is exempt from garbage collection
+func collectableOwner(c *hydraclient.OAuth2ClientJSON) (types.NamespacedName, bool) {
+	if c.ClientID == nil || !c.IsManaged() || c.IsGCExempt() {
+		return types.NamespacedName{}, false
+	}
+	return parseOwner(c.Owner)
+}
+
+// parseOwner parses the owner of a client created by the controller, which
+// has the form name/namespace
+func parseOwner(owner string) (types.NamespacedName, bool) {
+	parts := strings.Split(owner, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return types.NamespacedName{}, false
+	}
+	return types.NamespacedName{Name: parts[0], Namespace: parts[1]}, true
+}
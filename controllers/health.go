@@ -0,0 +1,115 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/ory/hydra-maester/pkg/hydraclient"
+)
+
+// defaultReadinessTimeout bounds the request to ORY Hydra made by each
+// readiness probe, which must answer before the kubelet gives up on it
+const defaultReadinessTimeout = time.Second
+
+// HydraHealthClient checks whether ORY Hydra is ready to serve requests
+type HydraHealthClient interface {
+	CheckReady() error
+}
+
+// HealthProbes serves the liveness probe of the controller on /healthz and
+// its readiness probe on /readyz. The controller is only ready while ORY
+// Hydra is, so that it isn't considered available while it can't register
+// clients.
+type HealthProbes struct {
+	Addr string
+	// Hydra is checked by the readiness probe. If it is nil, the readiness
+	// probe succeeds like the liveness probe.
+	Hydra HydraHealthClient
+	// Timeout bounds the check of ORY Hydra, defaults to one second
+	Timeout time.Duration
+	Log     logr.Logger
+}
+
+// Start implements manager.Runnable
+func (p *HealthProbes) Start(stop <-chan struct{}) error {
+	listener, err := net.Listen("tcp", p.Addr)
+	if err != nil {
+		return fmt.Errorf("unable to listen on %s for health probes: %w", p.Addr, err)
+	}
+
+	server := &http.Server{Handler: p.Handler()}
+	go func() {
+		<-stop
+		server.Close()
+	}()
+
+	p.Log.Info("serving health probes", "addr", listener.Addr().String())
+	if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, the probes
+// are served by all replicas
+func (p *HealthProbes) NeedLeaderElection() bool {
+	return false
+}
+
+// Handler returns the handler serving /healthz and /readyz
+func (p *HealthProbes) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, req *http.Request) {
+		if err := p.checkHydra(req.Context()); err != nil {
+			p.Log.Info("ORY Hydra is not ready", "error", err.Error())
+			http.Error(w, fmt.Sprintf("ORY Hydra is not ready: %v", err), http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	})
+	return mux
+}
+
+func (p *HealthProbes) checkHydra(ctx context.Context) error {
+	if p.Hydra == nil {
+		return nil
+	}
+	timeout := p.Timeout
+	if timeout == 0 {
+		timeout = defaultReadinessTimeout
+	}
+	return checkHydraReady(ctx, p.Hydra, timeout)
+}
+
+// checkHydraReady checks whether ORY Hydra is ready, giving up after timeout
+func checkHydraReady(ctx context.Context, hydra HydraHealthClient, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if hc, ok := hydra.(*hydraclient.Client); ok {
+		hydra = hc.WithContext(ctx)
+	}
+	return hydra.CheckReady()
+}
@@ -0,0 +1,92 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	apiv1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// hydraBackoffBase is how long the reconciliation of a client is
+	// postponed after ORY Hydra was first found unavailable, doubled on each
+	// further failure up to hydraBackoffMax
+	hydraBackoffBase = 5 * time.Second
+	hydraBackoffMax  = 5 * time.Minute
+)
+
+// hydraBackoff counts the consecutive reconciliations of each OAuth2Client
+// which failed because ORY Hydra was unavailable, e.g. while it restarts, so
+// that they are retried less and less often instead of hot-looping
+type hydraBackoff struct {
+	mu       sync.Mutex
+	failures map[types.NamespacedName]int
+}
+
+// next records another failure of the reconciliation of the given client and
+// returns how long to wait before retrying it
+func (b *hydraBackoff) next(key types.NamespacedName) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures == nil {
+		b.failures = map[types.NamespacedName]int{}
+	}
+	delay := hydraBackoffBase
+	for i := 0; i < b.failures[key] && delay < hydraBackoffMax; i++ {
+		delay *= 2
+	}
+	if delay > hydraBackoffMax {
+		delay = hydraBackoffMax
+	}
+	b.failures[key]++
+	return delay
+}
+
+// reset forgets the failures of the given client once it has been
+// reconciled, and reports whether there were any
+func (b *hydraBackoff) reset(key types.NamespacedName) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, failed := b.failures[key]; !failed {
+		return false
+	}
+	delete(b.failures, key)
+	return true
+}
+
+// setHydraAvailable sets the HydraAvailable condition of the OAuth2Client
+// with the given name
+func (r *OAuth2ClientReconciler) setHydraAvailable(ctx context.Context, key types.NamespacedName, status apiv1.ConditionStatus, reason, message string) error {
+	var c hydrav1alpha1.OAuth2Client
+	if err := r.Get(ctx, key, &c); err != nil {
+		if apierrs.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !c.Status.SetCondition(hydrav1alpha1.OAuth2ClientConditionHydraAvailable, status, reason, message) {
+		return nil
+	}
+	return r.updateClientStatus(ctx, &c)
+}
@@ -0,0 +1,262 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	"github.com/ory/hydra-maester/pkg/hydraclient"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	defaultHydraInstancePort = 4445
+
+	// tokenRequestTimeout is how long requests for access tokens to
+	// authenticate to ORY Hydra with may take
+	tokenRequestTimeout = 10 * time.Second
+)
+
+// instanceClient is the client of a HydraInstance along with the version of
+// the configuration it was built from
+type instanceClient struct {
+	version string
+	client  ClientRegistrar
+}
+
+// hydraClientForInstance returns the client of the HydraInstance with the
+// given name. It is rebuilt whenever the HydraInstance or its Secrets change.
+func (r *OAuth2ClientReconciler) hydraClientForInstance(ctx context.Context, name string) (ClientRegistrar, error) {
+	var instance hydrav1alpha1.HydraInstance
+	if err := r.Get(ctx, types.NamespacedName{Name: name}, &instance); err != nil {
+		return nil, fmt.Errorf("unable to get HydraInstance %s: %w", name, err)
+	}
+
+	version := []string{instance.ResourceVersion}
+	var tlsSecret, authSecret *apiv1.Secret
+	if instance.Spec.TLS != nil {
+		secret, err := r.instanceSecret(ctx, &instance, instance.Spec.TLS.SecretRef)
+		if err != nil {
+			return nil, err
+		}
+		tlsSecret = secret
+		version = append(version, secret.ResourceVersion)
+	}
+	if instance.Spec.Auth != nil {
+		secret, err := r.instanceSecret(ctx, &instance, instance.Spec.Auth.SecretRef)
+		if err != nil {
+			return nil, err
+		}
+		authSecret = secret
+		version = append(version, secret.ResourceVersion)
+	}
+
+	r.clientsMu.RLock()
+	cached, ok := r.instanceClients[name]
+	r.clientsMu.RUnlock()
+	if ok && cached.version == strings.Join(version, "/") {
+		return cached.client, nil
+	}
+
+	c, err := newInstanceClient(&instance, tlsSecret, authSecret)
+	if err != nil {
+		return nil, fmt.Errorf("unable to build the client of HydraInstance %s: %w", name, err)
+	}
+
+	r.clientsMu.Lock()
+	defer r.clientsMu.Unlock()
+	if r.instanceClients == nil {
+		r.instanceClients = make(map[string]instanceClient)
+	}
+	r.instanceClients[name] = instanceClient{version: strings.Join(version, "/"), client: c}
+	return c, nil
+}
+
+func (r *OAuth2ClientReconciler) instanceSecret(ctx context.Context, instance *hydrav1alpha1.HydraInstance, ref apiv1.SecretReference) (*apiv1.Secret, error) {
+	var secret apiv1.Secret
+	if err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}, &secret); err != nil {
+		return nil, fmt.Errorf("unable to get Secret %s/%s of HydraInstance %s: %w", ref.Namespace, ref.Name, instance.Name, err)
+	}
+	return &secret, nil
+}
+
+// newInstanceClient builds a client for the ORY Hydra admin API described
+// by instance. Unless its endpoint is set, it is detected from the version
+// ORY Hydra reports, like --hydra-api-version=auto does for the default
+// instance.
+func newInstanceClient(instance *hydrav1alpha1.HydraInstance, tlsSecret, authSecret *apiv1.Secret) (*hydraclient.Client, error) {
+	spec := instance.Spec
+	port, endpoint := spec.Port, spec.Endpoint
+	if port == 0 {
+		port = defaultHydraInstancePort
+	}
+
+	u, err := url.Parse(fmt.Sprintf("%s:%d", spec.URL, port))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse ORY Hydra's URL: %w", err)
+	}
+
+	c := hydraclient.New(*u.ResolveReference(&url.URL{Path: endpoint}),
+		hydraclient.WithHTTPClient(&http.Client{Transport: InstrumentHydraTransport(nil)}),
+		hydraclient.WithForwardedProto(spec.ForwardedProto),
+	)
+
+	if tlsSecret != nil {
+		config := &tls.Config{ServerName: spec.TLS.ServerName}
+		if ca, ok := tlsSecret.Data[apiv1.ServiceAccountRootCAKey]; ok {
+			config.RootCAs = x509.NewCertPool()
+			if !config.RootCAs.AppendCertsFromPEM(ca) {
+				return nil, fmt.Errorf("%s holds no PEM encoded certificates", apiv1.ServiceAccountRootCAKey)
+			}
+		}
+		if cert, ok := tlsSecret.Data[apiv1.TLSCertKey]; ok {
+			pair, err := tls.X509KeyPair(cert, tlsSecret.Data[apiv1.TLSPrivateKeyKey])
+			if err != nil {
+				return nil, fmt.Errorf("unable to load the client certificate: %w", err)
+			}
+			config.Certificates = []tls.Certificate{pair}
+		}
+		c.HTTPClient.Transport = InstrumentHydraTransport(&http.Transport{TLSClientConfig: config})
+	}
+
+	for name, value := range spec.Headers {
+		hydraclient.WithHeaders(http.Header{name: {value}})(c)
+	}
+
+	if authSecret != nil {
+		credentials, err := NewHydraAuthCredentials(authSecret.Data, *spec.Auth)
+		if err != nil {
+			return nil, err
+		}
+		credentials.Option()(c)
+	}
+
+	if endpoint == "" {
+		version, err := getHydraVersion(c, defaultReadinessTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("unable to determine ORY Hydra's version to detect the client endpoint, set endpoint to skip the detection: %w", err)
+		}
+		if endpoint, err = hydraclient.ClientsEndpoint(version); err != nil {
+			return nil, fmt.Errorf("unable to parse ORY Hydra's version %q to detect the client endpoint, set endpoint to skip the detection: %w", version, err)
+		}
+		c.HydraURL = *u.ResolveReference(&url.URL{Path: endpoint})
+	}
+
+	return c, nil
+}
+
+// HydraAuthCredentials are the credentials a client of ORY Hydra
+// authenticates with, either Authorization, sent in Header or, if it is
+// empty, in the Authorization header, or the access tokens of TokenSource
+type HydraAuthCredentials struct {
+	Header        string
+	Authorization string
+	TokenSource   oauth2.TokenSource
+}
+
+// Option returns the option authenticating a client with the credentials
+func (c *HydraAuthCredentials) Option() hydraclient.Option {
+	switch {
+	case c.TokenSource != nil:
+		return hydraclient.WithTokenSource(c.TokenSource)
+	case c.Header != "":
+		return hydraclient.WithAPIKey(c.Header, c.Authorization)
+	default:
+		return hydraclient.WithAuthorization(c.Authorization)
+	}
+}
+
+// NewHydraAuthCredentials returns the credentials of a client of ORY Hydra
+// as configured by auth, from data, the keys of its auth Secret. These are
+// either a token under `token`, sent as a bearer token or, if auth.Header
+// is set, as it is in that header, or a user name and password for basic
+// authentication under `username` and `password`, or if auth.TokenURL is
+// set, the credentials of the OAuth2 client obtaining access tokens under
+// `client_id` and `client_secret`.
+func NewHydraAuthCredentials(data map[string][]byte, auth hydrav1alpha1.HydraInstanceAuth) (*HydraAuthCredentials, error) {
+	if auth.TokenURL != "" {
+		if auth.Header != "" {
+			return nil, fmt.Errorf("access tokens can't be sent in the %s header", auth.Header)
+		}
+		clientID, hasClientID := data[ClientIDKey]
+		if !hasClientID {
+			return nil, fmt.Errorf("the auth Secret must hold %s and %s to obtain access tokens", ClientIDKey, ClientSecretKey)
+		}
+		config := &clientcredentials.Config{
+			ClientID:     string(clientID),
+			ClientSecret: string(data[ClientSecretKey]),
+			TokenURL:     auth.TokenURL,
+			Scopes:       auth.Scopes,
+		}
+		ctx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{Timeout: tokenRequestTimeout})
+		return &HydraAuthCredentials{TokenSource: config.TokenSource(ctx)}, nil
+	}
+
+	token, hasToken := data[apiv1.ServiceAccountTokenKey]
+	username, hasUsername := data[apiv1.BasicAuthUsernameKey]
+	switch {
+	case hasToken && auth.Header != "":
+		return &HydraAuthCredentials{Header: auth.Header, Authorization: string(token)}, nil
+	case auth.Header != "":
+		return nil, fmt.Errorf("the auth Secret must hold %s to be sent in the %s header", apiv1.ServiceAccountTokenKey, auth.Header)
+	case hasToken:
+		return &HydraAuthCredentials{Authorization: "Bearer " + string(token)}, nil
+	case hasUsername:
+		credentials := string(username) + ":" + string(data[apiv1.BasicAuthPasswordKey])
+		return &HydraAuthCredentials{Authorization: "Basic " + base64.StdEncoding.EncodeToString([]byte(credentials))}, nil
+	default:
+		return nil, fmt.Errorf("the auth Secret must hold either %s or %s", apiv1.ServiceAccountTokenKey, apiv1.BasicAuthUsernameKey)
+	}
+}
+
+// hydraInstanceToOAuth2Clients maps HydraInstances to the OAuth2Clients
+// referencing them
+func (r *OAuth2ClientReconciler) hydraInstanceToOAuth2Clients() handler.EventHandler {
+	return &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+			// the clients of deleted or modified instances are rebuilt
+			r.ResetHydraClients()
+
+			var clients hydrav1alpha1.OAuth2ClientList
+			if err := r.List(context.Background(), &clients); err != nil {
+				r.Log.Error(err, "unable to list OAuth2Clients for HydraInstance", "hydrainstance", o.Meta.GetName())
+				return nil
+			}
+
+			var requests []reconcile.Request
+			for _, c := range clients.Items {
+				if r.selects(&c) && c.Spec.HydraInstanceRef != nil && c.Spec.HydraInstanceRef.Name == o.Meta.GetName() {
+					requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: c.Name, Namespace: c.Namespace}})
+				}
+			}
+			return requests
+		}),
+	}
+}
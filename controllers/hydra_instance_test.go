@@ -0,0 +1,74 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewInstanceClientEndpoint(t *testing.T) {
+	for _, tc := range []struct {
+		desc     string
+		endpoint string
+		version  string
+		status   int
+		expected string
+		err      bool
+	}{
+		{desc: "ORY Hydra 1.x", version: "v1.11.10", status: http.StatusOK, expected: "/clients"},
+		{desc: "ORY Hydra 2.x", version: "v2.2.0", status: http.StatusOK, expected: "/admin/clients"},
+		{desc: "endpoint set", endpoint: "/admin/clients", status: http.StatusInternalServerError, expected: "/admin/clients"},
+		{desc: "unparsable version", version: "master", status: http.StatusOK, err: true},
+		{desc: "version unavailable", status: http.StatusServiceUnavailable, err: true},
+	} {
+		t.Run("case="+tc.desc, func(t *testing.T) {
+			h := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				assert.Equal(t, "/version", req.URL.Path)
+				w.WriteHeader(tc.status)
+				fmt.Fprintf(w, `{"version": %q}`, tc.version)
+			}))
+			defer h.Close()
+
+			u, err := url.Parse(h.URL)
+			require.NoError(t, err)
+			port, err := strconv.Atoi(u.Port())
+			require.NoError(t, err)
+
+			instance := &hydrav1alpha1.HydraInstance{Spec: hydrav1alpha1.HydraInstanceSpec{
+				URL:      "http://" + u.Hostname(),
+				Port:     port,
+				Endpoint: tc.endpoint,
+			}}
+
+			c, err := newInstanceClient(instance, nil, nil)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, c.HydraURL.Path)
+		})
+	}
+}
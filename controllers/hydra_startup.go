@@ -0,0 +1,89 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+const (
+	// hydraStartupBackoffBase is how long WaitForHydra waits before checking
+	// ORY Hydra again after its first check failed, doubled on each further
+	// failure up to hydraStartupBackoffMax
+	hydraStartupBackoffBase = time.Second
+	hydraStartupBackoffMax  = 30 * time.Second
+)
+
+// errStopped is returned by retryWithBackoff once stop is closed
+var errStopped = errors.New("stopped")
+
+// WaitForHydra blocks until ORY Hydra is ready, checking it again with an
+// exponential backoff while it isn't, so that the controller doesn't start
+// reconciling, and failing, while ORY Hydra is still starting. It returns an
+// error if ORY Hydra isn't ready after timeout, and nil once stop is closed.
+func WaitForHydra(stop <-chan struct{}, hydra HydraHealthClient, timeout time.Duration, log logr.Logger) error {
+	err := retryWithBackoff(stop, timeout, log, "waiting for ORY Hydra to become ready", func() error {
+		return checkHydraReady(context.Background(), hydra, defaultReadinessTimeout)
+	})
+	switch err {
+	case nil:
+		log.Info("ORY Hydra is ready")
+		return nil
+	case errStopped:
+		return nil
+	default:
+		return fmt.Errorf("ORY Hydra is not ready after %s: %w", timeout, err)
+	}
+}
+
+// retryWithBackoff calls try until it succeeds, waiting
+// hydraStartupBackoffBase after its first failure and doubling the delay up
+// to hydraStartupBackoffMax. It returns the last error of try once timeout
+// elapsed, and errStopped if stop is closed first.
+func retryWithBackoff(stop <-chan struct{}, timeout time.Duration, log logr.Logger, msg string, try func() error) error {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	delay := hydraStartupBackoffBase
+	for {
+		err := try()
+		if err == nil {
+			return nil
+		}
+		log.Info(msg, "error", err.Error(), "retryAfter", delay.String())
+
+		retry := time.NewTimer(delay)
+		select {
+		case <-stop:
+			retry.Stop()
+			return errStopped
+		case <-deadline.C:
+			retry.Stop()
+			return err
+		case <-retry.C:
+		}
+
+		delay *= 2
+		if delay > hydraStartupBackoffMax {
+			delay = hydraStartupBackoffMax
+		}
+	}
+}
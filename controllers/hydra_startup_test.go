@@ -0,0 +1,97 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ory/hydra-maester/controllers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// startingHydra is a HydraHealthClient which becomes ready after failing a
+// number of checks, never if failures is negative
+type startingHydra struct {
+	mu       sync.Mutex
+	failures int
+	checks   int
+}
+
+func (h *startingHydra) CheckReady() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checks++
+	if h.failures < 0 || h.checks <= h.failures {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func (h *startingHydra) checked() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.checks
+}
+
+func TestWaitForHydra(t *testing.T) {
+
+	log := ctrl.Log.WithName("test")
+
+	t.Run("case=ready", func(t *testing.T) {
+		hydra := &startingHydra{}
+		require.NoError(t, controllers.WaitForHydra(make(chan struct{}), hydra, time.Minute, log))
+		assert.Equal(t, 1, hydra.checked())
+	})
+
+	t.Run("case=ready after a retry", func(t *testing.T) {
+		hydra := &startingHydra{failures: 1}
+		start := time.Now()
+		require.NoError(t, controllers.WaitForHydra(make(chan struct{}), hydra, time.Minute, log))
+		assert.Equal(t, 2, hydra.checked())
+		assert.True(t, time.Since(start) >= time.Second, "the check wasn't delayed by the backoff")
+	})
+
+	t.Run("case=timeout", func(t *testing.T) {
+		hydra := &startingHydra{failures: -1}
+		err := controllers.WaitForHydra(make(chan struct{}), hydra, 100*time.Millisecond, log)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "ORY Hydra is not ready after 100ms")
+		assert.Contains(t, err.Error(), "connection refused")
+		assert.Equal(t, 1, hydra.checked())
+	})
+
+	t.Run("case=stopped", func(t *testing.T) {
+		hydra := &startingHydra{failures: -1}
+		stop := make(chan struct{})
+		done := make(chan error)
+		go func() {
+			done <- controllers.WaitForHydra(stop, hydra, time.Minute, log)
+		}()
+		close(stop)
+
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("WaitForHydra didn't return once stopped")
+		}
+	})
+}
@@ -0,0 +1,125 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/ory/hydra-maester/pkg/hydraclient"
+)
+
+const (
+	// MinTestedHydraVersion is the oldest ORY Hydra version the controller is tested against
+	MinTestedHydraVersion = "v1.0.0"
+	// MaxTestedHydraVersion is the first ORY Hydra version the controller is not tested against
+	MaxTestedHydraVersion = "v2.0.0"
+
+	defaultVersionProbeInterval = time.Hour
+)
+
+type HydraVersionClient interface {
+	GetVersion() (string, error)
+}
+
+// DetectHydraClientsEndpoint returns the clients endpoint of ORY Hydra,
+// /clients up to ORY Hydra 1.x and /admin/clients from 2.0, told by the
+// version it reports. While the version can't be fetched, e.g. because ORY
+// Hydra is still starting, it is requested again with the backoff of
+// WaitForHydra, for at most timeout. It returns an empty endpoint once stop
+// is closed.
+func DetectHydraClientsEndpoint(stop <-chan struct{}, hydra HydraVersionClient, timeout time.Duration, log logr.Logger) (string, error) {
+	var version string
+	err := retryWithBackoff(stop, timeout, log, "waiting for ORY Hydra to report its version", func() error {
+		var err error
+		version, err = getHydraVersion(hydra, defaultReadinessTimeout)
+		return err
+	})
+	switch err {
+	case nil:
+	case errStopped:
+		return "", nil
+	default:
+		return "", fmt.Errorf("unable to determine ORY Hydra's version after %s: %w", timeout, err)
+	}
+
+	endpoint, err := hydraclient.ClientsEndpoint(version)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse ORY Hydra's version %q: %w", version, err)
+	}
+	return endpoint, nil
+}
+
+// getHydraVersion fetches the version of ORY Hydra, giving up after timeout
+func getHydraVersion(hydra HydraVersionClient, timeout time.Duration) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if hc, ok := hydra.(*hydraclient.Client); ok {
+		hydra = hc.WithContext(ctx)
+	}
+	return hydra.GetVersion()
+}
+
+// HydraVersionProbe periodically records the version of ORY Hydra in the
+// hydra_maester_hydra_info metric and warns if it is outside of the tested
+// compatibility range
+type HydraVersionProbe struct {
+	Client   HydraVersionClient
+	Interval time.Duration
+	Log      logr.Logger
+}
+
+// Start implements manager.Runnable
+func (p *HydraVersionProbe) Start(stop <-chan struct{}) error {
+	interval := p.Interval
+	if interval == 0 {
+		interval = defaultVersionProbeInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		p.probe()
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *HydraVersionProbe) probe() {
+	version, err := getHydraVersion(p.Client, defaultReadinessTimeout)
+	if err != nil {
+		p.Log.Error(err, "unable to determine ORY Hydra's version")
+		return
+	}
+
+	compatible, err := hydraclient.IsVersionInRange(version, MinTestedHydraVersion, MaxTestedHydraVersion)
+	if err != nil {
+		p.Log.Info(fmt.Sprintf("unable to parse ORY Hydra's version %q, compatibility can't be verified", version))
+	} else if !compatible {
+		p.Log.Info(fmt.Sprintf("ORY Hydra version %s is outside of the tested range [%s, %s)", version, MinTestedHydraVersion, MaxTestedHydraVersion))
+	}
+
+	hydraInfo.Reset()
+	hydraInfo.WithLabelValues(version, strconv.FormatBool(compatible)).Set(1)
+}
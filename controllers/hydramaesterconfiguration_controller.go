@@ -0,0 +1,172 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/go-logr/logr"
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// HydraMaesterConfigurationReconciler checks the HydraMaesterConfigurations
+// and reports in their status whether they are in effect
+type HydraMaesterConfigurationReconciler struct {
+	client.Client
+	Log logr.Logger
+}
+
+// +kubebuilder:rbac:groups=hydra.ory.sh,resources=hydramaesterconfigurations,verbs=get;list;watch
+// +kubebuilder:rbac:groups=hydra.ory.sh,resources=hydramaesterconfigurations/status,verbs=get;update;patch
+
+func (r *HydraMaesterConfigurationReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.Background()
+	_ = r.Log.WithValues("hydramaesterconfiguration", req.NamespacedName)
+
+	var config hydrav1alpha1.HydraMaesterConfiguration
+	if err := r.Get(ctx, req.NamespacedName, &config); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	var reconciliationError hydrav1alpha1.ReconciliationError
+	err := config.Validate()
+	if config.Name != hydrav1alpha1.HydraMaesterConfigurationName {
+		err = fmt.Errorf("only the HydraMaesterConfiguration named %s is read", hydrav1alpha1.HydraMaesterConfigurationName)
+	}
+	if err != nil {
+		reconciliationError = hydrav1alpha1.ReconciliationError{Code: hydrav1alpha1.StatusInvalidSpec, Description: err.Error()}
+	}
+
+	if config.Status.ObservedGeneration == config.Generation && config.Status.ReconciliationError == reconciliationError {
+		return ctrl.Result{}, nil
+	}
+	config.Status.ObservedGeneration = config.Generation
+	config.Status.ReconciliationError = reconciliationError
+	if err := r.Status().Update(ctx, &config); err != nil {
+		r.Log.Error(err, "status update failed for HydraMaesterConfiguration", "name", config.Name)
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+func (r *HydraMaesterConfigurationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&hydrav1alpha1.HydraMaesterConfiguration{}).
+		Complete(r)
+}
+
+// readConfiguration returns the HydraMaesterConfiguration read by the
+// controller, or nil if there is none
+func readConfiguration(ctx context.Context, reader client.Reader) (*hydrav1alpha1.HydraMaesterConfiguration, error) {
+	var config hydrav1alpha1.HydraMaesterConfiguration
+	if err := reader.Get(ctx, types.NamespacedName{Name: hydrav1alpha1.HydraMaesterConfigurationName}, &config); err != nil {
+		if apierrs.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unable to get HydraMaesterConfiguration %s: %w", hydrav1alpha1.HydraMaesterConfigurationName, err)
+	}
+	return &config, nil
+}
+
+// reconcileSettings are the settings of a reconciliation, given by the
+// HydraMaesterConfiguration and falling back to the options of the reconciler
+type reconcileSettings struct {
+	redirectURIAllowPattern *regexp.Regexp
+	strictFields            bool
+	driftDetectionInterval  time.Duration
+	secretTTL               *metav1.Duration
+	jwtAudiences            map[string][]string
+}
+
+// settings returns the settings in effect. An invalid HydraMaesterConfiguration
+// fails the reconciliation rather than being ignored, so its policies are
+// never loosened by mistake.
+func (r *OAuth2ClientReconciler) settings(ctx context.Context) (reconcileSettings, error) {
+	s := reconcileSettings{
+		redirectURIAllowPattern: r.RedirectURIAllowPattern,
+		strictFields:            r.StrictFields,
+		driftDetectionInterval:  r.DriftDetectionInterval,
+	}
+
+	config, err := readConfiguration(ctx, r)
+	if err != nil || config == nil {
+		return s, err
+	}
+	if err := config.Validate(); err != nil {
+		return s, fmt.Errorf("HydraMaesterConfiguration %s is invalid: %w", config.Name, err)
+	}
+
+	if d := config.Spec.Defaults; d != nil {
+		if d.RedirectURIAllowPattern != nil {
+			// the pattern has been checked by Validate
+			s.redirectURIAllowPattern, _ = CompileRedirectURIAllowPattern(*d.RedirectURIAllowPattern)
+		}
+		s.jwtAudiences = d.JWTAudiences
+	}
+	if p := config.Spec.Policies; p != nil {
+		if p.StrictFields != nil {
+			s.strictFields = *p.StrictFields
+		}
+		if p.DriftDetectionInterval != nil {
+			s.driftDetectionInterval = p.DriftDetectionInterval.Duration
+		}
+	}
+	if rotation := config.Spec.Rotation; rotation != nil {
+		s.secretTTL = rotation.SecretTTL
+	}
+	return s, nil
+}
+
+// configurationToOAuth2Clients maps the HydraMaesterConfiguration to all
+// OAuth2Clients, so that its changes take effect right away
+func (r *OAuth2ClientReconciler) configurationToOAuth2Clients() handler.EventHandler {
+	return &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+			if o.Meta.GetName() != hydrav1alpha1.HydraMaesterConfigurationName {
+				return nil
+			}
+			return r.allOAuth2ClientRequests("hydramaesterconfiguration", o.Meta.GetName())
+		}),
+	}
+}
+
+// allOAuth2ClientRequests returns the requests reconciling all OAuth2Clients
+// because of a change of the named object of the given kind
+func (r *OAuth2ClientReconciler) allOAuth2ClientRequests(kind, name string) []reconcile.Request {
+	var clients hydrav1alpha1.OAuth2ClientList
+	if err := r.List(context.Background(), &clients); err != nil {
+		r.Log.Error(err, "unable to list OAuth2Clients", kind, name)
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(clients.Items))
+	for _, c := range clients.Items {
+		if r.selects(&c) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: c.Name, Namespace: c.Namespace}})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,257 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	"github.com/ory/hydra-maester/pkg/hydraclient"
+	apiv1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// retainedKeys is the number of keys generated by the controller kept in a
+// set: the current key and the previous one, so that signatures made before
+// a rotation can still be verified
+const retainedKeys = 2
+
+// EventReasonKeyGenerated is the reason of the events emitted for
+// JsonWebKeySets when a key has been generated
+const EventReasonKeyGenerated = "KeyGenerated"
+
+// HydraKeysClient manages JSON web key sets in ORY Hydra
+type HydraKeysClient interface {
+	GetJSONWebKeySet(set string) (*hydraclient.JSONWebKeySet, bool, error)
+	GenerateJSONWebKey(set string, r *hydraclient.JSONWebKeyGeneratorRequest) (*hydraclient.JSONWebKeySet, error)
+	DeleteJSONWebKey(set, kid string) error
+	DeleteJSONWebKeySet(set string) error
+}
+
+// JsonWebKeySetReconciler reconciles a JsonWebKeySet object
+type JsonWebKeySetReconciler struct {
+	client.Client
+	HydraClient HydraKeysClient
+	Recorder    record.EventRecorder
+	Log         logr.Logger
+}
+
+// +kubebuilder:rbac:groups=hydra.ory.sh,resources=jsonwebkeysets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=hydra.ory.sh,resources=jsonwebkeysets/status,verbs=get;update;patch
+
+func (r *JsonWebKeySetReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.Background()
+	_ = r.Log.WithValues("jsonwebkeyset", req.NamespacedName)
+
+	var keySet hydrav1alpha1.JsonWebKeySet
+	if err := r.Get(ctx, req.NamespacedName, &keySet); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !keySet.DeletionTimestamp.IsZero() {
+		if !containsString(keySet.Finalizers, FinalizerName) {
+			return ctrl.Result{}, nil
+		}
+		if err := r.HydraClient.DeleteJSONWebKeySet(keySet.GetSetName()); err != nil {
+			return ctrl.Result{}, err
+		}
+		keySet.Finalizers = removeString(keySet.Finalizers, FinalizerName)
+		return ctrl.Result{}, r.Update(ctx, &keySet)
+	}
+	if !containsString(keySet.Finalizers, FinalizerName) {
+		keySet.Finalizers = append(keySet.Finalizers, FinalizerName)
+		if err := r.Update(ctx, &keySet); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	current, found, err := r.HydraClient.GetJSONWebKeySet(keySet.GetSetName())
+	if err != nil {
+		return ctrl.Result{}, r.updateStatusError(ctx, &keySet, hydrav1alpha1.StatusKeyGenerationFailed, err)
+	}
+
+	if !found || needsRotation(&keySet, current) {
+		if current, err = r.rotate(&keySet, current); err != nil {
+			return ctrl.Result{}, r.updateStatusError(ctx, &keySet, hydrav1alpha1.StatusKeyGenerationFailed, err)
+		}
+	}
+
+	if err := r.ensureSecret(ctx, &keySet, current); err != nil {
+		return ctrl.Result{}, r.updateStatusError(ctx, &keySet, hydrav1alpha1.StatusCreateSecretFailed, err)
+	}
+
+	keySet.Status.ObservedGeneration = keySet.Generation
+	keySet.Status.ReconciliationError = hydrav1alpha1.ReconciliationError{}
+	if err := r.Status().Update(ctx, &keySet); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if interval := keySet.Spec.RotationInterval; interval != nil {
+		return ctrl.Result{RequeueAfter: time.Until(keySet.Status.RotatedAt.Add(interval.Duration))}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+func (r *JsonWebKeySetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&hydrav1alpha1.JsonWebKeySet{}).
+		Owns(&apiv1.Secret{}).
+		Complete(r)
+}
+
+// needsRotation reports whether a new key has to be generated in the set,
+// because the current key is gone or no longer matches the spec, or its
+// rotation interval has passed
+func needsRotation(k *hydrav1alpha1.JsonWebKeySet, current *hydraclient.JSONWebKeySet) bool {
+	if len(k.Status.KeyIDs) == 0 || k.Status.RotatedAt == nil {
+		return true
+	}
+
+	key := findKey(current, k.Status.KeyIDs[0])
+	if key == nil || key.Algorithm != string(k.Spec.Algorithm) || key.Use != string(k.GetUse()) {
+		return true
+	}
+
+	interval := k.Spec.RotationInterval
+	return interval != nil && time.Since(k.Status.RotatedAt.Time) >= interval.Duration
+}
+
+// findKey returns the key of the set with the given ID, or nil if there is
+// none
+func findKey(set *hydraclient.JSONWebKeySet, kid string) *hydraclient.JSONWebKey {
+	if set == nil {
+		return nil
+	}
+	for i := range set.Keys {
+		if set.Keys[i].BaseKeyID() == kid {
+			return &set.Keys[i]
+		}
+	}
+	return nil
+}
+
+// rotate generates a new key in the set of k and deletes the keys which are
+// no longer retained. It returns the resulting set.
+func (r *JsonWebKeySetReconciler) rotate(k *hydrav1alpha1.JsonWebKeySet, current *hydraclient.JSONWebKeySet) (*hydraclient.JSONWebKeySet, error) {
+	kid := string(uuid.NewUUID())
+	if _, err := r.HydraClient.GenerateJSONWebKey(k.GetSetName(), &hydraclient.JSONWebKeyGeneratorRequest{
+		Algorithm: string(k.Spec.Algorithm),
+		KeyID:     kid,
+		Use:       string(k.GetUse()),
+	}); err != nil {
+		return nil, err
+	}
+
+	now := metav1.Now()
+	k.Status.RotatedAt = &now
+	keyIDs := []string{kid}
+	for _, old := range k.Status.KeyIDs {
+		if findKey(current, old) == nil {
+			continue
+		}
+		if len(keyIDs) < retainedKeys {
+			keyIDs = append(keyIDs, old)
+			continue
+		}
+		for _, key := range current.Keys {
+			if key.BaseKeyID() != old {
+				continue
+			}
+			if err := r.HydraClient.DeleteJSONWebKey(k.GetSetName(), key.KeyID); err != nil {
+				return nil, err
+			}
+		}
+	}
+	k.Status.KeyIDs = keyIDs
+
+	if r.Recorder != nil {
+		r.Recorder.Event(k, apiv1.EventTypeNormal, EventReasonKeyGenerated, fmt.Sprintf("generated key %s in set %s", kid, k.GetSetName()))
+	}
+
+	rotated, found, err := r.HydraClient.GetJSONWebKeySet(k.GetSetName())
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("key set %s does not exist after generating key %s", k.GetSetName(), kid)
+	}
+	return rotated, nil
+}
+
+// ensureSecret writes the set to the Secret of k. Secrets which aren't owned
+// by k are left untouched.
+func (r *JsonWebKeySetReconciler) ensureSecret(ctx context.Context, k *hydrav1alpha1.JsonWebKeySet, set *hydraclient.JSONWebKeySet) error {
+	data, err := json.Marshal(set)
+	if err != nil {
+		return err
+	}
+
+	var secret apiv1.Secret
+	err = r.Get(ctx, types.NamespacedName{Name: k.Spec.SecretName, Namespace: k.Namespace}, &secret)
+	if apierrs.IsNotFound(err) {
+		controller := true
+		secret = apiv1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      k.Spec.SecretName,
+				Namespace: k.Namespace,
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: hydrav1alpha1.GroupVersion.String(),
+					Kind:       "JsonWebKeySet",
+					Name:       k.Name,
+					UID:        k.UID,
+					Controller: &controller,
+				}},
+			},
+			Data: map[string][]byte{hydrav1alpha1.JSONWebKeySetSecretKey: data},
+		}
+		return r.Create(ctx, &secret)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !metav1.IsControlledBy(&secret, k) {
+		return fmt.Errorf("secret %s/%s is not owned by the key set", secret.Namespace, secret.Name)
+	}
+	if bytes.Equal(secret.Data[hydrav1alpha1.JSONWebKeySetSecretKey], data) {
+		return nil
+	}
+	secret.Data = map[string][]byte{hydrav1alpha1.JSONWebKeySetSecretKey: data}
+	return r.Update(ctx, &secret)
+}
+
+func (r *JsonWebKeySetReconciler) updateStatusError(ctx context.Context, k *hydrav1alpha1.JsonWebKeySet, code hydrav1alpha1.StatusCode, err error) error {
+	r.Log.Error(err, fmt.Sprintf("error processing key set %s/%s", k.Namespace, k.Name))
+	k.Status.ReconciliationError = hydrav1alpha1.ReconciliationError{
+		Code:        code,
+		Description: err.Error(),
+	}
+	if updateErr := r.Status().Update(ctx, k); updateErr != nil {
+		return updateErr
+	}
+	return err
+}
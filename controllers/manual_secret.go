@@ -0,0 +1,90 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// ManualSecretAnnotation marks the Secret of a client as holding a client
+// secret set by an operator. The secret is pushed to ORY Hydra and automatic
+// rotation of the client secret is suspended until the annotation is removed.
+const ManualSecretAnnotation = "hydra-maester.ory.sh/manual-secret"
+
+func isManualSecret(secret *apiv1.Secret) bool {
+	return secret.Annotations[ManualSecretAnnotation] == "true"
+}
+
+// trackManualSecret records in the status of c whether its credentials are
+// set manually. It reports whether a manual secret has been set, changed or
+// cleared since the last time it was pushed to ORY Hydra.
+func trackManualSecret(c *hydrav1alpha1.OAuth2Client, secret *apiv1.Secret) bool {
+	if !isManualSecret(secret) {
+		if c.Status.ManualSecretVersion == "" {
+			return false
+		}
+		c.Status.ManualSecretVersion = ""
+		c.Status.SetCondition(hydrav1alpha1.OAuth2ClientConditionManualSecret, apiv1.ConditionFalse, "ManualSecretCleared", "")
+		return true
+	}
+
+	if c.Status.ManualSecretVersion == secret.ResourceVersion {
+		return false
+	}
+	c.Status.ManualSecretVersion = secret.ResourceVersion
+	c.Status.SetCondition(hydrav1alpha1.OAuth2ClientConditionManualSecret, apiv1.ConditionTrue, "ManualSecretSet",
+		"client secret is set manually, automatic rotation is suspended")
+	return true
+}
+
+// hasManualSecret reports whether the client secret of c is set manually
+func hasManualSecret(c *hydrav1alpha1.OAuth2Client) bool {
+	return c.Status.ManualSecretVersion != ""
+}
+
+// secretToOAuth2Clients maps Secrets to the OAuth2Clients using them
+func (r *OAuth2ClientReconciler) secretToOAuth2Clients() handler.EventHandler {
+	return &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+			var clients hydrav1alpha1.OAuth2ClientList
+			if err := r.List(context.Background(), &clients, client.InNamespace(o.Meta.GetNamespace())); err != nil {
+				r.Log.Error(err, "unable to list OAuth2Clients for secret", "secret", o.Meta.GetName())
+				return nil
+			}
+
+			// copies kept for SecretTargets belong to a client in another
+			// namespace
+			if labels := o.Meta.GetLabels(); labels[OAuth2ClientNamespaceLabel] != "" {
+				return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: labels[OAuth2ClientLabel], Namespace: labels[OAuth2ClientNamespaceLabel]}}}
+			}
+
+			var requests []reconcile.Request
+			for _, c := range clients.Items {
+				if r.selects(&c) && (c.Spec.SecretName == o.Meta.GetName() || c.Spec.ExistingSecretRef != nil && c.Spec.ExistingSecretRef.Name == o.Meta.GetName()) {
+					requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: c.Name, Namespace: c.Namespace}})
+				}
+			}
+			return requests
+		}),
+	}
+}
@@ -0,0 +1,163 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/ory/hydra-maester/pkg/tracing"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const metricsNamespace = "hydra_maester"
+
+var (
+	hydraInfo = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "hydra_info",
+		Help:      "Version of the ORY Hydra instance the controller talks to, and whether it is within the tested compatibility range.",
+	}, []string{"version", "compatible"})
+
+	suppressedSyncs = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "suppressed_syncs_total",
+		Help:      "Number of OAuth2Client updates ignored because their content did not change.",
+	})
+
+	garbageCollectedClients = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "garbage_collected_clients_total",
+		Help:      "Number of clients deleted from ORY Hydra because their OAuth2Client no longer exists.",
+	})
+
+	clientDrift = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "client_drift",
+		Help:      "Whether an OAuth2Client differs from its client in ORY Hydra, only reported in audit mode.",
+	}, []string{"namespace", "name"})
+
+	reconcileLag = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "reconcile_lag_seconds",
+		Help:      "Time OAuth2Clients waited in the queue between a watch event and the start of their reconciliation, per namespace.",
+		Buckets:   []float64{0.01, 0.05, 0.1, 0.5, 1, 5, 10, 30, 60, 300},
+	}, []string{"namespace"})
+
+	managedClientsGauge = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "managed_clients",
+		Help:      "Number of OAuth2Clients managed by the controller.",
+	}, func() float64 {
+		return float64(managedClients.len())
+	})
+
+	reconciles = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "reconciles_total",
+		Help:      "Number of reconciliations of OAuth2Clients, by result.",
+	}, []string{"result"})
+
+	reconcileErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "reconcile_errors_total",
+		Help:      "Number of errors recorded in the status of OAuth2Clients, by status code.",
+	}, []string{"code"})
+
+	hydraRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "hydra_requests_total",
+		Help:      "Number of requests to the admin API of ORY Hydra, by HTTP method and status code.",
+	}, []string{"method", "code"})
+
+	secretsCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "secrets_created_total",
+		Help:      "Number of Secrets created with the credentials of OAuth2Clients.",
+	})
+
+	secretRotations = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "secret_rotations_total",
+		Help:      "Number of client secrets rotated in ORY Hydra.",
+	})
+
+	oldestPendingReconcile = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "oldest_pending_reconcile_seconds",
+		Help:      "Age of the oldest OAuth2Client waiting in the queue to be reconciled.",
+	}, func() float64 {
+		return pendingReconciles.oldest().Seconds()
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		hydraInfo,
+		suppressedSyncs,
+		garbageCollectedClients,
+		clientDrift,
+		reconcileLag,
+		oldestPendingReconcile,
+		managedClientsGauge,
+		reconciles,
+		reconcileErrors,
+		hydraRequests,
+		secretsCreated,
+		secretRotations,
+	)
+}
+
+// InstrumentHydraTransport wraps the transport of the HTTP client talking to
+// ORY Hydra, so that its requests are counted in the
+// hydra_maester_hydra_requests_total metric and traced. A nil transport
+// stands for http.DefaultTransport.
+func InstrumentHydraTransport(transport http.RoundTripper) http.RoundTripper {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return tracing.Transport(promhttp.InstrumentRoundTripperCounter(hydraRequests, transport))
+}
+
+// managedClients holds the OAuth2Clients the controller manages, counted by
+// the hydra_maester_managed_clients metric
+var managedClients = &clientSet{keys: map[types.NamespacedName]struct{}{}}
+
+type clientSet struct {
+	mu   sync.Mutex
+	keys map[types.NamespacedName]struct{}
+}
+
+func (s *clientSet) add(key types.NamespacedName) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys[key] = struct{}{}
+}
+
+func (s *clientSet) remove(key types.NamespacedName) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.keys, key)
+}
+
+func (s *clientSet) len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.keys)
+}
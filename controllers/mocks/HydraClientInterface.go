@@ -2,7 +2,7 @@
 
 package mocks
 
-import hydra "github.com/ory/hydra-maester/hydra"
+import hydraclient "github.com/ory/hydra-maester/pkg/hydraclient"
 import mock "github.com/stretchr/testify/mock"
 
 // HydraClientInterface is an autogenerated mock type for the HydraClientInterface type
@@ -25,15 +25,15 @@ func (_m *HydraClientInterface) DeleteOAuth2Client(id string) error {
 }
 
 // GetOAuth2Client provides a mock function with given fields: id
-func (_m *HydraClientInterface) GetOAuth2Client(id string) (*hydra.OAuth2ClientJSON, bool, error) {
+func (_m *HydraClientInterface) GetOAuth2Client(id string) (*hydraclient.OAuth2ClientJSON, bool, error) {
 	ret := _m.Called(id)
 
-	var r0 *hydra.OAuth2ClientJSON
-	if rf, ok := ret.Get(0).(func(string) *hydra.OAuth2ClientJSON); ok {
+	var r0 *hydraclient.OAuth2ClientJSON
+	if rf, ok := ret.Get(0).(func(string) *hydraclient.OAuth2ClientJSON); ok {
 		r0 = rf(id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*hydra.OAuth2ClientJSON)
+			r0 = ret.Get(0).(*hydraclient.OAuth2ClientJSON)
 		}
 	}
 
@@ -55,15 +55,15 @@ func (_m *HydraClientInterface) GetOAuth2Client(id string) (*hydra.OAuth2ClientJ
 }
 
 // ListOAuth2Client provides a mock function with given fields:
-func (_m *HydraClientInterface) ListOAuth2Client() ([]*hydra.OAuth2ClientJSON, error) {
+func (_m *HydraClientInterface) ListOAuth2Client() ([]*hydraclient.OAuth2ClientJSON, error) {
 	ret := _m.Called()
 
-	var r0 []*hydra.OAuth2ClientJSON
-	if rf, ok := ret.Get(0).(func() []*hydra.OAuth2ClientJSON); ok {
+	var r0 []*hydraclient.OAuth2ClientJSON
+	if rf, ok := ret.Get(0).(func() []*hydraclient.OAuth2ClientJSON); ok {
 		r0 = rf()
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*hydra.OAuth2ClientJSON)
+			r0 = ret.Get(0).([]*hydraclient.OAuth2ClientJSON)
 		}
 	}
 
@@ -78,20 +78,20 @@ func (_m *HydraClientInterface) ListOAuth2Client() ([]*hydra.OAuth2ClientJSON, e
 }
 
 // PostOAuth2Client provides a mock function with given fields: o
-func (_m *HydraClientInterface) PostOAuth2Client(o *hydra.OAuth2ClientJSON) (*hydra.OAuth2ClientJSON, error) {
+func (_m *HydraClientInterface) PostOAuth2Client(o *hydraclient.OAuth2ClientJSON) (*hydraclient.OAuth2ClientJSON, error) {
 	ret := _m.Called(o)
 
-	var r0 *hydra.OAuth2ClientJSON
-	if rf, ok := ret.Get(0).(func(*hydra.OAuth2ClientJSON) *hydra.OAuth2ClientJSON); ok {
+	var r0 *hydraclient.OAuth2ClientJSON
+	if rf, ok := ret.Get(0).(func(*hydraclient.OAuth2ClientJSON) *hydraclient.OAuth2ClientJSON); ok {
 		r0 = rf(o)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*hydra.OAuth2ClientJSON)
+			r0 = ret.Get(0).(*hydraclient.OAuth2ClientJSON)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(*hydra.OAuth2ClientJSON) error); ok {
+	if rf, ok := ret.Get(1).(func(*hydraclient.OAuth2ClientJSON) error); ok {
 		r1 = rf(o)
 	} else {
 		r1 = ret.Error(1)
@@ -101,20 +101,20 @@ func (_m *HydraClientInterface) PostOAuth2Client(o *hydra.OAuth2ClientJSON) (*hy
 }
 
 // PutOAuth2Client provides a mock function with given fields: o
-func (_m *HydraClientInterface) PutOAuth2Client(o *hydra.OAuth2ClientJSON) (*hydra.OAuth2ClientJSON, error) {
+func (_m *HydraClientInterface) PutOAuth2Client(o *hydraclient.OAuth2ClientJSON) (*hydraclient.OAuth2ClientJSON, error) {
 	ret := _m.Called(o)
 
-	var r0 *hydra.OAuth2ClientJSON
-	if rf, ok := ret.Get(0).(func(*hydra.OAuth2ClientJSON) *hydra.OAuth2ClientJSON); ok {
+	var r0 *hydraclient.OAuth2ClientJSON
+	if rf, ok := ret.Get(0).(func(*hydraclient.OAuth2ClientJSON) *hydraclient.OAuth2ClientJSON); ok {
 		r0 = rf(o)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*hydra.OAuth2ClientJSON)
+			r0 = ret.Get(0).(*hydraclient.OAuth2ClientJSON)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(*hydra.OAuth2ClientJSON) error); ok {
+	if rf, ok := ret.Get(1).(func(*hydraclient.OAuth2ClientJSON) error); ok {
 		r1 = rf(o)
 	} else {
 		r1 = ret.Error(1)
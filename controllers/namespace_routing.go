@@ -0,0 +1,48 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// AdminURLAnnotation overrides the URL of the default ORY Hydra admin API for
+// all OAuth2Clients in the annotated namespace which don't set their own
+const AdminURLAnnotation = "hydra-maester.ory.sh/admin-url"
+
+// namespaceAdminURL returns the URL of the ORY Hydra admin API set for the
+// given namespace, or an empty string if the namespace isn't annotated
+func (r *OAuth2ClientReconciler) namespaceAdminURL(ctx context.Context, namespace string) (string, error) {
+	var ns apiv1.Namespace
+	if err := r.Get(ctx, types.NamespacedName{Name: namespace}, &ns); err != nil {
+		return "", err
+	}
+
+	adminURL, ok := ns.Annotations[AdminURLAnnotation]
+	if !ok {
+		return "", nil
+	}
+	u, err := url.Parse(adminURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("invalid %s annotation on namespace %s: %s is not an http(s) URL", AdminURLAnnotation, namespace, adminURL)
+	}
+	return adminURL, nil
+}
@@ -18,26 +18,45 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
 
 	"github.com/go-logr/logr"
 	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
-	"github.com/ory/hydra-maester/hydra"
+	"github.com/ory/hydra-maester/pkg/hydraclient"
+	"github.com/ory/hydra-maester/pkg/tracing"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	batchv1 "k8s.io/api/batch/v1"
 	apiv1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 const (
 	ClientIDKey     = "client_id"
 	ClientSecretKey = "client_secret"
 	FinalizerName   = "finalizer.ory.hydra.sh"
+
+	deviceAuthorizationPath = "/oauth2/device/auth"
+	deviceVerificationPath  = "/oauth2/device/verify"
 )
 
-type HydraClientMakerFunc func(hydrav1alpha1.OAuth2ClientSpec) (HydraClientInterface, error)
+type HydraClientMakerFunc func(hydrav1alpha1.OAuth2ClientSpec) (ClientRegistrar, error)
 
 type clientMapKey struct {
 	url            string
@@ -46,48 +65,177 @@ type clientMapKey struct {
 	forwardedProto string
 }
 
-type HydraClientInterface interface {
-	GetOAuth2Client(id string) (*hydra.OAuth2ClientJSON, bool, error)
-	ListOAuth2Client() ([]*hydra.OAuth2ClientJSON, error)
-	PostOAuth2Client(o *hydra.OAuth2ClientJSON) (*hydra.OAuth2ClientJSON, error)
-	PutOAuth2Client(o *hydra.OAuth2ClientJSON) (*hydra.OAuth2ClientJSON, error)
-	DeleteOAuth2Client(id string) error
-}
-
 // OAuth2ClientReconciler reconciles a OAuth2Client object
 type OAuth2ClientReconciler struct {
-	HydraClient      HydraClientInterface
+	HydraClient      ClientRegistrar
 	HydraClientMaker HydraClientMakerFunc
-	Log              logr.Logger
-	otherClients     map[clientMapKey]HydraClientInterface
+	// HydraPublicURL is ORY Hydra's public address, used to derive the
+	// endpoints reported in the status of device flow clients
+	HydraPublicURL string
+	// RedirectURIAllowPattern, if set, must match all redirect URIs of
+	// clients in namespaces which don't override it
+	RedirectURIAllowPattern *regexp.Regexp
+	// DriftDetectionInterval, if set, is the interval at which registered
+	// clients are compared with ORY Hydra and restored if they were modified
+	// or deleted outside of the controller
+	DriftDetectionInterval time.Duration
+	// ReconcileTimeout, if set, is the time budget of a reconciliation,
+	// shared by all its requests to ORY Hydra and Kubernetes
+	ReconcileTimeout time.Duration
+	// MaxConcurrentReconciles is the number of OAuth2Clients reconciled in
+	// parallel, one if not set
+	MaxConcurrentReconciles int
+	// StrictFields, if set, fails the reconciliation of clients whose last
+	// applied manifest has spec fields unknown to the controller
+	StrictFields bool
+	// VerificationImage, if set, is the image of the Jobs spawned to verify
+	// that a token can be obtained with the credentials of clients' Secrets
+	VerificationImage string
+	// AuditMode, if set, only compares clients with ORY Hydra and reports
+	// their drift, the controller neither registers, updates nor deletes
+	// clients in ORY Hydra, and leaves their Secrets and finalizers alone
+	AuditMode bool
+	// LabelSelector, if set, restricts the controller to the OAuth2Clients
+	// matching it, so that several deployments can share them out
+	LabelSelector labels.Selector
+	// WatchNamespaces, if set, are the only namespaces whose OAuth2Clients
+	// are reconciled
+	WatchNamespaces []string
+	// ExcludeNamespaces are namespaces whose OAuth2Clients are never
+	// reconciled, even if they are in WatchNamespaces
+	ExcludeNamespaces []string
+	// SecretClientIDKey and SecretClientSecretKey, if set, replace client_id
+	// and client_secret as the keys of the Secrets of clients without a
+	// SecretProjection, e.g. CLIENT_ID and CLIENT_SECRET for envFrom
+	SecretClientIDKey     string
+	SecretClientSecretKey string
+	// CredentialStore, if set, also keeps the credentials of the clients
+	// whose Secrets are generated by the controller, at the paths rendered
+	// from CredentialStorePath for each OAuth2Client
+	CredentialStore     CredentialStore
+	CredentialStorePath *template.Template
+	// CredentialStoreOnly, if set, keeps generated credentials in the
+	// CredentialStore only, no Secret is created for them
+	CredentialStoreOnly bool
+	// ClientSecretPolicy, if set, defines the client secrets generated by
+	// the controller on rotations, and on registrations if
+	// GenerateClientSecrets is set instead of leaving it to ORY Hydra
+	ClientSecretPolicy    *ClientSecretPolicy
+	GenerateClientSecrets bool
+	Recorder              record.EventRecorder
+	Log                   logr.Logger
+
+	// otherClients holds the clients for ORY Hydra instances other than the
+	// default one, built lazily by HydraClientMaker and guarded by clientsMu
+	otherClients map[clientMapKey]ClientRegistrar
+	// instanceClients holds the clients for HydraInstances by their name,
+	// also guarded by clientsMu
+	instanceClients map[string]instanceClient
+	clientsMu       sync.RWMutex
+
+	// deletedClients remembers the clients recently deleted from ORY Hydra
+	deletedClients tombstones
+	// hydraBackoff postpones the reconciliations of clients while ORY Hydra
+	// is unavailable
+	hydraBackoff hydraBackoff
+
 	client.Client
 }
 
 // +kubebuilder:rbac:groups=hydra.ory.sh,resources=oauth2clients,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=hydra.ory.sh,resources=oauth2clients/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=hydra.ory.sh,resources=oauth2clienttemplates,verbs=get;list;watch
+// +kubebuilder:rbac:groups=hydra.ory.sh,resources=hydrainstances,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;delete
 
 func (r *OAuth2ClientReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
-	ctx := context.Background()
+	ctx, cancel := r.reconcileContext()
+	defer cancel()
+	ctx, span := tracing.Tracer().Start(ctx, "OAuth2Client.Reconcile", trace.WithAttributes(
+		attribute.String("k8s.namespace.name", req.Namespace),
+		attribute.String("oauth2client.name", req.Name),
+	))
+	defer span.End()
 	_ = r.Log.WithValues("oauth2client", req.NamespacedName)
+	observeReconcileLag(req)
+
+	result, err := r.reconcile(ctx, req)
+	if isBudgetExhausted(err) {
+		countReconcile(span, "timeout", err)
+		r.Log.Info(fmt.Sprintf("reconciliation of client %s ran out of time, requeueing it: %s", req.NamespacedName, err))
+		return ctrl.Result{Requeue: true}, nil
+	}
+	if hydraclient.IsUnavailable(err) {
+		countReconcile(span, "hydra_unavailable", err)
+		// rather than failing, the reconciliation is retried with an
+		// exponential backoff until ORY Hydra is back, or when it asked to
+		delay := r.hydraBackoff.next(req.NamespacedName)
+		if retryAfter, ok := hydraclient.RetryAfter(err); ok {
+			delay = retryAfter
+		}
+		r.Log.Info(fmt.Sprintf("ORY Hydra is unavailable for client %s, retrying in %s: %s", req.NamespacedName, delay, err))
+		message := fmt.Sprintf("%s, retrying in %s", err, delay)
+		return ctrl.Result{RequeueAfter: delay}, r.setHydraAvailable(ctx, req.NamespacedName, apiv1.ConditionFalse, "HydraUnavailable", message)
+	}
+	if err == nil && r.hydraBackoff.reset(req.NamespacedName) {
+		err = r.setHydraAvailable(ctx, req.NamespacedName, apiv1.ConditionTrue, "HydraAvailable", "")
+	}
+	// obtaining a token writes to ORY Hydra, so credentials are not verified
+	// in audit mode
+	if err == nil && r.VerificationImage != "" && !r.AuditMode {
+		err = r.verifyCredentials(ctx, req)
+	}
+	if err != nil {
+		countReconcile(span, "error", err)
+	} else {
+		countReconcile(span, "success", nil)
+	}
+	return result, err
+}
 
+// countReconcile records the result of a reconciliation in the
+// hydra_maester_reconciles_total metric and in its span
+func countReconcile(span trace.Span, result string, err error) {
+	reconciles.WithLabelValues(result).Inc()
+	span.SetAttributes(attribute.String("oauth2client.reconcile.result", result))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
+
+func (r *OAuth2ClientReconciler) reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	var oauth2client hydrav1alpha1.OAuth2Client
 	if err := r.Get(ctx, req.NamespacedName, &oauth2client); err != nil {
 		if apierrs.IsNotFound(err) {
-			if registerErr := r.unregisterOAuth2Clients(ctx, &oauth2client); registerErr != nil {
+			managedClients.remove(req.NamespacedName)
+			if r.AuditMode {
+				clientDrift.DeleteLabelValues(req.Namespace, req.Name)
+				return ctrl.Result{}, nil
+			}
+			if registerErr := r.unregisterOAuth2Clients(ctx, &oauth2client, false); registerErr != nil {
 				return ctrl.Result{}, registerErr
 			}
 			return ctrl.Result{}, nil
 		}
 		return ctrl.Result{}, err
 	}
+	// clients mapped from other resources may belong to another deployment
+	if !r.selects(&oauth2client) {
+		managedClients.remove(req.NamespacedName)
+		return ctrl.Result{}, nil
+	}
 
 	// examine DeletionTimestamp to determine if object is under deletion
 	if oauth2client.ObjectMeta.DeletionTimestamp.IsZero() {
+		managedClients.add(req.NamespacedName)
 		// The object is not being deleted, so if it does not have our finalizer,
 		// then lets add the finalizer and update the object. This is equivalent
 		// registering our finalizer.
-		if !containsString(oauth2client.ObjectMeta.Finalizers, FinalizerName) {
+		if !r.AuditMode && !containsString(oauth2client.ObjectMeta.Finalizers, FinalizerName) {
 			typeMeta := oauth2client.TypeMeta
 			oauth2client.ObjectMeta.Finalizers = append(oauth2client.ObjectMeta.Finalizers, FinalizerName)
 			if err := r.Update(ctx, &oauth2client); err != nil {
@@ -98,12 +246,42 @@ func (r *OAuth2ClientReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error
 		}
 	} else {
 		// The object is being deleted
+		managedClients.remove(req.NamespacedName)
 		if containsString(oauth2client.ObjectMeta.Finalizers, FinalizerName) {
-			// our finalizer is present, so lets handle any external dependency
-			if err := r.unregisterOAuth2Clients(ctx, &oauth2client); err != nil {
-				// if fail to delete the external dependency here, return with error
-				// so that it can be retried
-				return ctrl.Result{}, err
+			// a protected client deleted without the webhook is kept until the
+			// annotation is removed
+			if isDeletionProtected(&oauth2client) {
+				r.recordEvent(&oauth2client, apiv1.EventTypeWarning, EventReasonDeletionPrevented, fmt.Sprintf("client is protected by the %s annotation, remove it to complete the deletion", PreventDeletionAnnotation))
+				return ctrl.Result{}, nil
+			}
+
+			// our finalizer is present, so lets handle any external dependency,
+			// except in audit mode where it is only released
+			switch {
+			case r.AuditMode:
+			case oauth2client.GetDeletionPolicy() == hydrav1alpha1.DeletionPolicyOrphan:
+				if err := r.orphanOAuth2Client(ctx, &oauth2client); err != nil {
+					r.recordEvent(&oauth2client, apiv1.EventTypeWarning, EventReasonClientDeletionFailed, err.Error())
+					return ctrl.Result{}, err
+				}
+				r.recordEvent(&oauth2client, apiv1.EventTypeNormal, EventReasonClientOrphaned, "kept the client in ORY Hydra and its Secret")
+			default:
+				if err := r.unregisterOAuth2Clients(ctx, &oauth2client, false); err != nil {
+					// if fail to delete the external dependency here, return with error
+					// so that it can be retried
+					r.recordEvent(&oauth2client, apiv1.EventTypeWarning, EventReasonClientDeletionFailed, err.Error())
+					return ctrl.Result{}, err
+				}
+				r.recordEvent(&oauth2client, apiv1.EventTypeNormal, EventReasonClientDeleted, "deleted the client from ORY Hydra")
+				if err := r.deleteOwnedSecret(ctx, &oauth2client); err != nil {
+					return ctrl.Result{}, err
+				}
+				if err := r.deleteStoredCredentials(ctx, &oauth2client); err != nil {
+					return ctrl.Result{}, err
+				}
+				if err := r.deleteSecretCopies(ctx, &oauth2client); err != nil {
+					return ctrl.Result{}, err
+				}
 			}
 
 			// remove our finalizer from the list and update it.
@@ -117,27 +295,177 @@ func (r *OAuth2ClientReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error
 
 	}
 
-	var secret apiv1.Secret
-	if err := r.Get(ctx, types.NamespacedName{Name: oauth2client.Spec.SecretName, Namespace: req.Namespace}, &secret); err != nil {
+	suspendedChanged := checkSuspended(&oauth2client)
+	if oauth2client.Spec.Suspend {
+		if suspendedChanged {
+			return ctrl.Result{}, r.updateClientStatus(ctx, &oauth2client)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	clientExpiryChanged, untilClientExpiry, expired := checkClientExpiry(&oauth2client)
+	if expired && !r.AuditMode {
+		return r.expireOAuth2Client(ctx, &oauth2client)
+	}
+
+	settings, err := r.settings(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if settings.strictFields {
+		fields, err := unknownFields(&oauth2client)
+		if err != nil {
+			r.Log.Error(err, fmt.Sprintf("unable to check client %s/%s for unknown fields", oauth2client.Name, oauth2client.Namespace))
+		}
+		if len(fields) > 0 {
+			unknownErr := errors.Errorf("fields %s are unknown to the controller and were dropped", strings.Join(fields, ", "))
+			oauth2client.Status.SetCondition(hydrav1alpha1.OAuth2ClientConditionUnknownFields, apiv1.ConditionTrue, "UnknownFieldsDropped", unknownErr.Error())
+			if updateErr := r.updateReconciliationStatusError(ctx, &oauth2client, hydrav1alpha1.StatusUnknownFields, unknownErr); updateErr != nil {
+				return ctrl.Result{}, updateErr
+			}
+			return ctrl.Result{}, nil
+		}
+		oauth2client.Status.SetCondition(hydrav1alpha1.OAuth2ClientConditionUnknownFields, apiv1.ConditionFalse, "NoUnknownFields", "")
+	}
+
+	template, err := r.templateFor(ctx, &oauth2client)
+	if err != nil {
 		if apierrs.IsNotFound(err) {
-			if registerErr := r.registerOAuth2Client(ctx, &oauth2client, nil); registerErr != nil {
-				return ctrl.Result{}, registerErr
+			notFoundErr := errors.Errorf("template %s/%s does not exist", oauth2client.Namespace, oauth2client.Spec.TemplateRef.Name)
+			if updateErr := r.updateReconciliationStatusError(ctx, &oauth2client, hydrav1alpha1.StatusTemplateNotFound, notFoundErr); updateErr != nil {
+				return ctrl.Result{}, updateErr
 			}
 			return ctrl.Result{}, nil
 		}
 		return ctrl.Result{}, err
 	}
+	if template != nil {
+		if err := oauth2client.ApplyTemplate(template); err != nil {
+			if updateErr := r.updateReconciliationStatusError(ctx, &oauth2client, hydrav1alpha1.StatusInvalidSpec, errors.Wrapf(err, "template %s/%s is invalid", template.Namespace, template.Name)); updateErr != nil {
+				return ctrl.Result{}, updateErr
+			}
+			return ctrl.Result{}, nil
+		}
+	}
+	templateChanged := oauth2client.Status.TemplateGeneration != templateGeneration(template)
+	oauth2client.Status.TemplateGeneration = templateGeneration(template)
+	if oauth2client.Spec.SecretTTL == nil {
+		oauth2client.Spec.SecretTTL = settings.secretTTL
+	}
+	if oauth2client.Spec.AccessTokenStrategy == hydrav1alpha1.AccessTokenStrategyJWT && len(oauth2client.Spec.Audience) == 0 {
+		oauth2client.Spec.Audience = settings.jwtAudiences[oauth2client.Namespace]
+	}
+
+	if err := oauth2client.Validate(); err != nil {
+		if updateErr := r.updateReconciliationStatusError(ctx, &oauth2client, hydrav1alpha1.StatusInvalidSpec, err); updateErr != nil {
+			return ctrl.Result{}, updateErr
+		}
+		return ctrl.Result{}, nil
+	}
 
-	credentials, err := parseSecret(secret, oauth2client.Spec.TokenEndpointAuthMethod)
+	allowPattern, err := r.redirectURIAllowPattern(ctx, oauth2client.Namespace, settings.redirectURIAllowPattern)
 	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := checkRedirectURIs(&oauth2client, allowPattern); err != nil {
+		oauth2client.Status.SetCondition(hydrav1alpha1.OAuth2ClientConditionRedirectURIsAllowed, apiv1.ConditionFalse, "RedirectURINotAllowed", err.Error())
+		if updateErr := r.updateReconciliationStatusError(ctx, &oauth2client, hydrav1alpha1.StatusRedirectURINotAllowed, err); updateErr != nil {
+			return ctrl.Result{}, updateErr
+		}
+		return ctrl.Result{}, nil
+	}
+	conditionsChanged := oauth2client.Status.SetCondition(hydrav1alpha1.OAuth2ClientConditionRedirectURIsAllowed, apiv1.ConditionTrue, "RedirectURIsAllowed", "") || clientExpiryChanged || suspendedChanged
+
+	denied, warned, err := r.policyViolations(ctx, &oauth2client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if len(denied) > 0 {
+		violationErr := errors.Errorf("client violates policies: %s", strings.Join(append(denied, warned...), "; "))
+		oauth2client.Status.SetCondition(hydrav1alpha1.OAuth2ClientConditionPolicyCompliant, apiv1.ConditionFalse, "PolicyViolated", violationErr.Error())
+		if updateErr := r.updateReconciliationStatusError(ctx, &oauth2client, hydrav1alpha1.StatusPolicyViolation, violationErr); updateErr != nil {
+			return ctrl.Result{}, updateErr
+		}
+		return ctrl.Result{}, nil
+	}
+	if len(warned) > 0 {
+		message := fmt.Sprintf("client violates policies: %s", strings.Join(warned, "; "))
+		if oauth2client.Status.SetCondition(hydrav1alpha1.OAuth2ClientConditionPolicyCompliant, apiv1.ConditionFalse, "PolicyViolated", message) {
+			r.recordEvent(&oauth2client, apiv1.EventTypeWarning, EventReasonPolicyViolated, message)
+			conditionsChanged = true
+		}
+	} else if oauth2client.Status.SetCondition(hydrav1alpha1.OAuth2ClientConditionPolicyCompliant, apiv1.ConditionTrue, "PolicyCompliant", "") {
+		conditionsChanged = true
+	}
+
+	var secret apiv1.Secret
+	var credentials *hydraclient.Oauth2ClientCredentials
+	secretErr := r.Get(ctx, types.NamespacedName{Name: oauth2client.Spec.SecretName, Namespace: req.Namespace}, &secret)
+	if apierrs.IsNotFound(secretErr) {
+		// credentials kept in the credential store only have no Secret
+		stored, err := r.storedSecret(ctx, &oauth2client)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if stored != nil {
+			secret, secretErr = *stored, nil
+		}
+	}
+	if secretErr != nil {
+		if !apierrs.IsNotFound(secretErr) {
+			return ctrl.Result{}, secretErr
+		}
+		// public clients get no Secret, they are known by the ID in their
+		// spec or status once registered
+		publicChanged := markPublicClient(&oauth2client)
+		if credentials = publicClientCredentials(&oauth2client); credentials == nil {
+			if r.AuditMode {
+				if auditErr := r.auditOAuth2Client(ctx, &oauth2client, nil, nil, conditionsChanged || publicChanged); auditErr != nil {
+					return ctrl.Result{}, auditErr
+				}
+				return ctrl.Result{RequeueAfter: requeueAfter(untilClientExpiry, settings.driftDetectionInterval)}, nil
+			}
+			if restored, restoreErr := r.restoreSecret(ctx, &oauth2client); restoreErr != nil || restored {
+				return ctrl.Result{}, restoreErr
+			}
+			if registerErr := r.registerOAuth2Client(ctx, &oauth2client, nil); registerErr != nil {
+				return ctrl.Result{}, registerErr
+			}
+			return ctrl.Result{}, nil
+		}
+		conditionsChanged = conditionsChanged || publicChanged
+	} else if credentials, err = r.parseSecret(secret, &oauth2client); err != nil {
+		if secret.Name != "" && !isOwnedBy(&secret, &oauth2client) && takesOverSecrets(&oauth2client) && !r.AuditMode {
+			// the Secret can't be used as provided credentials, so it is
+			// taken over once the client is registered or restored
+			if restored, restoreErr := r.restoreSecret(ctx, &oauth2client); restoreErr != nil || restored {
+				return ctrl.Result{}, restoreErr
+			}
+			return ctrl.Result{}, r.registerOAuth2Client(ctx, &oauth2client, nil)
+		}
 		r.Log.Error(err, fmt.Sprintf("secret %s/%s is invalid", secret.Name, secret.Namespace))
 		if updateErr := r.updateReconciliationStatusError(ctx, &oauth2client, hydrav1alpha1.StatusInvalidSecret, err); updateErr != nil {
 			return ctrl.Result{}, updateErr
 		}
 		return ctrl.Result{}, nil
 	}
+	manualSecretChanged := trackManualSecret(&oauth2client, &secret)
+
+	if oauth2client.Spec.ClientID != "" && string(credentials.ID) != oauth2client.Spec.ClientID {
+		mismatchErr := errors.Errorf("ID provided in secret %s/%s does not match the client ID in the spec", secret.Name, secret.Namespace)
+		if updateErr := r.updateReconciliationStatusError(ctx, &oauth2client, hydrav1alpha1.StatusInvalidSecret, mismatchErr); updateErr != nil {
+			return ctrl.Result{}, updateErr
+		}
+		return ctrl.Result{}, nil
+	}
 
-	hydraClient, err := r.getHydraClientForClient(oauth2client)
+	if remaining := r.deletedClients.remaining(string(credentials.ID)); remaining > 0 {
+		r.Log.Info(fmt.Sprintf("client %s of %s was just deleted from ORY Hydra, skipping the reconciliation", credentials.ID, req.NamespacedName))
+		return ctrl.Result{RequeueAfter: remaining}, nil
+	}
+
+	hydraClient, err := r.getHydraClientForClient(ctx, oauth2client)
 	if err != nil {
 		r.Log.Error(err, fmt.Sprintf(
 			"hydra address %s:%d%s is invalid",
@@ -151,124 +479,384 @@ func (r *OAuth2ClientReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error
 		return ctrl.Result{}, nil
 	}
 
+	if err := checkBudget(ctx, "fetching the client from ORY Hydra"); err != nil {
+		return ctrl.Result{}, err
+	}
 	fetched, found, err := hydraClient.GetOAuth2Client(string(credentials.ID))
 	if err != nil {
+		r.recordEvent(&oauth2client, apiv1.EventTypeWarning, EventReasonHydraRequestFailed, err.Error())
 		return ctrl.Result{}, err
 
 	}
 
+	if r.AuditMode {
+		if !found {
+			fetched = nil
+		}
+		if err := r.auditOAuth2Client(ctx, &oauth2client, credentials, fetched, conditionsChanged || manualSecretChanged); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: requeueAfter(untilClientExpiry, settings.driftDetectionInterval)}, nil
+	}
+
+	conditionsChanged = trackSecretAge(&oauth2client) || conditionsChanged
+	rotationDue, untilRotation := scheduledRotation(&oauth2client)
+	rotationDue = rotationDue && r.rotationSkipReason(&oauth2client, &secret) == ""
+	if request := pendingRotation(&oauth2client); found && (request != "" || rotationDue) && fetched.Owner == fmt.Sprintf("%s/%s", oauth2client.Name, oauth2client.Namespace) {
+		if err := r.rotateClientSecret(ctx, &oauth2client, &secret, credentials, fetched, request); err != nil {
+			return ctrl.Result{}, err
+		}
+		_, untilExpiry := checkSecretExpiry(&oauth2client)
+		_, untilRotation = scheduledRotation(&oauth2client)
+		return ctrl.Result{RequeueAfter: requeueAfter(untilExpiry, untilRotation, untilPreviousSecretExpiry(&oauth2client), untilClientExpiry, settings.driftDetectionInterval)}, nil
+	}
+
 	if found {
-		//conclude reconciliation if the client exists and has not been updated
-		if oauth2client.Generation == oauth2client.Status.ObservedGeneration {
-			return ctrl.Result{}, nil
+		expiryChanged, untilExpiry := checkSecretExpiry(&oauth2client)
+		previousExpired, untilPreviousExpiry, err := r.expirePreviousSecret(ctx, &oauth2client, &secret)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		checksumChanged := trackSecretChecksum(&oauth2client, &secret)
+		// a reconciliation error of the current generation, e.g. a policy
+		// violation which has been lifted since, is cleared by updating
+		upToDate := oauth2client.Generation == oauth2client.Status.ObservedGeneration && !manualSecretChanged && !templateChanged &&
+			oauth2client.Status.ReconciliationError.Code == ""
+
+		if upToDate {
+			drifted := false
+			if settings.driftDetectionInterval > 0 && fetched.Owner == fmt.Sprintf("%s/%s", oauth2client.Name, oauth2client.Namespace) {
+				if drifted, err = hasDrifted(&oauth2client, credentials, fetched); err != nil {
+					return ctrl.Result{}, err
+				}
+			}
+
+			//conclude reconciliation if the client exists and has not been updated
+			if !drifted {
+				// copies of the Secret deleted or modified since are restored
+				targetsChanged, err := r.syncSecretTargets(ctx, &oauth2client, &secret)
+				if err != nil {
+					return ctrl.Result{}, err
+				}
+				if expiryChanged || conditionsChanged || targetsChanged || previousExpired || checksumChanged {
+					if err := r.updateClientStatus(ctx, &oauth2client); err != nil {
+						return ctrl.Result{}, err
+					}
+				}
+				return ctrl.Result{RequeueAfter: requeueAfter(untilExpiry, untilRotation, untilPreviousExpiry, untilClientExpiry, settings.driftDetectionInterval)}, nil
+			}
+
+			if updateErr := r.updateRegisteredOAuth2Client(ctx, &oauth2client, credentials, fetched, false); updateErr != nil {
+				return ctrl.Result{}, updateErr
+			}
+			r.recordDriftCorrected(&oauth2client, "client was modified in ORY Hydra, restored it from the spec")
+			return ctrl.Result{RequeueAfter: requeueAfter(untilExpiry, untilRotation, untilPreviousExpiry, untilClientExpiry, settings.driftDetectionInterval)}, nil
 		}
 
 		if fetched.Owner != fmt.Sprintf("%s/%s", oauth2client.Name, oauth2client.Namespace) {
 			conflictErr := errors.Errorf("ID provided in secret %s/%s is assigned to another resource", secret.Name, secret.Namespace)
+			if secret.Name == "" {
+				conflictErr = errors.Errorf("client ID %s is assigned to another resource", credentials.ID)
+			}
 			if updateErr := r.updateReconciliationStatusError(ctx, &oauth2client, hydrav1alpha1.StatusInvalidSecret, conflictErr); updateErr != nil {
 				return ctrl.Result{}, updateErr
 			}
 			return ctrl.Result{}, nil
 		}
 
-		if updateErr := r.updateRegisteredOAuth2Client(ctx, &oauth2client, credentials); updateErr != nil {
+		if updateErr := r.updateRegisteredOAuth2Client(ctx, &oauth2client, credentials, fetched, manualSecretChanged); updateErr != nil {
 			return ctrl.Result{}, updateErr
 		}
-		return ctrl.Result{}, nil
+		if err := checkBudget(ctx, "updating the secret"); err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.ensureSecretProjection(ctx, &oauth2client, &secret, credentials); err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.ensureStoredCredentials(ctx, &oauth2client, &secret, credentials); err != nil {
+			if updateErr := r.updateReconciliationStatusError(ctx, &oauth2client, hydrav1alpha1.StatusStoreCredentialsFailed, err); updateErr != nil {
+				return ctrl.Result{}, updateErr
+			}
+			return ctrl.Result{}, err
+		}
+		pushChanged, err := r.ensureSecretPush(ctx, &oauth2client, &secret, credentials)
+		if err != nil {
+			if updateErr := r.updateReconciliationStatusError(ctx, &oauth2client, hydrav1alpha1.StatusPushSecretFailed, err); updateErr != nil {
+				return ctrl.Result{}, updateErr
+			}
+			return ctrl.Result{}, err
+		}
+		targetsChanged, err := r.syncSecretTargets(ctx, &oauth2client, &secret)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		checksumChanged = trackSecretChecksum(&oauth2client, &secret) || checksumChanged
+		if pushChanged || targetsChanged || previousExpired || checksumChanged {
+			if err := r.updateClientStatus(ctx, &oauth2client); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{RequeueAfter: requeueAfter(untilExpiry, untilRotation, untilPreviousExpiry, untilClientExpiry, settings.driftDetectionInterval)}, nil
 	}
 
+	deleted := wasRegistered(&oauth2client)
 	if registerErr := r.registerOAuth2Client(ctx, &oauth2client, credentials); registerErr != nil {
 		return ctrl.Result{}, registerErr
 	}
+	if deleted {
+		r.recordDriftCorrected(&oauth2client, "client was deleted from ORY Hydra, registered it again")
+	}
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: requeueAfter(untilClientExpiry, settings.driftDetectionInterval)}, nil
 }
 
 func (r *OAuth2ClientReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&hydrav1alpha1.OAuth2Client{}).
-		Complete(r)
+	// the controller is wired by hand rather than with the builder, as the
+	// handler of the primary watch must be wrapped to measure queue lag
+	c, err := controller.New("oauth2client", mgr, controller.Options{Reconciler: r, MaxConcurrentReconciles: r.MaxConcurrentReconciles})
+	if err != nil {
+		return err
+	}
+	if err := c.Watch(&source.Kind{Type: &hydrav1alpha1.OAuth2Client{}}, trackPending(&handler.EnqueueRequestForObject{}), contentChangedPredicate, r.selectedPredicate()); err != nil {
+		return err
+	}
+	if err := c.Watch(&source.Kind{Type: &apiv1.Secret{}}, trackPending(r.secretToOAuth2Clients()), r.namespacePredicate()); err != nil {
+		return err
+	}
+	if err := c.Watch(&source.Kind{Type: &hydrav1alpha1.OAuth2ClientTemplate{}}, trackPending(r.templateToOAuth2Clients()), r.namespacePredicate()); err != nil {
+		return err
+	}
+	if err := c.Watch(&source.Kind{Type: &hydrav1alpha1.HydraInstance{}}, trackPending(r.hydraInstanceToOAuth2Clients())); err != nil {
+		return err
+	}
+	if err := c.Watch(&source.Kind{Type: &hydrav1alpha1.HydraMaesterConfiguration{}}, trackPending(r.configurationToOAuth2Clients())); err != nil {
+		return err
+	}
+	if err := c.Watch(&source.Kind{Type: &hydrav1alpha1.OAuth2ClientPolicy{}}, trackPending(r.policyToOAuth2Clients())); err != nil {
+		return err
+	}
+	if r.VerificationImage == "" {
+		return nil
+	}
+	return c.Watch(&source.Kind{Type: &batchv1.Job{}}, trackPending(&handler.EnqueueRequestForOwner{OwnerType: &hydrav1alpha1.OAuth2Client{}, IsController: true}))
 }
 
-func (r *OAuth2ClientReconciler) registerOAuth2Client(ctx context.Context, c *hydrav1alpha1.OAuth2Client, credentials *hydra.Oauth2ClientCredentials) error {
-	if err := r.unregisterOAuth2Clients(ctx, c); err != nil {
+func (r *OAuth2ClientReconciler) registerOAuth2Client(ctx context.Context, c *hydrav1alpha1.OAuth2Client, credentials *hydraclient.Oauth2ClientCredentials) error {
+	if err := r.unregisterOAuth2Clients(ctx, c, true); err != nil {
 		return err
 	}
 
-	hydra, err := r.getHydraClientForClient(*c)
+	hydra, err := r.getHydraClientForClient(ctx, *c)
 	if err != nil {
 		return err
 	}
+	if err := checkBudget(ctx, "registering the client in ORY Hydra"); err != nil {
+		return err
+	}
+
+	startSecretLifetime(c)
+	restartSecretAge(c)
 
 	if credentials != nil {
-		if _, err := hydra.PostOAuth2Client(c.ToOAuth2ClientJSON().WithCredentials(credentials)); err != nil {
+		created, err := hydra.PostOAuth2Client(c.ToOAuth2ClientJSON().WithCredentials(credentials))
+		if err != nil {
 			if updateErr := r.updateReconciliationStatusError(ctx, c, hydrav1alpha1.StatusRegistrationFailed, err); updateErr != nil {
 				return updateErr
 			}
+			return retryIfTransient(err)
 		}
+		recordWrite(c, created)
+		r.recordEvent(c, apiv1.EventTypeNormal, EventReasonClientCreated, fmt.Sprintf("registered client %s in ORY Hydra", *created.ClientID))
 		return r.ensureEmptyStatusError(ctx, c)
 	}
 
-	created, err := hydra.PostOAuth2Client(c.ToOAuth2ClientJSON())
+	desired := c.ToOAuth2ClientJSON()
+	if c.IsPublic() {
+		created, err := hydra.PostOAuth2Client(desired)
+		if err != nil {
+			if updateErr := r.updateReconciliationStatusError(ctx, c, hydrav1alpha1.StatusRegistrationFailed, err); updateErr != nil {
+				return updateErr
+			}
+			return retryIfTransient(err)
+		}
+		recordWrite(c, created)
+		r.recordEvent(c, apiv1.EventTypeNormal, EventReasonClientCreated, fmt.Sprintf("registered public client %s in ORY Hydra", *created.ClientID))
+		return r.ensureEmptyStatusError(ctx, c)
+	}
+
+	existing, err := r.existingCredentials(ctx, c)
 	if err != nil {
-		if updateErr := r.updateReconciliationStatusError(ctx, c, hydrav1alpha1.StatusRegistrationFailed, err); updateErr != nil {
+		if _, ok := err.(apierrs.APIStatus); ok {
+			return err
+		}
+		if updateErr := r.updateReconciliationStatusError(ctx, c, hydrav1alpha1.StatusInvalidSecret, err); updateErr != nil {
 			return updateErr
 		}
 		return nil
 	}
+	var generated []byte
+	if existing != nil {
+		desired = desired.WithCredentials(existing)
+	} else if r.GenerateClientSecrets {
+		if generated, err = r.generateClientSecret(); err != nil {
+			return err
+		}
+		secret := string(generated)
+		desired.Secret = &secret
+	}
+
+	created, err := hydra.PostOAuth2Client(desired)
+	if err != nil {
+		if updateErr := r.updateReconciliationStatusError(ctx, c, hydrav1alpha1.StatusRegistrationFailed, err); updateErr != nil {
+			return updateErr
+		}
+		return retryIfTransient(err)
+	}
+	recordWrite(c, created)
+	r.recordEvent(c, apiv1.EventTypeNormal, EventReasonClientCreated, fmt.Sprintf("registered client %s in ORY Hydra", *created.ClientID))
+
+	credentials = credentialsOf(created)
+	if existing != nil {
+		// ORY Hydra doesn't necessarily return a secret it has been given
+		credentials = existing
+	} else if generated != nil {
+		credentials.Password = generated
+	}
+	return r.writeCredentials(ctx, c, credentials)
+}
+
+// writeCredentials writes the credentials of c, registered in ORY Hydra, to
+// its Secret and the credential store
+func (r *OAuth2ClientReconciler) writeCredentials(ctx context.Context, c *hydrav1alpha1.OAuth2Client, credentials *hydraclient.Oauth2ClientCredentials) error {
+	data, err := r.secretData(c, credentials)
+	if err != nil {
+		return err
+	}
+	if err := r.storeCredentials(ctx, c, data); err != nil {
+		if updateErr := r.updateReconciliationStatusError(ctx, c, hydrav1alpha1.StatusStoreCredentialsFailed, err); updateErr != nil {
+			return updateErr
+		}
+		return retryIfTransient(err)
+	}
+	if r.CredentialStore != nil && r.CredentialStoreOnly {
+		r.recordEvent(c, apiv1.EventTypeNormal, EventReasonCredentialsStored, fmt.Sprintf("stored the credentials of client %s in the credential store", credentials.ID))
+		return r.ensureEmptyStatusError(ctx, c)
+	}
 
 	clientSecret := apiv1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      c.Spec.SecretName,
-			Namespace: c.Namespace,
-			OwnerReferences: []metav1.OwnerReference{{
-				APIVersion: c.TypeMeta.APIVersion,
-				Kind:       c.TypeMeta.Kind,
-				Name:       c.ObjectMeta.Name,
-				UID:        c.ObjectMeta.UID,
-			}},
-		},
-		Data: map[string][]byte{
-			ClientIDKey: []byte(*created.ClientID),
+			Name:            c.Spec.SecretName,
+			Namespace:       c.Namespace,
+			OwnerReferences: []metav1.OwnerReference{ownerReferenceTo(c)},
 		},
+		Type: secretTypeOf(c),
+		Data: data,
 	}
 
-	if created.Secret != nil {
-		clientSecret.Data[ClientSecretKey] = []byte(*created.Secret)
+	if c.Status.ClientSecretExpiresAt != nil {
+		clientSecret.Annotations = map[string]string{
+			ClientSecretExpiresAtAnnotation: c.Status.ClientSecretExpiresAt.Format(time.RFC3339),
+		}
 	}
+	labelSecret(&clientSecret, c)
 
 	if err := r.Create(ctx, &clientSecret); err != nil {
-		if updateErr := r.updateReconciliationStatusError(ctx, c, hydrav1alpha1.StatusCreateSecretFailed, err); updateErr != nil {
-			return updateErr
+		if apierrs.IsAlreadyExists(err) {
+			// the Secret has been created since it was looked up, e.g. by
+			// another tool, and is only overwritten if the policy allows it
+			err = r.resolveSecretConflict(ctx, c, &clientSecret)
 		}
+		if err != nil {
+			// the client is registered, its ID is kept so that it isn't lost
+			c.Status.ClientID = string(credentials.ID)
+			if updateErr := r.updateReconciliationStatusError(ctx, c, hydrav1alpha1.StatusCreateSecretFailed, err); updateErr != nil {
+				return updateErr
+			}
+			return nil
+		}
+	} else {
+		secretsCreated.Inc()
+		r.recordEvent(c, apiv1.EventTypeNormal, EventReasonSecretCreated, fmt.Sprintf("created secret %s with the client credentials", clientSecret.Name))
 	}
 
+	if _, err := r.ensureSecretPush(ctx, c, &clientSecret, credentials); err != nil {
+		if updateErr := r.updateReconciliationStatusError(ctx, c, hydrav1alpha1.StatusPushSecretFailed, err); updateErr != nil {
+			return updateErr
+		}
+		return err
+	}
+	if _, err := r.syncSecretTargets(ctx, c, &clientSecret); err != nil {
+		return err
+	}
+	trackSecretChecksum(c, &clientSecret)
 	return r.ensureEmptyStatusError(ctx, c)
 }
 
-func (r *OAuth2ClientReconciler) updateRegisteredOAuth2Client(ctx context.Context, c *hydrav1alpha1.OAuth2Client, credentials *hydra.Oauth2ClientCredentials) error {
-	hydra, err := r.getHydraClientForClient(*c)
+// updateRegisteredOAuth2Client updates the client in ORY Hydra if it differs
+// from c, or if secretChanged is set
+func (r *OAuth2ClientReconciler) updateRegisteredOAuth2Client(ctx context.Context, c *hydrav1alpha1.OAuth2Client, credentials *hydraclient.Oauth2ClientCredentials, fetched *hydraclient.OAuth2ClientJSON, secretChanged bool) error {
+	hydra, err := r.getHydraClientForClient(ctx, *c)
+	if err != nil {
+		return err
+	}
+
+	if detectConflict(c, fetched) && c.Spec.ConflictPolicy == hydrav1alpha1.ConflictPolicyHold {
+		condition := c.Status.GetCondition(hydrav1alpha1.OAuth2ClientConditionConflictDetected)
+		return r.updateReconciliationStatusError(ctx, c, hydrav1alpha1.StatusConflictDetected, errors.New(condition.Message))
+	}
+
+	ensureSecretLifetime(c)
+
+	desired, err := desiredOAuth2Client(c, credentials, fetched)
 	if err != nil {
 		return err
 	}
+	c.Status.ClientID = string(credentials.ID)
+
+	if !secretChanged {
+		differs, err := desired.DiffersFrom(fetched)
+		if err != nil {
+			return err
+		}
+		if !differs {
+			return r.ensureEmptyStatusError(ctx, c)
+		}
+	}
 
-	if _, err := hydra.PutOAuth2Client(c.ToOAuth2ClientJSON().WithCredentials(credentials)); err != nil {
+	if err := checkBudget(ctx, "updating the client in ORY Hydra"); err != nil {
+		return err
+	}
+	updated, err := hydra.PutOAuth2Client(desired)
+	if err != nil {
 		if updateErr := r.updateReconciliationStatusError(ctx, c, hydrav1alpha1.StatusUpdateFailed, err); updateErr != nil {
 			return updateErr
 		}
+		return retryIfTransient(err)
 	}
+	recordWrite(c, updated)
+	r.recordEvent(c, apiv1.EventTypeNormal, EventReasonClientUpdated, fmt.Sprintf("updated client %s in ORY Hydra", *desired.ClientID))
 	return r.ensureEmptyStatusError(ctx, c)
 }
 
-func (r *OAuth2ClientReconciler) unregisterOAuth2Clients(ctx context.Context, c *hydrav1alpha1.OAuth2Client) error {
+// desiredOAuth2Client returns the client c should be registered as in ORY
+// Hydra, given the client currently registered
+func desiredOAuth2Client(c *hydrav1alpha1.OAuth2Client, credentials *hydraclient.Oauth2ClientCredentials, fetched *hydraclient.OAuth2ClientJSON) (*hydraclient.OAuth2ClientJSON, error) {
+	desired := c.ToOAuth2ClientJSON().WithCredentials(credentials)
+	return hydraclient.Desired(desired, fetched, c.Spec.UpdateStrategy == hydrav1alpha1.UpdateStrategyMerge, c.Spec.UnmanagedFields)
+}
+
+// unregisterOAuth2Clients deletes the clients owned by c from ORY Hydra. Clients
+// marked as exempt from garbage collection are kept if keepExempt is set.
+func (r *OAuth2ClientReconciler) unregisterOAuth2Clients(ctx context.Context, c *hydrav1alpha1.OAuth2Client, keepExempt bool) error {
 
 	// if a reqired field is empty, that means this is a delete after
-	// the finalizers have done their job, so just return
-	if c.Spec.Scope == "" || c.Spec.SecretName == "" {
+	// the finalizers have done their job, so just return. The scope isn't
+	// checked, as it may be given by a template which isn't applied yet.
+	if c.Spec.SecretName == "" {
 		return nil
 	}
 
-	hydra, err := r.getHydraClientForClient(*c)
+	hydra, err := r.getHydraClientForClient(ctx, *c)
 	if err != nil {
 		return err
 	}
@@ -280,32 +868,92 @@ func (r *OAuth2ClientReconciler) unregisterOAuth2Clients(ctx context.Context, c
 
 	for _, cJSON := range clients {
 		if cJSON.Owner == fmt.Sprintf("%s/%s", c.Name, c.Namespace) {
+			if keepExempt && cJSON.IsGCExempt() {
+				continue
+			}
 			if err := hydra.DeleteOAuth2Client(*cJSON.ClientID); err != nil {
 				return err
 			}
+			r.deletedClients.add(*cJSON.ClientID)
+		}
+	}
+
+	return nil
+}
+
+// deleteOwnedSecret deletes the Secret holding the credentials of c if it was
+// generated by the controller. Secrets provided by users are left untouched.
+func (r *OAuth2ClientReconciler) deleteOwnedSecret(ctx context.Context, c *hydrav1alpha1.OAuth2Client) error {
+	var secret apiv1.Secret
+	if err := r.Get(ctx, types.NamespacedName{Name: c.Spec.SecretName, Namespace: c.Namespace}, &secret); err != nil {
+		if apierrs.IsNotFound(err) {
+			return nil
 		}
+		return err
 	}
 
+	if !isOwnedBy(&secret, c) {
+		return nil
+	}
+	if err := r.Delete(ctx, &secret); err != nil && !apierrs.IsNotFound(err) {
+		return err
+	}
 	return nil
 }
 
 func (r *OAuth2ClientReconciler) updateReconciliationStatusError(ctx context.Context, c *hydrav1alpha1.OAuth2Client, code hydrav1alpha1.StatusCode, err error) error {
 	r.Log.Error(err, fmt.Sprintf("error processing client %s/%s ", c.Name, c.Namespace), "oauth2client", "register")
+	r.recordEvent(c, apiv1.EventTypeWarning, eventReasonFor(code), err.Error())
+	reconcileErrors.WithLabelValues(string(code)).Inc()
+	c.Status.SetCondition(hydrav1alpha1.OAuth2ClientConditionReady, apiv1.ConditionFalse, eventReasonFor(code), err.Error())
 	c.Status.ReconciliationError = hydrav1alpha1.ReconciliationError{
 		Code:        code,
 		Description: err.Error(),
 	}
+	// the template is not applied until the client is reconciled successfully
+	c.Status.TemplateGeneration = 0
 
 	return r.updateClientStatus(ctx, c)
 }
 
+// retryIfTransient returns err if the request to ORY Hydra which failed with
+// it is worth retrying, so the reconciliation is requeued with a backoff
+func retryIfTransient(err error) error {
+	if hydraclient.IsUnavailable(err) {
+		return err
+	}
+	return nil
+}
+
+// ensureEmptyStatusError records the successful reconciliation of the
+// current generation of c
 func (r *OAuth2ClientReconciler) ensureEmptyStatusError(ctx context.Context, c *hydrav1alpha1.OAuth2Client) error {
+	c.Status.ObservedGeneration = c.Generation
 	c.Status.ReconciliationError = hydrav1alpha1.ReconciliationError{}
+	c.Status.SetCondition(hydrav1alpha1.OAuth2ClientConditionReady, apiv1.ConditionTrue, "Reconciled", "")
+	c.Status.DeviceAuthorization = r.deviceAuthorizationFor(c)
+	checkSecretExpiry(c)
 	return r.updateClientStatus(ctx, c)
 }
 
+func (r *OAuth2ClientReconciler) deviceAuthorizationFor(c *hydrav1alpha1.OAuth2Client) *hydrav1alpha1.DeviceAuthorization {
+	if r.HydraPublicURL == "" || !c.HasGrantType(hydrav1alpha1.GrantTypeDeviceCode) {
+		return nil
+	}
+
+	u, err := url.Parse(r.HydraPublicURL)
+	if err != nil {
+		r.Log.Error(err, fmt.Sprintf("hydra public URL %s is invalid", r.HydraPublicURL))
+		return nil
+	}
+
+	return &hydrav1alpha1.DeviceAuthorization{
+		DeviceAuthorizationEndpoint: u.ResolveReference(&url.URL{Path: deviceAuthorizationPath}).String(),
+		VerificationURI:             u.ResolveReference(&url.URL{Path: deviceVerificationPath}).String(),
+	}
+}
+
 func (r *OAuth2ClientReconciler) updateClientStatus(ctx context.Context, c *hydrav1alpha1.OAuth2Client) error {
-	c.Status.ObservedGeneration = c.Generation
 	if err := r.Status().Update(ctx, c); err != nil {
 		r.Log.Error(err, fmt.Sprintf("status update failed for client %s/%s ", c.Name, c.Namespace), "oauth2client", "update status")
 		return err
@@ -313,25 +961,35 @@ func (r *OAuth2ClientReconciler) updateClientStatus(ctx context.Context, c *hydr
 	return nil
 }
 
-func parseSecret(secret apiv1.Secret, authMethod hydrav1alpha1.TokenEndpointAuthMethod) (*hydra.Oauth2ClientCredentials, error) {
-
-	id, found := secret.Data[ClientIDKey]
-	if !found {
-		return nil, errors.New(`"client_id property missing"`)
+// getHydraClientForClient returns the client of the ORY Hydra instance
+// oauth2client is managed in, bound to the deadline of ctx. It is given by
+// the HydraInstance of the client, else by its spec, else by the annotation
+// of its namespace, and defaults to the instance set by the flags.
+func (r *OAuth2ClientReconciler) getHydraClientForClient(ctx context.Context, oauth2client hydrav1alpha1.OAuth2Client) (ClientRegistrar, error) {
+	if ref := oauth2client.Spec.HydraInstanceRef; ref != nil {
+		c, err := r.hydraClientForInstance(ctx, ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		return withDeadline(ctx, c), nil
 	}
 
-	psw, found := secret.Data[ClientSecretKey]
-	if !found && authMethod != "none" {
-		return nil, errors.New(`"client_secret property missing"`)
+	if oauth2client.Spec.HydraAdmin.URL == "" {
+		adminURL, err := r.namespaceAdminURL(ctx, oauth2client.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		oauth2client.Spec.HydraAdmin.URL = adminURL
 	}
 
-	return &hydra.Oauth2ClientCredentials{
-		ID:       id,
-		Password: psw,
-	}, nil
+	c, err := r.cachedHydraClientForClient(oauth2client)
+	if err != nil {
+		return nil, err
+	}
+	return withDeadline(ctx, c), nil
 }
 
-func (r *OAuth2ClientReconciler) getHydraClientForClient(oauth2client hydrav1alpha1.OAuth2Client) (HydraClientInterface, error) {
+func (r *OAuth2ClientReconciler) cachedHydraClientForClient(oauth2client hydrav1alpha1.OAuth2Client) (ClientRegistrar, error) {
 	spec := oauth2client.Spec
 	if spec.HydraAdmin == (hydrav1alpha1.HydraAdmin{}) {
 		r.Log.Info(fmt.Sprintf("using default client"))
@@ -343,10 +1001,39 @@ func (r *OAuth2ClientReconciler) getHydraClientForClient(oauth2client hydrav1alp
 		endpoint:       spec.HydraAdmin.Endpoint,
 		forwardedProto: spec.HydraAdmin.ForwardedProto,
 	}
+
+	r.clientsMu.RLock()
+	c, ok := r.otherClients[key]
+	r.clientsMu.RUnlock()
+	if ok {
+		return c, nil
+	}
+
+	r.clientsMu.Lock()
+	defer r.clientsMu.Unlock()
 	if c, ok := r.otherClients[key]; ok {
 		return c, nil
 	}
-	return r.HydraClientMaker(spec)
+	c, err := r.HydraClientMaker(spec)
+	if err != nil {
+		return nil, err
+	}
+	if r.otherClients == nil {
+		r.otherClients = make(map[clientMapKey]ClientRegistrar)
+	}
+	r.otherClients[key] = c
+	return c, nil
+}
+
+// ResetHydraClients drops the cached clients for ORY Hydra instances other
+// than the default one, so they are rebuilt on next use. It is called
+// whenever a HydraInstance changes, and should be called whenever the
+// credentials used by HydraClientMaker change.
+func (r *OAuth2ClientReconciler) ResetHydraClients() {
+	r.clientsMu.Lock()
+	defer r.clientsMu.Unlock()
+	r.otherClients = nil
+	r.instanceClients = nil
 }
 
 // Helper functions to check and remove string from a slice of strings.
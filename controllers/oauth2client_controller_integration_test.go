@@ -13,7 +13,7 @@ import (
 	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
 	"github.com/ory/hydra-maester/controllers"
 	"github.com/ory/hydra-maester/controllers/mocks"
-	"github.com/ory/hydra-maester/hydra"
+	"github.com/ory/hydra-maester/pkg/hydraclient"
 	. "github.com/stretchr/testify/mock"
 	apiv1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -63,8 +63,8 @@ var _ = Describe("OAuth2Client Controller", func() {
 				mch.On("GetOAuth2Client", Anything).Return(nil, false, nil)
 				mch.On("DeleteOAuth2Client", Anything).Return(nil)
 				mch.On("ListOAuth2Client", Anything).Return(nil, nil)
-				mch.On("PostOAuth2Client", AnythingOfType("*hydra.OAuth2ClientJSON")).Return(func(o *hydra.OAuth2ClientJSON) *hydra.OAuth2ClientJSON {
-					return &hydra.OAuth2ClientJSON{
+				mch.On("PostOAuth2Client", AnythingOfType("*hydraclient.OAuth2ClientJSON")).Return(func(o *hydraclient.OAuth2ClientJSON) *hydraclient.OAuth2ClientJSON {
+					return &hydraclient.OAuth2ClientJSON{
 						ClientID:      &tstClientID,
 						Secret:        pointer.StringPtr(tstSecret),
 						GrantTypes:    o.GrantTypes,
@@ -74,7 +74,7 @@ var _ = Describe("OAuth2Client Controller", func() {
 						Audience:      o.Audience,
 						Owner:         o.Owner,
 					}
-				}, func(o *hydra.OAuth2ClientJSON) error {
+				}, func(o *hydraclient.OAuth2ClientJSON) error {
 					return nil
 				})
 
@@ -103,6 +103,9 @@ var _ = Describe("OAuth2Client Controller", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(retrieved.Status.ReconciliationError.Code).To(BeEmpty())
 				Expect(retrieved.Status.ReconciliationError.Description).To(BeEmpty())
+				Expect(retrieved.Status.ObservedGeneration).To(Equal(retrieved.Generation))
+				Expect(retrieved.Status.ClientID).To(Equal(tstClientID))
+				Expect(retrieved.Status.GetCondition(hydrav1alpha1.OAuth2ClientConditionReady).Status).To(Equal(apiv1.ConditionTrue))
 
 				//Verify the created Secret
 				var createdSecret apiv1.Secret
@@ -121,6 +124,78 @@ var _ = Describe("OAuth2Client Controller", func() {
 				mgrStopped.Wait()
 			})
 
+			It("register the client with the client ID pinned in the spec", func() {
+
+				tstName, tstClientID, tstSecretName := "test6", "pinned-client-id", "my-secret-pinned"
+				var postedClient *hydraclient.OAuth2ClientJSON
+				expectedRequest := &reconcile.Request{NamespacedName: types.NamespacedName{Name: tstName, Namespace: tstNamespace}}
+
+				s := scheme.Scheme
+				err := hydrav1alpha1.AddToScheme(s)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = apiv1.AddToScheme(s)
+				Expect(err).NotTo(HaveOccurred())
+
+				// Setup the Manager and Controller.  Wrap the Controller Reconcile function so it writes each request to a
+				// channel when it is finished.
+				mgr, err := manager.New(cfg, manager.Options{Scheme: s})
+				Expect(err).NotTo(HaveOccurred())
+				c := mgr.GetClient()
+
+				mch := &mocks.HydraClientInterface{}
+				mch.On("GetOAuth2Client", Anything).Return(nil, false, nil)
+				mch.On("DeleteOAuth2Client", Anything).Return(nil)
+				mch.On("ListOAuth2Client", Anything).Return(nil, nil)
+				mch.On("PostOAuth2Client", AnythingOfType("*hydraclient.OAuth2ClientJSON")).Return(func(o *hydraclient.OAuth2ClientJSON) *hydraclient.OAuth2ClientJSON {
+					postedClient = o
+					return &hydraclient.OAuth2ClientJSON{
+						ClientID:   o.ClientID,
+						Secret:     pointer.StringPtr(tstSecret),
+						GrantTypes: o.GrantTypes,
+						Scope:      o.Scope,
+						Owner:      o.Owner,
+					}
+				}, func(o *hydraclient.OAuth2ClientJSON) error {
+					return nil
+				})
+
+				recFn, requests := SetupTestReconcile(getAPIReconciler(mgr, mch))
+
+				Expect(add(mgr, recFn)).To(Succeed())
+
+				//Start the manager and the controller
+				stopMgr, mgrStopped := StartTestManager(mgr)
+
+				instance := testInstance(tstName, tstSecretName)
+				instance.Spec.ClientID = tstClientID
+				err = c.Create(context.TODO(), instance)
+				if apierrors.IsInvalid(err) {
+					Fail(fmt.Sprintf("failed to create object, got an invalid object error: %v", err))
+					return
+				}
+				Expect(err).NotTo(HaveOccurred())
+				Eventually(requests, timeout).Should(Receive(Equal(*expectedRequest)))
+
+				//Verify the client was registered with the pinned ID
+				Expect(postedClient).NotTo(BeNil())
+				Expect(*postedClient.ClientID).To(Equal(tstClientID))
+
+				//Verify the created Secret
+				var createdSecret apiv1.Secret
+				ok := client.ObjectKey{Name: tstSecretName, Namespace: tstNamespace}
+				err = k8sClient.Get(context.TODO(), ok, &createdSecret)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(createdSecret.Data[controllers.ClientIDKey]).To(Equal([]byte(tstClientID)))
+
+				//delete instance
+				c.Delete(context.TODO(), instance)
+
+				//Ensure manager is stopped properly
+				close(stopMgr)
+				mgrStopped.Wait()
+			})
+
 			It("update object status if the call failed", func() {
 
 				tstName, tstSecretName := "test2", "my-secret-456"
@@ -171,6 +246,8 @@ var _ = Describe("OAuth2Client Controller", func() {
 				Expect(retrieved.Status.ReconciliationError).NotTo(BeNil())
 				Expect(retrieved.Status.ReconciliationError.Code).To(Equal(hydrav1alpha1.StatusRegistrationFailed))
 				Expect(retrieved.Status.ReconciliationError.Description).To(Equal("error"))
+				Expect(retrieved.Status.ObservedGeneration).To(BeZero())
+				Expect(retrieved.Status.GetCondition(hydrav1alpha1.OAuth2ClientConditionReady).Status).To(Equal(apiv1.ConditionFalse))
 
 				//Verify no secret has been created
 				var createdSecret apiv1.Secret
@@ -190,7 +267,7 @@ var _ = Describe("OAuth2Client Controller", func() {
 			It("use provided Secret if it exists", func() {
 
 				tstName, tstClientID, tstSecretName := "test3", "testClientID-3", "my-secret-789"
-				var postedClient *hydra.OAuth2ClientJSON
+				var postedClient *hydraclient.OAuth2ClientJSON
 				expectedRequest := &reconcile.Request{NamespacedName: types.NamespacedName{Name: tstName, Namespace: tstNamespace}}
 
 				s := scheme.Scheme
@@ -211,8 +288,8 @@ var _ = Describe("OAuth2Client Controller", func() {
 				mch.On("DeleteOAuth2Client", Anything).Return(nil)
 				mch.On("ListOAuth2Client", Anything).Return(nil, nil)
 				mch.On("GetOAuth2Client", Anything).Return(nil, false, nil)
-				mch.On("PostOAuth2Client", AnythingOfType("*hydra.OAuth2ClientJSON")).Return(func(o *hydra.OAuth2ClientJSON) *hydra.OAuth2ClientJSON {
-					postedClient = &hydra.OAuth2ClientJSON{
+				mch.On("PostOAuth2Client", AnythingOfType("*hydraclient.OAuth2ClientJSON")).Return(func(o *hydraclient.OAuth2ClientJSON) *hydraclient.OAuth2ClientJSON {
+					postedClient = &hydraclient.OAuth2ClientJSON{
 						ClientID:      o.ClientID,
 						Secret:        o.Secret,
 						GrantTypes:    o.GrantTypes,
@@ -223,7 +300,7 @@ var _ = Describe("OAuth2Client Controller", func() {
 						Owner:         o.Owner,
 					}
 					return postedClient
-				}, func(o *hydra.OAuth2ClientJSON) error {
+				}, func(o *hydraclient.OAuth2ClientJSON) error {
 					return nil
 				})
 
@@ -356,7 +433,7 @@ var _ = Describe("OAuth2Client Controller", func() {
 				mgrStopped.Wait()
 			})
 
-			It("tolerate nil client_secret if tokenEndpointAuthMethod is none", func() {
+			It("create no Secret if tokenEndpointAuthMethod is none", func() {
 				tstName, tstClientID, tstSecretName := "test5", "testClientID-5", "my-secret-without-client-secret"
 				expectedRequest := &reconcile.Request{NamespacedName: types.NamespacedName{Name: tstName, Namespace: tstNamespace}}
 
@@ -377,8 +454,8 @@ var _ = Describe("OAuth2Client Controller", func() {
 				mch.On("GetOAuth2Client", Anything).Return(nil, false, nil)
 				mch.On("DeleteOAuth2Client", Anything).Return(nil)
 				mch.On("ListOAuth2Client", Anything).Return(nil, nil)
-				mch.On("PostOAuth2Client", AnythingOfType("*hydra.OAuth2ClientJSON")).Return(func(o *hydra.OAuth2ClientJSON) *hydra.OAuth2ClientJSON {
-					return &hydra.OAuth2ClientJSON{
+				mch.On("PostOAuth2Client", AnythingOfType("*hydraclient.OAuth2ClientJSON")).Return(func(o *hydraclient.OAuth2ClientJSON) *hydraclient.OAuth2ClientJSON {
+					return &hydraclient.OAuth2ClientJSON{
 						ClientID:      &tstClientID,
 						Secret:        nil,
 						GrantTypes:    o.GrantTypes,
@@ -388,7 +465,7 @@ var _ = Describe("OAuth2Client Controller", func() {
 						Audience:      o.Audience,
 						Owner:         o.Owner,
 					}
-				}, func(o *hydra.OAuth2ClientJSON) error {
+				}, func(o *hydraclient.OAuth2ClientJSON) error {
 					return nil
 				})
 
@@ -418,15 +495,14 @@ var _ = Describe("OAuth2Client Controller", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(retrieved.Status.ReconciliationError.Code).To(BeEmpty())
 				Expect(retrieved.Status.ReconciliationError.Description).To(BeEmpty())
+				Expect(retrieved.Status.ClientID).To(Equal(tstClientID))
+				Expect(retrieved.Status.IsConditionTrue(hydrav1alpha1.OAuth2ClientConditionPublicClient)).To(BeTrue())
 
-				//Verify the created Secret
+				//Verify that no Secret was created
 				var createdSecret apiv1.Secret
 				ok = client.ObjectKey{Name: tstSecretName, Namespace: tstNamespace}
 				err = k8sClient.Get(context.TODO(), ok, &createdSecret)
-				Expect(err).NotTo(HaveOccurred())
-				Expect(createdSecret.Data[controllers.ClientIDKey]).To(Equal([]byte(tstClientID)))
-				Expect(createdSecret.Data[controllers.ClientSecretKey]).To(BeNil())
-				Expect(createdSecret.OwnerReferences).To(Equal(getOwnerReferenceTo(retrieved)))
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
 
 				//delete instance
 				c.Delete(context.TODO(), instance)
@@ -435,16 +511,86 @@ var _ = Describe("OAuth2Client Controller", func() {
 				close(stopMgr)
 				mgrStopped.Wait()
 			})
+
+			It("delete the generated Secret when the client is deleted", func() {
+				tstName, tstClientID, tstSecretName := "test7", "testClientID-7", "my-secret-to-delete"
+				expectedRequest := &reconcile.Request{NamespacedName: types.NamespacedName{Name: tstName, Namespace: tstNamespace}}
+
+				s := scheme.Scheme
+				err := hydrav1alpha1.AddToScheme(s)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = apiv1.AddToScheme(s)
+				Expect(err).NotTo(HaveOccurred())
+
+				// Setup the Manager and Controller.  Wrap the Controller Reconcile function so it writes each request to a
+				// channel when it is finished.
+				mgr, err := manager.New(cfg, manager.Options{Scheme: s})
+				Expect(err).NotTo(HaveOccurred())
+				c := mgr.GetClient()
+
+				mch := &mocks.HydraClientInterface{}
+				mch.On("GetOAuth2Client", Anything).Return(nil, false, nil)
+				mch.On("DeleteOAuth2Client", Anything).Return(nil)
+				mch.On("ListOAuth2Client", Anything).Return(nil, nil)
+				mch.On("PostOAuth2Client", AnythingOfType("*hydraclient.OAuth2ClientJSON")).Return(func(o *hydraclient.OAuth2ClientJSON) *hydraclient.OAuth2ClientJSON {
+					return &hydraclient.OAuth2ClientJSON{
+						ClientID:      &tstClientID,
+						Secret:        pointer.StringPtr(tstSecret),
+						GrantTypes:    o.GrantTypes,
+						ResponseTypes: o.ResponseTypes,
+						RedirectURIs:  o.RedirectURIs,
+						Scope:         o.Scope,
+						Audience:      o.Audience,
+						Owner:         o.Owner,
+					}
+				}, func(o *hydraclient.OAuth2ClientJSON) error {
+					return nil
+				})
+
+				recFn, requests := SetupTestReconcile(getAPIReconciler(mgr, mch))
+
+				Expect(add(mgr, recFn)).To(Succeed())
+
+				//Start the manager and the controller
+				stopMgr, mgrStopped := StartTestManager(mgr)
+
+				instance := testInstance(tstName, tstSecretName)
+				err = c.Create(context.TODO(), instance)
+				Expect(err).NotTo(HaveOccurred())
+				Eventually(requests, timeout).Should(Receive(Equal(*expectedRequest)))
+
+				//Verify the created Secret
+				var createdSecret apiv1.Secret
+				ok := client.ObjectKey{Name: tstSecretName, Namespace: tstNamespace}
+				err = k8sClient.Get(context.TODO(), ok, &createdSecret)
+				Expect(err).NotTo(HaveOccurred())
+
+				//delete instance
+				Expect(c.Delete(context.TODO(), instance)).To(Succeed())
+
+				//Verify the Secret is deleted along with the instance
+				Eventually(func() bool {
+					err := k8sClient.Get(context.TODO(), ok, &createdSecret)
+					return apierrors.IsNotFound(err)
+				}, timeout).Should(BeTrue())
+
+				//Ensure manager is stopped properly
+				close(stopMgr)
+				mgrStopped.Wait()
+			})
 		})
 	})
 })
 
 func getOwnerReferenceTo(c hydrav1alpha1.OAuth2Client) []metav1.OwnerReference {
+	controller := true
 	return []metav1.OwnerReference{{
-		APIVersion: c.APIVersion,
-		Kind:       c.Kind,
+		APIVersion: hydrav1alpha1.GroupVersion.String(),
+		Kind:       "OAuth2Client",
 		Name:       c.Name,
 		UID:        c.UID,
+		Controller: &controller,
 	}}
 }
 
@@ -470,6 +616,7 @@ func getAPIReconciler(mgr ctrl.Manager, mock controllers.HydraClientInterface) r
 		Client:      mgr.GetClient(),
 		Log:         ctrl.Log.WithName("controllers").WithName("OAuth2Client"),
 		HydraClient: mock,
+		Recorder:    mgr.GetEventRecorderFor("hydra-maester"),
 		HydraClientMaker: func(hydrav1alpha1.OAuth2ClientSpec) (controllers.HydraClientInterface, error) {
 			return mock, nil
 		},
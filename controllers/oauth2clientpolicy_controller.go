@@ -0,0 +1,127 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// OAuth2ClientPolicyReconciler checks the OAuth2ClientPolicies and reports in
+// their status whether they are valid
+type OAuth2ClientPolicyReconciler struct {
+	client.Client
+	Log logr.Logger
+}
+
+// +kubebuilder:rbac:groups=hydra.ory.sh,resources=oauth2clientpolicies,verbs=get;list;watch
+// +kubebuilder:rbac:groups=hydra.ory.sh,resources=oauth2clientpolicies/status,verbs=get;update;patch
+
+func (r *OAuth2ClientPolicyReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.Background()
+	_ = r.Log.WithValues("oauth2clientpolicy", req.NamespacedName)
+
+	var policy hydrav1alpha1.OAuth2ClientPolicy
+	if err := r.Get(ctx, req.NamespacedName, &policy); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	var reconciliationError hydrav1alpha1.ReconciliationError
+	if err := policy.Validate(); err != nil {
+		reconciliationError = hydrav1alpha1.ReconciliationError{Code: hydrav1alpha1.StatusInvalidSpec, Description: err.Error()}
+	}
+
+	if policy.Status.ObservedGeneration == policy.Generation && policy.Status.ReconciliationError == reconciliationError {
+		return ctrl.Result{}, nil
+	}
+	policy.Status.ObservedGeneration = policy.Generation
+	policy.Status.ReconciliationError = reconciliationError
+	if err := r.Status().Update(ctx, &policy); err != nil {
+		r.Log.Error(err, "status update failed for OAuth2ClientPolicy", "name", policy.Name)
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+func (r *OAuth2ClientPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&hydrav1alpha1.OAuth2ClientPolicy{}).
+		Complete(r)
+}
+
+// policyViolations returns how c violates the policies applying to its
+// namespace, split by the enforcement action of the violated policies. An
+// invalid policy fails the reconciliation rather than being ignored.
+func (r *OAuth2ClientReconciler) policyViolations(ctx context.Context, c *hydrav1alpha1.OAuth2Client) (denied, warned []string, err error) {
+	var policies hydrav1alpha1.OAuth2ClientPolicyList
+	if err := r.List(ctx, &policies); err != nil {
+		return nil, nil, err
+	}
+	if len(policies.Items) == 0 {
+		return nil, nil, nil
+	}
+
+	var ns apiv1.Namespace
+	if err := r.Get(ctx, types.NamespacedName{Name: c.Namespace}, &ns); err != nil {
+		return nil, nil, err
+	}
+
+	for i := range policies.Items {
+		policy := &policies.Items[i]
+		if err := policy.Validate(); err != nil {
+			return nil, nil, fmt.Errorf("OAuth2ClientPolicy %s is invalid: %w", policy.Name, err)
+		}
+		selector := labels.Everything()
+		if policy.Spec.NamespaceSelector != nil {
+			// the selector has been checked by Validate
+			selector, _ = metav1.LabelSelectorAsSelector(policy.Spec.NamespaceSelector)
+		}
+		if !selector.Matches(labels.Set(ns.Labels)) {
+			continue
+		}
+
+		for _, violation := range policy.Violations(c) {
+			violation = fmt.Sprintf("%s (policy %s)", violation, policy.Name)
+			if policy.GetEnforcementAction() == hydrav1alpha1.PolicyEnforcementWarn {
+				warned = append(warned, violation)
+			} else {
+				denied = append(denied, violation)
+			}
+		}
+	}
+	return denied, warned, nil
+}
+
+// policyToOAuth2Clients maps OAuth2ClientPolicies to all OAuth2Clients, so
+// that their changes take effect right away
+func (r *OAuth2ClientReconciler) policyToOAuth2Clients() handler.EventHandler {
+	return &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+			return r.allOAuth2ClientRequests("oauth2clientpolicy", o.Meta.GetName())
+		}),
+	}
+}
@@ -0,0 +1,139 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"sort"
+
+	"github.com/go-logr/logr"
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// maxErrorSamples is the maximum number of reconciliation errors kept in
+// the status of an OAuth2ClientSummary
+const maxErrorSamples = 5
+
+// OAuth2ClientSummaryReconciler maintains an OAuth2ClientSummary in each
+// namespace with OAuth2Clients
+type OAuth2ClientSummaryReconciler struct {
+	client.Client
+	Log logr.Logger
+}
+
+// +kubebuilder:rbac:groups=hydra.ory.sh,resources=oauth2clientsummaries,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=hydra.ory.sh,resources=oauth2clientsummaries/status,verbs=get;update;patch
+
+func (r *OAuth2ClientSummaryReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.Background()
+	_ = r.Log.WithValues("oauth2clientsummary", req.NamespacedName)
+
+	if req.Name != hydrav1alpha1.OAuth2ClientSummaryName {
+		return ctrl.Result{}, nil
+	}
+
+	var clients hydrav1alpha1.OAuth2ClientList
+	if err := r.List(ctx, &clients, client.InNamespace(req.Namespace)); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	var summary hydrav1alpha1.OAuth2ClientSummary
+	err := r.Get(ctx, req.NamespacedName, &summary)
+	if err != nil && !apierrs.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+	exists := err == nil
+
+	if len(clients.Items) == 0 {
+		if exists {
+			if err := r.Delete(ctx, &summary); err != nil && !apierrs.IsNotFound(err) {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if !exists {
+		summary = hydrav1alpha1.OAuth2ClientSummary{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      req.Name,
+				Namespace: req.Namespace,
+			},
+		}
+		if err := r.Create(ctx, &summary); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	status := summarize(clients.Items)
+	if exists && reflect.DeepEqual(summary.Status, status) {
+		return ctrl.Result{}, nil
+	}
+
+	summary.Status = status
+	if err := r.Status().Update(ctx, &summary); err != nil {
+		r.Log.Error(err, "status update failed for OAuth2Client summary", "namespace", req.Namespace)
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+func (r *OAuth2ClientSummaryReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&hydrav1alpha1.OAuth2ClientSummary{}).
+		Watches(&source.Kind{Type: &hydrav1alpha1.OAuth2Client{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+				return []reconcile.Request{{NamespacedName: types.NamespacedName{
+					Name:      hydrav1alpha1.OAuth2ClientSummaryName,
+					Namespace: o.Meta.GetNamespace(),
+				}}}
+			}),
+		}).
+		Complete(r)
+}
+
+// summarize aggregates the status of clients
+func summarize(clients []hydrav1alpha1.OAuth2Client) hydrav1alpha1.OAuth2ClientSummaryStatus {
+	sort.Slice(clients, func(i, j int) bool { return clients[i].Name < clients[j].Name })
+
+	var status hydrav1alpha1.OAuth2ClientSummaryStatus
+	for _, c := range clients {
+		switch {
+		case c.Status.ReconciliationError.Code != "":
+			status.Failed++
+			if len(status.Errors) < maxErrorSamples {
+				status.Errors = append(status.Errors, hydrav1alpha1.OAuth2ClientErrorSample{
+					Name:                c.Name,
+					ReconciliationError: c.Status.ReconciliationError,
+				})
+			}
+		case c.Generation == c.Status.ObservedGeneration:
+			status.Ready++
+		default:
+			status.Pending++
+		}
+	}
+	return status
+}
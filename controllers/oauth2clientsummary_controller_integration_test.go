@@ -0,0 +1,61 @@
+package controllers_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	"github.com/ory/hydra-maester/controllers"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+var _ = Describe("OAuth2ClientSummary Controller", func() {
+
+	It("should summarize the OAuth2Clients of a namespace", func() {
+
+		s := scheme.Scheme
+		err := hydrav1alpha1.AddToScheme(s)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = apiv1.AddToScheme(s)
+		Expect(err).NotTo(HaveOccurred())
+
+		mgr, err := manager.New(cfg, manager.Options{Scheme: s})
+		Expect(err).NotTo(HaveOccurred())
+		c := mgr.GetClient()
+
+		err = (&controllers.OAuth2ClientSummaryReconciler{
+			Client: c,
+			Log:    ctrl.Log.WithName("controllers").WithName("OAuth2ClientSummary"),
+		}).SetupWithManager(mgr)
+		Expect(err).NotTo(HaveOccurred())
+
+		//Start the manager and the controller
+		stopMgr, mgrStopped := StartTestManager(mgr)
+
+		instance := testInstance("summary-test", "summary-test-secret")
+		Expect(c.Create(context.TODO(), instance)).To(Succeed())
+
+		//Verify the summary counts the client as pending, as it is not reconciled
+		var summary hydrav1alpha1.OAuth2ClientSummary
+		key := types.NamespacedName{Name: hydrav1alpha1.OAuth2ClientSummaryName, Namespace: tstNamespace}
+		Eventually(func() int32 {
+			if err := k8sClient.Get(context.TODO(), key, &summary); err != nil {
+				return 0
+			}
+			return summary.Status.Pending
+		}, timeout).Should(BeNumerically(">=", 1))
+
+		//delete instance
+		c.Delete(context.TODO(), instance)
+
+		//Ensure manager is stopped properly
+		close(stopMgr)
+		mgrStopped.Wait()
+	})
+})
@@ -0,0 +1,222 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"regexp"
+	"text/template"
+	"time"
+
+	"github.com/go-logr/logr"
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Option configures the OAuth2ClientReconciler built by New
+type Option func(*OAuth2ClientReconciler)
+
+// WithHydraClient sets the client of the default ORY Hydra instance. It is
+// required.
+func WithHydraClient(c ClientRegistrar) Option {
+	return func(r *OAuth2ClientReconciler) {
+		r.HydraClient = c
+	}
+}
+
+// WithClientRegistrar sets the registrar of the default authorization
+// server, e.g. a backend other than ORY Hydra or a fake for tests. It is the
+// same as WithHydraClient.
+func WithClientRegistrar(registrar ClientRegistrar) Option {
+	return WithHydraClient(registrar)
+}
+
+// WithHydraClientMaker sets the function building clients for the ORY Hydra
+// instances set in the spec of OAuth2Clients. Without it, such OAuth2Clients
+// fail with an invalid ORY Hydra address.
+func WithHydraClientMaker(maker HydraClientMakerFunc) Option {
+	return func(r *OAuth2ClientReconciler) {
+		r.HydraClientMaker = maker
+	}
+}
+
+// WithHydraPublicURL sets ORY Hydra's public address
+func WithHydraPublicURL(u string) Option {
+	return func(r *OAuth2ClientReconciler) {
+		r.HydraPublicURL = u
+	}
+}
+
+// WithRedirectURIAllowPattern sets the pattern all redirect URIs must match
+// in namespaces which don't override it
+func WithRedirectURIAllowPattern(pattern *regexp.Regexp) Option {
+	return func(r *OAuth2ClientReconciler) {
+		r.RedirectURIAllowPattern = pattern
+	}
+}
+
+// WithDriftDetectionInterval sets the interval at which registered clients
+// are compared with ORY Hydra
+func WithDriftDetectionInterval(interval time.Duration) Option {
+	return func(r *OAuth2ClientReconciler) {
+		r.DriftDetectionInterval = interval
+	}
+}
+
+// WithReconcileTimeout sets the time budget of a reconciliation
+func WithReconcileTimeout(timeout time.Duration) Option {
+	return func(r *OAuth2ClientReconciler) {
+		r.ReconcileTimeout = timeout
+	}
+}
+
+// WithMaxConcurrentReconciles sets the number of OAuth2Clients reconciled in
+// parallel
+func WithMaxConcurrentReconciles(n int) Option {
+	return func(r *OAuth2ClientReconciler) {
+		r.MaxConcurrentReconciles = n
+	}
+}
+
+// WithStrictFields fails the reconciliation of clients whose last applied
+// manifest has spec fields unknown to the controller
+func WithStrictFields(strict bool) Option {
+	return func(r *OAuth2ClientReconciler) {
+		r.StrictFields = strict
+	}
+}
+
+// WithVerificationImage enables the verification of clients' credentials
+// with Jobs running the given image, which must provide sh and curl
+func WithVerificationImage(image string) Option {
+	return func(r *OAuth2ClientReconciler) {
+		r.VerificationImage = image
+	}
+}
+
+// WithAuditMode only compares OAuth2Clients with ORY Hydra and reports their
+// drift, without changing ORY Hydra
+func WithAuditMode(audit bool) Option {
+	return func(r *OAuth2ClientReconciler) {
+		r.AuditMode = audit
+	}
+}
+
+// WithLabelSelector restricts the reconciler to the OAuth2Clients matching
+// selector
+func WithLabelSelector(selector labels.Selector) Option {
+	return func(r *OAuth2ClientReconciler) {
+		r.LabelSelector = selector
+	}
+}
+
+// WithWatchNamespaces restricts the reconciler to the OAuth2Clients of
+// namespaces, all namespaces if it is empty
+func WithWatchNamespaces(namespaces []string) Option {
+	return func(r *OAuth2ClientReconciler) {
+		r.WatchNamespaces = namespaces
+	}
+}
+
+// WithExcludeNamespaces keeps the reconciler away from the OAuth2Clients of
+// namespaces
+func WithExcludeNamespaces(namespaces []string) Option {
+	return func(r *OAuth2ClientReconciler) {
+		r.ExcludeNamespaces = namespaces
+	}
+}
+
+// WithSecretKeys sets the keys holding the client ID and secret in the
+// Secrets of OAuth2Clients without a SecretProjection, client_id and
+// client_secret if they are empty
+func WithSecretKeys(clientIDKey, clientSecretKey string) Option {
+	return func(r *OAuth2ClientReconciler) {
+		r.SecretClientIDKey = clientIDKey
+		r.SecretClientSecretKey = clientSecretKey
+	}
+}
+
+// WithCredentialStore keeps the credentials of clients in store as well, at
+// the paths rendered from pathTemplate, DefaultCredentialStorePath if it is
+// nil. If only is set, no Secrets are created for them.
+func WithCredentialStore(store CredentialStore, pathTemplate *template.Template, only bool) Option {
+	return func(r *OAuth2ClientReconciler) {
+		r.CredentialStore = store
+		r.CredentialStorePath = pathTemplate
+		r.CredentialStoreOnly = only
+	}
+}
+
+// WithClientSecretPolicy sets the policy of the client secrets generated by
+// the controller on rotations. If generate is set, the controller also
+// generates the client secrets of new clients instead of ORY Hydra.
+func WithClientSecretPolicy(policy *ClientSecretPolicy, generate bool) Option {
+	return func(r *OAuth2ClientReconciler) {
+		r.ClientSecretPolicy = policy
+		r.GenerateClientSecrets = generate
+	}
+}
+
+// WithClient sets the Kubernetes client used to read and write OAuth2Clients
+// and their Secrets, instead of the client of the manager
+func WithClient(c client.Client) Option {
+	return func(r *OAuth2ClientReconciler) {
+		r.Client = c
+	}
+}
+
+// WithRecorder sets the recorder events are emitted with, instead of a
+// recorder of the manager named hydra-maester
+func WithRecorder(recorder record.EventRecorder) Option {
+	return func(r *OAuth2ClientReconciler) {
+		r.Recorder = recorder
+	}
+}
+
+// WithLogger sets the logger of the reconciler
+func WithLogger(log logr.Logger) Option {
+	return func(r *OAuth2ClientReconciler) {
+		r.Log = log
+	}
+}
+
+// New builds an OAuth2ClientReconciler configured by opts and registers it
+// with mgr, so that operators embedding the controller don't depend on how
+// it is wired
+func New(mgr ctrl.Manager, opts ...Option) (*OAuth2ClientReconciler, error) {
+	r := &OAuth2ClientReconciler{
+		Client:   mgr.GetClient(),
+		Recorder: mgr.GetEventRecorderFor("hydra-maester"),
+		Log:      ctrl.Log.WithName("controllers").WithName("OAuth2Client"),
+		HydraClientMaker: func(spec hydrav1alpha1.OAuth2ClientSpec) (ClientRegistrar, error) {
+			return nil, fmt.Errorf("no client can be made for ORY Hydra at %s:%d", spec.HydraAdmin.URL, spec.HydraAdmin.Port)
+		},
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if r.HydraClient == nil {
+		return nil, fmt.Errorf("a client of ORY Hydra is required")
+	}
+	if err := r.SetupWithManager(mgr); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
@@ -0,0 +1,82 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// contentChangedPredicate drops update events of OAuth2Clients whose content
+// is identical to the previous version, such as the same manifest being
+// re-applied by a GitOps tool. Periodic resyncs, which carry an unchanged
+// resource version, are let through.
+var contentChangedPredicate = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		if e.MetaOld == nil || e.MetaNew == nil || e.MetaOld.GetResourceVersion() == e.MetaNew.GetResourceVersion() {
+			return true
+		}
+
+		oldClient, ok := e.ObjectOld.(*hydrav1alpha1.OAuth2Client)
+		if !ok {
+			return true
+		}
+		newClient, ok := e.ObjectNew.(*hydrav1alpha1.OAuth2Client)
+		if !ok {
+			return true
+		}
+
+		oldHash, err := contentHash(oldClient)
+		if err != nil {
+			return true
+		}
+		newHash, err := contentHash(newClient)
+		if err != nil {
+			return true
+		}
+
+		if oldHash == newHash {
+			suppressedSyncs.Inc()
+			return false
+		}
+		return true
+	},
+}
+
+// contentHash hashes the parts of an OAuth2Client the reconciler acts upon
+func contentHash(c *hydrav1alpha1.OAuth2Client) ([sha256.Size]byte, error) {
+	raw, err := json.Marshal(struct {
+		Spec              hydrav1alpha1.OAuth2ClientSpec
+		Labels            map[string]string
+		Annotations       map[string]string
+		Finalizers        []string
+		DeletionTimestamp bool
+	}{
+		Spec:              c.Spec,
+		Labels:            c.Labels,
+		Annotations:       c.Annotations,
+		Finalizers:        c.Finalizers,
+		DeletionTimestamp: c.DeletionTimestamp != nil,
+	})
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+	return sha256.Sum256(raw), nil
+}
@@ -0,0 +1,118 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestContentChangedPredicate(t *testing.T) {
+
+	client := func(resourceVersion string) *hydrav1alpha1.OAuth2Client {
+		return &hydrav1alpha1.OAuth2Client{
+			ObjectMeta: metav1.ObjectMeta{Name: "client", Namespace: "default", ResourceVersion: resourceVersion, Generation: 1},
+			Spec: hydrav1alpha1.OAuth2ClientSpec{
+				GrantTypes: []hydrav1alpha1.GrantType{"client_credentials"},
+				Scope:      "read",
+				SecretName: "client-secret",
+			},
+		}
+	}
+
+	for _, tc := range []struct {
+		desc       string
+		update     func(c *hydrav1alpha1.OAuth2Client)
+		old        runtime.Object
+		new        runtime.Object
+		expected   bool
+		suppressed bool
+	}{
+		{
+			desc: "status-only update",
+			update: func(c *hydrav1alpha1.OAuth2Client) {
+				c.Status.ObservedGeneration = 1
+				c.Status.ClientID = "client-id"
+			},
+			suppressed: true,
+		},
+		{
+			desc: "metadata-only update",
+			update: func(c *hydrav1alpha1.OAuth2Client) {
+				c.ManagedFields = []metav1.ManagedFieldsEntry{{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationApply}}
+			},
+			suppressed: true,
+		},
+		{
+			desc: "spec change",
+			update: func(c *hydrav1alpha1.OAuth2Client) {
+				c.Spec.Scope = "read write"
+				c.Generation = 2
+			},
+			expected: true,
+		},
+		{
+			desc: "label change",
+			update: func(c *hydrav1alpha1.OAuth2Client) {
+				c.Labels = map[string]string{"team": "payments"}
+			},
+			expected: true,
+		},
+		{
+			desc:     "resync",
+			old:      client("1"),
+			new:      client("1"),
+			expected: true,
+		},
+		{
+			desc:     "other kind",
+			old:      &apiv1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "client-secret", ResourceVersion: "1"}},
+			new:      &apiv1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "client-secret", ResourceVersion: "2"}},
+			expected: true,
+		},
+	} {
+		t.Run("case="+tc.desc, func(t *testing.T) {
+			oldObject, newObject := tc.old, tc.new
+			if tc.update != nil {
+				updated := client("2")
+				tc.update(updated)
+				oldObject, newObject = client("1"), updated
+			}
+
+			before := testutil.ToFloat64(suppressedSyncs)
+			passed := contentChangedPredicate.Update(event.UpdateEvent{
+				MetaOld:   oldObject.(metav1.Object),
+				ObjectOld: oldObject,
+				MetaNew:   newObject.(metav1.Object),
+				ObjectNew: newObject,
+			})
+
+			assert.Equal(t, tc.expected, passed)
+			suppressed := 0.0
+			if tc.suppressed {
+				suppressed = 1
+			}
+			assert.Equal(t, suppressed, testutil.ToFloat64(suppressedSyncs)-before)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	"github.com/ory/hydra-maester/pkg/hydraclient"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+// markPublicClient sets the PublicClient condition of c, which has no
+// Secret. It reports whether the condition changed.
+func markPublicClient(c *hydrav1alpha1.OAuth2Client) bool {
+	if !c.IsPublic() {
+		return false
+	}
+	return c.Status.SetCondition(hydrav1alpha1.OAuth2ClientConditionPublicClient, apiv1.ConditionTrue, "NoClientSecret",
+		"client authenticates without a client secret, no Secret is created")
+}
+
+// publicClientCredentials returns the credentials of c if it is a public
+// client with a known ID, the one in its spec or else the one it was
+// registered with. It returns nil if c is yet to be registered.
+func publicClientCredentials(c *hydrav1alpha1.OAuth2Client) *hydraclient.Oauth2ClientCredentials {
+	if !c.IsPublic() {
+		return nil
+	}
+	id := c.Spec.ClientID
+	if id == "" {
+		id = c.Status.ClientID
+	}
+	if id == "" {
+		return nil
+	}
+	return &hydraclient.Oauth2ClientCredentials{ID: []byte(id)}
+}
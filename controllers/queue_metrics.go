@@ -0,0 +1,117 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// pendingReconciles holds the requests of the OAuth2Client controller that
+// have been enqueued by a watch event but not reconciled yet
+var pendingReconciles = &pendingRequests{since: map[reconcile.Request]time.Time{}}
+
+// pendingRequests records when requests were first enqueued
+type pendingRequests struct {
+	mu    sync.Mutex
+	since map[reconcile.Request]time.Time
+}
+
+// add records req as pending unless it already is, as the queue
+// deduplicates it in that case
+func (p *pendingRequests) add(req reconcile.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.since[req]; !ok {
+		p.since[req] = time.Now()
+	}
+}
+
+// done removes req and returns how long it has been pending
+func (p *pendingRequests) done(req reconcile.Request) (time.Duration, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	since, ok := p.since[req]
+	if !ok {
+		return 0, false
+	}
+	delete(p.since, req)
+	return time.Since(since), true
+}
+
+// oldest returns how long the oldest pending request has been waiting
+func (p *pendingRequests) oldest() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var oldest time.Duration
+	for _, since := range p.since {
+		if age := time.Since(since); age > oldest {
+			oldest = age
+		}
+	}
+	return oldest
+}
+
+// observeReconcileLag records how long req waited in the queue
+func observeReconcileLag(req reconcile.Request) {
+	if lag, ok := pendingReconciles.done(req); ok {
+		reconcileLag.WithLabelValues(req.Namespace).Observe(lag.Seconds())
+	}
+}
+
+// trackPending wraps h so that the requests it enqueues are recorded in
+// pendingReconciles
+func trackPending(h handler.EventHandler) handler.EventHandler {
+	return &pendingTracker{EventHandler: h}
+}
+
+type pendingTracker struct {
+	handler.EventHandler
+}
+
+func (t *pendingTracker) Create(e event.CreateEvent, q workqueue.RateLimitingInterface) {
+	t.EventHandler.Create(e, &trackingQueue{q})
+}
+
+func (t *pendingTracker) Update(e event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	t.EventHandler.Update(e, &trackingQueue{q})
+}
+
+func (t *pendingTracker) Delete(e event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	t.EventHandler.Delete(e, &trackingQueue{q})
+}
+
+func (t *pendingTracker) Generic(e event.GenericEvent, q workqueue.RateLimitingInterface) {
+	t.EventHandler.Generic(e, &trackingQueue{q})
+}
+
+// trackingQueue records requests before adding them to the queue, so that
+// a worker can't pick them up before they are recorded
+type trackingQueue struct {
+	workqueue.RateLimitingInterface
+}
+
+func (q *trackingQueue) Add(item interface{}) {
+	if req, ok := item.(reconcile.Request); ok {
+		pendingReconciles.add(req)
+	}
+	q.RateLimitingInterface.Add(item)
+}
@@ -0,0 +1,76 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// RedirectURIAllowPatternAnnotation overrides the controller-wide redirect
+// URI allow pattern for all OAuth2Clients in the annotated namespace
+const RedirectURIAllowPatternAnnotation = "hydra-maester.ory.sh/redirect-uri-allow-pattern"
+
+// CompileRedirectURIAllowPattern compiles a redirect URI allow pattern. The
+// pattern has to match redirect URIs as a whole.
+func CompileRedirectURIAllowPattern(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	return regexp.Compile(fmt.Sprintf("^(?:%s)$", pattern))
+}
+
+// redirectURIAllowPattern returns the allow pattern in effect for the given
+// namespace, which defaults to fallback, or nil if all redirect URIs are
+// allowed
+func (r *OAuth2ClientReconciler) redirectURIAllowPattern(ctx context.Context, namespace string, fallback *regexp.Regexp) (*regexp.Regexp, error) {
+	var ns apiv1.Namespace
+	if err := r.Get(ctx, types.NamespacedName{Name: namespace}, &ns); err != nil {
+		return nil, err
+	}
+
+	if pattern, ok := ns.Annotations[RedirectURIAllowPatternAnnotation]; ok {
+		compiled, err := CompileRedirectURIAllowPattern(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s annotation on namespace %s: %w", RedirectURIAllowPatternAnnotation, namespace, err)
+		}
+		return compiled, nil
+	}
+
+	return fallback, nil
+}
+
+// checkRedirectURIs returns an error naming the first redirect URI of c
+// which is not matched by pattern
+func checkRedirectURIs(c *hydrav1alpha1.OAuth2Client, pattern *regexp.Regexp) error {
+	if pattern == nil {
+		return nil
+	}
+
+	for _, uris := range [][]hydrav1alpha1.RedirectURI{c.Spec.RedirectURIs, c.Spec.PostLogoutRedirectURIs} {
+		for _, uri := range uris {
+			if !pattern.MatchString(string(uri)) {
+				return fmt.Errorf("redirect URI %s is not allowed by pattern %s", uri, pattern)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"github.com/ory/hydra-maester/pkg/hydraclient"
+)
+
+// ClientRegistrar manages OAuth2 clients in an authorization server. The
+// client of ORY Hydra's admin API in pkg/hydraclient is the default
+// implementation; other backends, e.g. Ory Network, and fakes for tests can
+// be plugged in with WithClientRegistrar and WithHydraClientMaker.
+//
+// Implementations may additionally implement HydraKeysClient,
+// HydraTrustClient, HydraTokensClient, HydraVersionClient and
+// HydraHealthClient to enable the matching features.
+type ClientRegistrar interface {
+	// GetOAuth2Client returns the client with the given ID, and whether it
+	// exists
+	GetOAuth2Client(id string) (*hydraclient.OAuth2ClientJSON, bool, error)
+	// ListOAuth2Client returns all clients
+	ListOAuth2Client() ([]*hydraclient.OAuth2ClientJSON, error)
+	// PostOAuth2Client registers a client and returns it as registered
+	PostOAuth2Client(o *hydraclient.OAuth2ClientJSON) (*hydraclient.OAuth2ClientJSON, error)
+	// PutOAuth2Client replaces a registered client and returns it as updated
+	PutOAuth2Client(o *hydraclient.OAuth2ClientJSON) (*hydraclient.OAuth2ClientJSON, error)
+	// DeleteOAuth2Client deletes the client with the given ID, which is not
+	// an error if it doesn't exist
+	DeleteOAuth2Client(id string) error
+}
+
+// HydraClientInterface is the former name of ClientRegistrar, kept for
+// operators embedding the controller
+type HydraClientInterface = ClientRegistrar
+
+var _ ClientRegistrar = &hydraclient.Client{}
+var _ HydraTokensClient = &hydraclient.Client{}
+var _ HydraHealthClient = &hydraclient.Client{}
@@ -0,0 +1,89 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	apiv1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// takesOverSecrets reports whether the SecretConflictPolicy of c lets the
+// controller take over a Secret it didn't create
+func takesOverSecrets(c *hydrav1alpha1.OAuth2Client) bool {
+	return c.Spec.SecretConflictPolicy == hydrav1alpha1.SecretConflictPolicyAdopt ||
+		c.Spec.SecretConflictPolicy == hydrav1alpha1.SecretConflictPolicyOverwrite
+}
+
+// resolveSecretConflict writes desired, the Secret generated for c, over the
+// Secret of the same name which exists although the controller didn't create
+// it, as the SecretConflictPolicy of c allows. Once it returns, desired holds
+// the Secret written.
+func (r *OAuth2ClientReconciler) resolveSecretConflict(ctx context.Context, c *hydrav1alpha1.OAuth2Client, desired *apiv1.Secret) error {
+	if !takesOverSecrets(c) {
+		return fmt.Errorf("secret %s/%s already exists, set spec.secretName to a Secret which does not exist yet or spec.secretConflictPolicy to Adopt or Overwrite", desired.Namespace, desired.Name)
+	}
+
+	var existing apiv1.Secret
+	if err := r.Get(ctx, types.NamespacedName{Name: desired.Name, Namespace: desired.Namespace}, &existing); err != nil {
+		return err
+	}
+
+	if c.Spec.SecretConflictPolicy == hydrav1alpha1.SecretConflictPolicyOverwrite {
+		if err := r.Delete(ctx, &existing); err != nil && !apierrs.IsNotFound(err) {
+			return err
+		}
+		if err := r.Create(ctx, desired); err != nil {
+			return err
+		}
+		r.recordEvent(c, apiv1.EventTypeNormal, EventReasonSecretOverwritten, fmt.Sprintf("overwrote existing secret %s with the client credentials", desired.Name))
+		return nil
+	}
+
+	if controller := metav1.GetControllerOf(&existing); controller != nil && controller.UID != c.UID {
+		return fmt.Errorf("secret %s/%s already exists and is controlled by %s %s, it can't be adopted", existing.Namespace, existing.Name, controller.Kind, controller.Name)
+	}
+	if !isOwnedBy(&existing, c) {
+		existing.OwnerReferences = append(existing.OwnerReferences, ownerReferenceTo(c))
+	}
+	controlSecret(&existing, c)
+	for key, value := range desired.Annotations {
+		if existing.Annotations == nil {
+			existing.Annotations = map[string]string{}
+		}
+		existing.Annotations[key] = value
+	}
+	if existing.Type != desired.Type && (existing.Type != "" || desired.Type != apiv1.SecretTypeOpaque) {
+		// the type of a Secret can't be updated
+		if err := r.replaceSecret(ctx, c, &existing, desired.Data); err != nil {
+			return err
+		}
+	} else {
+		existing.Data = desired.Data
+		labelSecret(&existing, c)
+		if err := r.Update(ctx, &existing); err != nil {
+			return err
+		}
+	}
+	r.recordEvent(c, apiv1.EventTypeNormal, EventReasonSecretAdopted, fmt.Sprintf("adopted existing secret %s and wrote the client credentials to it", existing.Name))
+	*desired = existing
+	return nil
+}
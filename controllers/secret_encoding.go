@@ -0,0 +1,130 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+// SecretEncodingsAnnotation lists the keys of the Secrets generated for
+// OAuth2Clients whose values are not written as they are, with their
+// encoding, e.g. `client_secret=Hex`, so that the Secrets are still read
+// correctly after the encodings of their SecretProjection changed
+const SecretEncodingsAnnotation = "hydra-maester.ory.sh/encodings"
+
+// encodeSecretValue encodes value as the given encoding
+func encodeSecretValue(value string, encoding hydrav1alpha1.SecretValueEncoding) string {
+	switch encoding {
+	case hydrav1alpha1.SecretValueEncodingHex:
+		return hex.EncodeToString([]byte(value))
+	case hydrav1alpha1.SecretValueEncodingBase64URL:
+		return base64.RawURLEncoding.EncodeToString([]byte(value))
+	default:
+		return value
+	}
+}
+
+// decodeSecretValue decodes value, encoded as the given encoding
+func decodeSecretValue(value []byte, encoding hydrav1alpha1.SecretValueEncoding) ([]byte, error) {
+	switch encoding {
+	case hydrav1alpha1.SecretValueEncodingHex:
+		decoded := make([]byte, hex.DecodedLen(len(value)))
+		if _, err := hex.Decode(decoded, value); err != nil {
+			return nil, err
+		}
+		return decoded, nil
+	case hydrav1alpha1.SecretValueEncodingBase64URL:
+		decoded := make([]byte, base64.RawURLEncoding.DecodedLen(len(value)))
+		n, err := base64.RawURLEncoding.Decode(decoded, value)
+		if err != nil {
+			return nil, err
+		}
+		return decoded[:n], nil
+	default:
+		return value, nil
+	}
+}
+
+// encodingOf returns the encoding of property in p
+func encodingOf(p hydrav1alpha1.SecretProjection, property hydrav1alpha1.SecretProperty) hydrav1alpha1.SecretValueEncoding {
+	for _, item := range p.Items {
+		if item.Property == property {
+			return item.Encoding
+		}
+	}
+	return ""
+}
+
+// projectionEncodings returns the encodings of the keys of p, leaving out
+// the values written as they are
+func projectionEncodings(p *hydrav1alpha1.SecretProjection) map[string]hydrav1alpha1.SecretValueEncoding {
+	encodings := map[string]hydrav1alpha1.SecretValueEncoding{}
+	if p == nil {
+		return encodings
+	}
+	for _, item := range p.Items {
+		if item.Encoding != "" && item.Encoding != hydrav1alpha1.SecretValueEncodingRaw {
+			encodings[item.GetKey()] = item.Encoding
+		}
+	}
+	return encodings
+}
+
+// secretEncodings returns the encodings of the keys of secret, read from
+// the encodings annotation if the controller generated secret for c, or
+// from p otherwise
+func secretEncodings(secret apiv1.Secret, c *hydrav1alpha1.OAuth2Client, p hydrav1alpha1.SecretProjection) map[string]hydrav1alpha1.SecretValueEncoding {
+	if !isOwnedBy(&secret, c) {
+		return projectionEncodings(&p)
+	}
+	encodings := map[string]hydrav1alpha1.SecretValueEncoding{}
+	for _, entry := range splitList(secret.Annotations[SecretEncodingsAnnotation]) {
+		if i := strings.LastIndex(entry, "="); i > 0 {
+			encodings[entry[:i]] = hydrav1alpha1.SecretValueEncoding(entry[i+1:])
+		}
+	}
+	return encodings
+}
+
+// annotateEncodings sets the encodings annotation of the Secret generated
+// for c, or removes it if all of its values are written as they are. It
+// reports whether the annotation changed.
+func annotateEncodings(secret *apiv1.Secret, c *hydrav1alpha1.OAuth2Client) bool {
+	encodings := projectionEncodings(c.Spec.SecretProjection)
+	current, found := secret.Annotations[SecretEncodingsAnnotation]
+	if len(encodings) == 0 {
+		delete(secret.Annotations, SecretEncodingsAnnotation)
+		return found
+	}
+	entries := make([]string, 0, len(encodings))
+	for key, encoding := range encodings {
+		entries = append(entries, fmt.Sprintf("%s=%s", key, encoding))
+	}
+	sort.Strings(entries)
+	desired := strings.Join(entries, ",")
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Annotations[SecretEncodingsAnnotation] = desired
+	return current != desired
+}
@@ -0,0 +1,67 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClientSecretExpiresAtAnnotation is set on generated secrets of clients with a SecretTTL
+const ClientSecretExpiresAtAnnotation = "hydra-maester.ory.sh/client-secret-expires-at"
+
+// startSecretLifetime records the expiry of a newly issued client secret
+func startSecretLifetime(c *hydrav1alpha1.OAuth2Client) {
+	if c.Spec.SecretTTL == nil || hasManualSecret(c) {
+		c.Status.ClientSecretExpiresAt = nil
+		return
+	}
+	expiresAt := metav1.NewTime(time.Now().Add(c.Spec.SecretTTL.Duration).Truncate(time.Second))
+	c.Status.ClientSecretExpiresAt = &expiresAt
+}
+
+// ensureSecretLifetime keeps the recorded expiry of the client secret in
+// line with the SecretTTL, without extending the lifetime of a secret which
+// has already been issued
+func ensureSecretLifetime(c *hydrav1alpha1.OAuth2Client) {
+	if c.Spec.SecretTTL == nil || c.Status.ClientSecretExpiresAt == nil || hasManualSecret(c) {
+		startSecretLifetime(c)
+	}
+}
+
+// checkSecretExpiry sets the SecretExpired condition of c. It reports whether
+// the condition has changed and how long it takes for the secret to expire.
+func checkSecretExpiry(c *hydrav1alpha1.OAuth2Client) (bool, time.Duration) {
+	if c.Status.ClientSecretExpiresAt == nil {
+		if c.Status.GetCondition(hydrav1alpha1.OAuth2ClientConditionSecretExpired) == nil {
+			return false, 0
+		}
+		return c.Status.SetCondition(hydrav1alpha1.OAuth2ClientConditionSecretExpired, apiv1.ConditionFalse, "NoSecretTTL", ""), 0
+	}
+
+	expiresAt := c.Status.ClientSecretExpiresAt.Time
+	if remaining := time.Until(expiresAt); remaining > 0 {
+		message := fmt.Sprintf("client secret expires at %s", expiresAt.Format(time.RFC3339))
+		return c.Status.SetCondition(hydrav1alpha1.OAuth2ClientConditionSecretExpired, apiv1.ConditionFalse, "SecretValid", message), remaining
+	}
+
+	message := fmt.Sprintf("client secret expired at %s", expiresAt.Format(time.RFC3339))
+	return c.Status.SetCondition(hydrav1alpha1.OAuth2ClientConditionSecretExpired, apiv1.ConditionTrue, "SecretTTLExceeded", message), 0
+}
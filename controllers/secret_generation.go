@@ -0,0 +1,121 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math"
+	"math/big"
+)
+
+const (
+	alphanumericCharset = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+	base64URLCharset    = alphanumericCharset + "-_"
+	printableCharset    = alphanumericCharset + "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+)
+
+// ClientSecretCharsets are the named sets of characters client secrets can
+// be generated from
+var ClientSecretCharsets = map[string]string{
+	"alphanumeric": alphanumericCharset,
+	"base64url":    base64URLCharset,
+	"hex":          "0123456789abcdef",
+	"printable":    printableCharset,
+}
+
+// defaultClientSecretPolicy generates the client secrets of controllers
+// without a ClientSecretPolicy, as many characters as 32 random bytes
+// encoded as base64url
+var defaultClientSecretPolicy = ClientSecretPolicy{Length: 43, Charset: base64URLCharset}
+
+// ClientSecretPolicy defines the client secrets generated by the controller
+type ClientSecretPolicy struct {
+	// Length is the number of characters of the secrets
+	Length int
+	// Charset holds the characters the secrets are drawn from, uniformly
+	Charset string
+}
+
+// NewClientSecretPolicy returns the policy generating secrets of length
+// characters from charset, the name of one of ClientSecretCharsets or the
+// characters themselves. The secrets must have at least minEntropy bits of
+// entropy.
+func NewClientSecretPolicy(length int, charset string, minEntropy float64) (*ClientSecretPolicy, error) {
+	if named, ok := ClientSecretCharsets[charset]; ok {
+		charset = named
+	}
+	p := &ClientSecretPolicy{Length: length, Charset: charset}
+	if err := p.Validate(minEntropy); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Entropy returns the entropy, in bits, of the secrets generated with p
+func (p *ClientSecretPolicy) Entropy() float64 {
+	return float64(p.Length) * math.Log2(float64(len(p.Charset)))
+}
+
+// Validate checks that p generates secrets with at least minEntropy bits of
+// entropy, from a charset without duplicate characters, which would make
+// some of them more likely than others
+func (p *ClientSecretPolicy) Validate(minEntropy float64) error {
+	if p.Length <= 0 {
+		return fmt.Errorf("the length of client secrets must be positive")
+	}
+	if len(p.Charset) < 2 {
+		return fmt.Errorf("client secrets must be generated from at least two characters")
+	}
+	seen := map[byte]bool{}
+	for i := 0; i < len(p.Charset); i++ {
+		c := p.Charset[i]
+		if c < 0x21 || c > 0x7e {
+			return fmt.Errorf("client secrets can only be generated from printable ASCII characters, found %q", c)
+		}
+		if seen[c] {
+			return fmt.Errorf("character %q is repeated in the charset of client secrets", c)
+		}
+		seen[c] = true
+	}
+	if entropy := p.Entropy(); entropy < minEntropy {
+		return fmt.Errorf("client secrets of %d characters from a charset of %d have %.0f bits of entropy, less than the required %.0f", p.Length, len(p.Charset), entropy, minEntropy)
+	}
+	return nil
+}
+
+// Generate returns a random client secret
+func (p *ClientSecretPolicy) Generate() ([]byte, error) {
+	secret := make([]byte, p.Length)
+	max := big.NewInt(int64(len(p.Charset)))
+	for i := range secret {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return nil, err
+		}
+		secret[i] = p.Charset[n.Int64()]
+	}
+	return secret, nil
+}
+
+// generateClientSecret returns a random client secret, generated with the
+// ClientSecretPolicy of the controller, else with the default one
+func (r *OAuth2ClientReconciler) generateClientSecret() ([]byte, error) {
+	if r.ClientSecretPolicy != nil {
+		return r.ClientSecretPolicy.Generate()
+	}
+	return defaultClientSecretPolicy.Generate()
+}
@@ -0,0 +1,122 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientSecretPolicyEntropy(t *testing.T) {
+
+	for name, tc := range map[string]struct {
+		policy  ClientSecretPolicy
+		entropy float64
+	}{
+		"hex":            {ClientSecretPolicy{Length: 32, Charset: ClientSecretCharsets["hex"]}, 128},
+		"base64url":      {ClientSecretPolicy{Length: 43, Charset: ClientSecretCharsets["base64url"]}, 258},
+		"two characters": {ClientSecretPolicy{Length: 10, Charset: "ab"}, 10},
+	} {
+		t.Run("case="+name, func(t *testing.T) {
+			assert.InDelta(t, tc.entropy, tc.policy.Entropy(), 0.001)
+		})
+	}
+}
+
+func TestClientSecretPolicyValidate(t *testing.T) {
+
+	for name, tc := range map[string]struct {
+		policy     ClientSecretPolicy
+		minEntropy float64
+		err        string
+	}{
+		"valid":                {ClientSecretPolicy{Length: 43, Charset: alphanumericCharset}, 128, ""},
+		"zero length":          {ClientSecretPolicy{Length: 0, Charset: alphanumericCharset}, 0, "must be positive"},
+		"single character":     {ClientSecretPolicy{Length: 43, Charset: "a"}, 0, "at least two characters"},
+		"repeated character":   {ClientSecretPolicy{Length: 43, Charset: "abca"}, 0, `character 'a' is repeated`},
+		"space":                {ClientSecretPolicy{Length: 43, Charset: "ab "}, 0, "printable ASCII"},
+		"non ASCII":            {ClientSecretPolicy{Length: 43, Charset: "abé"}, 0, "printable ASCII"},
+		"insufficient entropy": {ClientSecretPolicy{Length: 16, Charset: ClientSecretCharsets["hex"]}, 128, "64 bits of entropy, less than the required 128"},
+	} {
+		t.Run("case="+name, func(t *testing.T) {
+			err := tc.policy.Validate(tc.minEntropy)
+			if tc.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
+
+func TestNewClientSecretPolicy(t *testing.T) {
+
+	t.Run("case=named charset", func(t *testing.T) {
+		p, err := NewClientSecretPolicy(32, "hex", 128)
+		require.NoError(t, err)
+		assert.Equal(t, ClientSecretCharsets["hex"], p.Charset)
+	})
+
+	t.Run("case=characters", func(t *testing.T) {
+		p, err := NewClientSecretPolicy(64, "xyz", 100)
+		require.NoError(t, err)
+		assert.Equal(t, "xyz", p.Charset)
+	})
+
+	t.Run("case=insufficient entropy", func(t *testing.T) {
+		_, err := NewClientSecretPolicy(8, "alphanumeric", 128)
+		assert.Error(t, err)
+	})
+}
+
+func TestClientSecretPolicyGenerate(t *testing.T) {
+
+	for name, p := range map[string]ClientSecretPolicy{
+		"hex":       {Length: 32, Charset: ClientSecretCharsets["hex"]},
+		"printable": {Length: 64, Charset: ClientSecretCharsets["printable"]},
+		"default":   defaultClientSecretPolicy,
+	} {
+		t.Run("case="+name, func(t *testing.T) {
+			first, err := p.Generate()
+			require.NoError(t, err)
+			assert.Len(t, first, p.Length)
+			for _, c := range string(first) {
+				assert.True(t, strings.ContainsRune(p.Charset, c), "%q is not in the charset", c)
+			}
+
+			second, err := p.Generate()
+			require.NoError(t, err)
+			assert.NotEqual(t, first, second)
+		})
+	}
+
+	t.Run("case=reconciler without policy", func(t *testing.T) {
+		secret, err := (&OAuth2ClientReconciler{}).generateClientSecret()
+		require.NoError(t, err)
+		assert.Len(t, secret, defaultClientSecretPolicy.Length)
+	})
+
+	t.Run("case=reconciler with policy", func(t *testing.T) {
+		r := &OAuth2ClientReconciler{ClientSecretPolicy: &ClientSecretPolicy{Length: 20, Charset: "01"}}
+		secret, err := r.generateClientSecret()
+		require.NoError(t, err)
+		assert.Regexp(t, "^[01]{20}$", string(secret))
+	})
+}
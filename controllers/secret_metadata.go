@@ -0,0 +1,120 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"sort"
+	"strings"
+
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+// The annotations listing the labels and annotations set on a Secret from
+// the spec of its OAuth2Client, so that those removed from the spec are
+// removed from the Secret as well
+const (
+	PropagatedLabelsAnnotation      = "hydra-maester.ory.sh/propagated-labels"
+	PropagatedAnnotationsAnnotation = "hydra-maester.ory.sh/propagated-annotations"
+)
+
+// reservedMetadataPrefix is the prefix of the labels and annotations set by
+// the controller, which can't be set from the spec
+const reservedMetadataPrefix = "hydra-maester.ory.sh/"
+
+// propagateMetadata sets the SecretLabels and SecretAnnotations of c on its
+// Secret, and removes those which were removed from the spec. It reports
+// whether the Secret changed.
+func propagateMetadata(secret *apiv1.Secret, c *hydrav1alpha1.OAuth2Client) bool {
+	annotations := map[string]string{}
+	for key, value := range c.Spec.SecretAnnotations {
+		if _, replication := replicationAnnotations(c)[key]; !replication {
+			annotations[key] = value
+		}
+	}
+
+	labelsChanged := propagate(&secret.Labels, c.Spec.SecretLabels, secret.Annotations[PropagatedLabelsAnnotation])
+	annotationsChanged := propagate(&secret.Annotations, annotations, secret.Annotations[PropagatedAnnotationsAnnotation])
+	listChanged := setPropagated(secret, PropagatedLabelsAnnotation, c.Spec.SecretLabels)
+	listChanged = setPropagated(secret, PropagatedAnnotationsAnnotation, annotations) || listChanged
+	return labelsChanged || annotationsChanged || listChanged
+}
+
+// propagate sets wanted on current, and removes the keys listed in
+// propagated which are no longer wanted. Reserved keys are left alone.
+func propagate(current *map[string]string, wanted map[string]string, propagated string) bool {
+	changed := false
+	for _, key := range splitList(propagated) {
+		if _, ok := wanted[key]; ok || strings.HasPrefix(key, reservedMetadataPrefix) {
+			continue
+		}
+		if _, ok := (*current)[key]; ok {
+			delete(*current, key)
+			changed = true
+		}
+	}
+	for key, value := range wanted {
+		if strings.HasPrefix(key, reservedMetadataPrefix) {
+			continue
+		}
+		if existing, ok := (*current)[key]; ok && existing == value {
+			continue
+		}
+		if *current == nil {
+			*current = map[string]string{}
+		}
+		(*current)[key] = value
+		changed = true
+	}
+	return changed
+}
+
+// setPropagated records the keys of wanted in the given annotation of secret
+func setPropagated(secret *apiv1.Secret, annotation string, wanted map[string]string) bool {
+	keys := make([]string, 0, len(wanted))
+	for key := range wanted {
+		if !strings.HasPrefix(key, reservedMetadataPrefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	value := strings.Join(keys, ",")
+
+	current, set := secret.Annotations[annotation]
+	if value == "" {
+		delete(secret.Annotations, annotation)
+		return set
+	}
+	if set && current == value {
+		return false
+	}
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Annotations[annotation] = value
+	return true
+}
+
+// splitList splits a comma-separated list, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
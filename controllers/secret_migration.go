@@ -0,0 +1,206 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/go-logr/logr"
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	apiv1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// OAuth2ClientLabel is set on the Secrets generated for OAuth2Clients,
+	// with the name of the OAuth2Client as value
+	OAuth2ClientLabel = "hydra-maester.ory.sh/oauth2client"
+	// SecretChecksumAnnotation holds the checksum of the data of the Secrets
+	// generated for OAuth2Clients
+	SecretChecksumAnnotation = "hydra-maester.ory.sh/checksum"
+)
+
+// ownerReferenceTo returns the owner reference of the Secret generated for
+// c, which marks c as its controller so that it is garbage collected along
+// with c
+func ownerReferenceTo(c *hydrav1alpha1.OAuth2Client) metav1.OwnerReference {
+	controller := true
+	return metav1.OwnerReference{
+		APIVersion: hydrav1alpha1.GroupVersion.String(),
+		Kind:       "OAuth2Client",
+		Name:       c.Name,
+		UID:        c.UID,
+		Controller: &controller,
+	}
+}
+
+// controlSecret marks c as the controller of its Secret, which earlier
+// versions of the controller only set c as an owner of. It reports whether
+// the owner reference changed.
+func controlSecret(secret *apiv1.Secret, c *hydrav1alpha1.OAuth2Client) bool {
+	if metav1.GetControllerOf(secret) != nil {
+		return false
+	}
+	for i := range secret.OwnerReferences {
+		if secret.OwnerReferences[i].UID == c.UID {
+			secret.OwnerReferences[i] = ownerReferenceTo(c)
+			return true
+		}
+	}
+	return false
+}
+
+// labelSecret sets the label, checksum, encodings and replication
+// annotations of the Secret generated for c
+func labelSecret(secret *apiv1.Secret, c *hydrav1alpha1.OAuth2Client) {
+	if secret.Labels == nil {
+		secret.Labels = map[string]string{}
+	}
+	secret.Labels[OAuth2ClientLabel] = c.Name
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Annotations[SecretChecksumAnnotation] = secretChecksum(secret.Data)
+	annotateEncodings(secret, c)
+	annotateReplication(secret, c)
+	propagateMetadata(secret, c)
+}
+
+// trackSecretChecksum records the checksum of the data of the Secret
+// generated for c in its status, so that it is visible on c as well. It
+// reports whether the status changed.
+func trackSecretChecksum(c *hydrav1alpha1.OAuth2Client, secret *apiv1.Secret) bool {
+	checksum := ""
+	if secret.Name != "" && isOwnedBy(secret, c) {
+		checksum = secretChecksum(secret.Data)
+	}
+	if c.Status.SecretChecksum == checksum {
+		return false
+	}
+	c.Status.SecretChecksum = checksum
+	return true
+}
+
+func isLabelled(secret *apiv1.Secret, c *hydrav1alpha1.OAuth2Client) bool {
+	return secret.Labels[OAuth2ClientLabel] == c.Name && secret.Annotations[SecretChecksumAnnotation] == secretChecksum(secret.Data)
+}
+
+// secretChecksum returns the SHA-256 checksum of data, independent of the
+// order of its keys
+func secretChecksum(data map[string][]byte) string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, k := range keys {
+		fmt.Fprintf(h, "%s=%d:", k, len(data[k]))
+		h.Write(data[k])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// SecretMigrator adopts, once on startup, the Secrets of OAuth2Clients which
+// have been generated by previous versions of the controller without owner
+// references, labels or checksums, so they are covered by the lifecycle of
+// the Secrets generated now. Secrets holding a manually set client secret
+// are left untouched, and Secrets owned by other objects are reported with
+// a warning.
+type SecretMigrator struct {
+	// Reader reads OAuth2Clients and Secrets. It should not be backed by a
+	// cache, as the migration can run before the caches are synced.
+	Reader   client.Reader
+	Client   client.Client
+	Recorder record.EventRecorder
+	Log      logr.Logger
+}
+
+// Start implements manager.Runnable
+func (m *SecretMigrator) Start(stop <-chan struct{}) error {
+	var clients hydrav1alpha1.OAuth2ClientList
+	if err := m.Reader.List(context.Background(), &clients); err != nil {
+		m.Log.Error(err, "unable to list OAuth2Clients, skipping the migration of their Secrets")
+		return nil
+	}
+
+	for i := range clients.Items {
+		c := &clients.Items[i]
+		if !c.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if err := m.migrate(context.Background(), c); err != nil {
+			m.Log.Error(err, fmt.Sprintf("unable to migrate the secret of client %s/%s", c.Name, c.Namespace))
+		}
+	}
+	return nil
+}
+
+func (m *SecretMigrator) migrate(ctx context.Context, c *hydrav1alpha1.OAuth2Client) error {
+	var secret apiv1.Secret
+	if err := m.Reader.Get(ctx, types.NamespacedName{Name: c.Spec.SecretName, Namespace: c.Namespace}, &secret); err != nil {
+		if apierrs.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if isManualSecret(&secret) {
+		return nil
+	}
+
+	adopted := false
+	if !isOwnedBy(&secret, c) {
+		if len(secret.OwnerReferences) > 0 {
+			m.recordEvent(c, apiv1.EventTypeWarning, EventReasonSecretNotMigrated,
+				fmt.Sprintf("secret %s is owned by another object, it is not adopted", secret.Name))
+			return nil
+		}
+		secret.OwnerReferences = append(secret.OwnerReferences, ownerReferenceTo(c))
+		adopted = true
+	} else if isLabelled(&secret, c) {
+		return nil
+	}
+
+	labelSecret(&secret, c)
+	if err := m.Client.Update(ctx, &secret); err != nil {
+		return err
+	}
+
+	if adopted {
+		m.recordEvent(c, apiv1.EventTypeNormal, EventReasonSecretMigrated,
+			fmt.Sprintf("adopted secret %s, it is deleted with the client from now on", secret.Name))
+	} else {
+		m.recordEvent(c, apiv1.EventTypeNormal, EventReasonSecretMigrated,
+			fmt.Sprintf("added the label and checksum to secret %s", secret.Name))
+	}
+	return nil
+}
+
+func (m *SecretMigrator) recordEvent(c *hydrav1alpha1.OAuth2Client, eventType, reason, message string) {
+	m.Log.Info(message, "oauth2client", types.NamespacedName{Name: c.Name, Namespace: c.Namespace}.String())
+	if m.Recorder != nil {
+		m.Recorder.Event(c, eventType, reason, message)
+	}
+}
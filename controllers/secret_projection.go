@@ -0,0 +1,315 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	"github.com/ory/hydra-maester/pkg/hydraclient"
+	"github.com/pkg/errors"
+	apiv1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	authorizationPath = "/oauth2/auth"
+	tokenPath         = "/oauth2/token"
+
+	// TokenEndpointKey and IssuerURLKey hold the token endpoint and the
+	// issuer in the Secrets of clients without a SecretProjection, if the
+	// controller knows ORY Hydra's public URL
+	TokenEndpointKey = "token_endpoint"
+	IssuerURLKey     = "issuer_url"
+)
+
+// defaultSecretProjection is the Secret layout of clients without a SecretProjection
+var defaultSecretProjection = defaultSecretProjectionWith(ClientIDKey, ClientSecretKey)
+
+// defaultSecretProjectionWith returns the default Secret layout with the
+// client ID and secret under the given keys. The token endpoint and the
+// issuer are left out if the controller doesn't know ORY Hydra's public URL.
+func defaultSecretProjectionWith(clientIDKey, clientSecretKey string) hydrav1alpha1.SecretProjection {
+	return hydrav1alpha1.SecretProjection{
+		Format: hydrav1alpha1.SecretProjectionFormatKeys,
+		Items: []hydrav1alpha1.SecretProjectionItem{
+			{Property: hydrav1alpha1.SecretPropertyClientID, Key: clientIDKey},
+			{Property: hydrav1alpha1.SecretPropertyClientSecret, Key: clientSecretKey},
+			{Property: hydrav1alpha1.SecretPropertyTokenEndpoint, Key: TokenEndpointKey},
+			{Property: hydrav1alpha1.SecretPropertyIssuer, Key: IssuerURLKey},
+		},
+	}
+}
+
+// secretProjectionOf returns the Secret layout of c, the default one with
+// the keys set by the controller's flags if c has no SecretProjection, or
+// with the keys of `kubernetes.io/basic-auth` if its Secret has that type
+func (r *OAuth2ClientReconciler) secretProjectionOf(c *hydrav1alpha1.OAuth2Client) hydrav1alpha1.SecretProjection {
+	if c.Spec.SecretProjection != nil {
+		return *c.Spec.SecretProjection
+	}
+	if c.Spec.SecretType == apiv1.SecretTypeBasicAuth {
+		return defaultSecretProjectionWith(apiv1.BasicAuthUsernameKey, apiv1.BasicAuthPasswordKey)
+	}
+	if r.SecretClientIDKey == "" && r.SecretClientSecretKey == "" {
+		return defaultSecretProjection
+	}
+	return defaultSecretProjectionWith(keyOr(r.SecretClientIDKey, ClientIDKey), keyOr(r.SecretClientSecretKey, ClientSecretKey))
+}
+
+func keyOr(key, fallback string) string {
+	if key != "" {
+		return key
+	}
+	return fallback
+}
+
+// secretTypeOf returns the type of the Secret of c
+func secretTypeOf(c *hydrav1alpha1.OAuth2Client) apiv1.SecretType {
+	if c.Spec.SecretType != "" {
+		return c.Spec.SecretType
+	}
+	return apiv1.SecretTypeOpaque
+}
+
+func jsonKeyOf(p hydrav1alpha1.SecretProjection) string {
+	if p.JSONKey != "" {
+		return p.JSONKey
+	}
+	return hydrav1alpha1.DefaultSecretProjectionJSONKey
+}
+
+// secretData returns the data of the Secret holding the given credentials of c
+func (r *OAuth2ClientReconciler) secretData(c *hydrav1alpha1.OAuth2Client, credentials *hydraclient.Oauth2ClientCredentials) (map[string][]byte, error) {
+	values := map[hydrav1alpha1.SecretProperty]string{
+		hydrav1alpha1.SecretPropertyClientID: string(credentials.ID),
+		hydrav1alpha1.SecretPropertyScope:    c.GetScope(),
+		hydrav1alpha1.SecretPropertyAudience: strings.Join(c.Spec.Audience, " "),
+	}
+	if credentials.Password != nil {
+		values[hydrav1alpha1.SecretPropertyClientSecret] = string(credentials.Password)
+	}
+	if r.HydraPublicURL != "" {
+		u, err := url.Parse(r.HydraPublicURL)
+		if err != nil {
+			return nil, fmt.Errorf("hydra public URL %s is invalid: %w", r.HydraPublicURL, err)
+		}
+		values[hydrav1alpha1.SecretPropertyIssuer] = u.String()
+		values[hydrav1alpha1.SecretPropertyAuthorizationEndpoint] = u.ResolveReference(&url.URL{Path: authorizationPath}).String()
+		values[hydrav1alpha1.SecretPropertyTokenEndpoint] = u.ResolveReference(&url.URL{Path: tokenPath}).String()
+	}
+
+	p := r.secretProjectionOf(c)
+	projected := map[string]string{}
+	for _, item := range p.Items {
+		if value := values[item.Property]; value != "" {
+			projected[item.GetKey()] = encodeSecretValue(value, item.Encoding)
+		}
+	}
+
+	data := make(map[string][]byte, len(projected)+len(p.Templates))
+	if p.Format == hydrav1alpha1.SecretProjectionFormatJSON {
+		raw, err := json.Marshal(projected)
+		if err != nil {
+			return nil, err
+		}
+		data[jsonKeyOf(p)] = raw
+	} else {
+		for key, value := range projected {
+			data[key] = []byte(value)
+		}
+	}
+
+	if len(p.Templates) == 0 {
+		return data, nil
+	}
+	properties := make(map[string]string, len(values))
+	for property, value := range values {
+		properties[string(property)] = value
+	}
+	for _, t := range p.Templates {
+		tmpl, err := t.Parse()
+		if err != nil {
+			return nil, fmt.Errorf("the template of key %s is invalid: %w", t.Key, err)
+		}
+		var rendered bytes.Buffer
+		if err := tmpl.Execute(&rendered, properties); err != nil {
+			return nil, fmt.Errorf("unable to render the template of key %s: %w", t.Key, err)
+		}
+		data[t.Key] = rendered.Bytes()
+	}
+	return data, nil
+}
+
+// credentialsOf returns the credentials of a client registered in ORY Hydra
+func credentialsOf(created *hydraclient.OAuth2ClientJSON) *hydraclient.Oauth2ClientCredentials {
+	credentials := &hydraclient.Oauth2ClientCredentials{ID: []byte(*created.ClientID)}
+	if created.Secret != nil {
+		credentials.Password = []byte(*created.Secret)
+	}
+	return credentials
+}
+
+// parseSecret reads the credentials of c from its Secret. Secrets written
+// before the default keys or the Secret type were changed are read with the
+// former keys, and rewritten by ensureSecretProjection.
+func (r *OAuth2ClientReconciler) parseSecret(secret apiv1.Secret, c *hydrav1alpha1.OAuth2Client) (*hydraclient.Oauth2ClientCredentials, error) {
+	credentials, err := parseSecretWith(secret, c, r.secretProjectionOf(c))
+	if err != nil && c.Spec.SecretProjection == nil {
+		fallbacks := []hydrav1alpha1.SecretProjection{defaultSecretProjection}
+		if secret.Type == apiv1.SecretTypeBasicAuth {
+			fallbacks = append(fallbacks, defaultSecretProjectionWith(apiv1.BasicAuthUsernameKey, apiv1.BasicAuthPasswordKey))
+		}
+		for _, p := range fallbacks {
+			if fallback, fallbackErr := parseSecretWith(secret, c, p); fallbackErr == nil {
+				return fallback, nil
+			}
+		}
+	}
+	return credentials, err
+}
+
+// parseSecretWith reads the credentials of c from its Secret laid out as p
+func parseSecretWith(secret apiv1.Secret, c *hydrav1alpha1.OAuth2Client, p hydrav1alpha1.SecretProjection) (*hydraclient.Oauth2ClientCredentials, error) {
+
+	values := secret.Data
+	if p.Format == hydrav1alpha1.SecretProjectionFormatJSON {
+		raw, found := secret.Data[jsonKeyOf(p)]
+		if !found {
+			return nil, errors.Errorf(`"%s property missing"`, jsonKeyOf(p))
+		}
+		var object map[string]string
+		if err := json.Unmarshal(raw, &object); err != nil {
+			return nil, errors.Wrapf(err, `"%s property is not a JSON object of strings"`, jsonKeyOf(p))
+		}
+		values = make(map[string][]byte, len(object))
+		for key, value := range object {
+			values[key] = []byte(value)
+		}
+	}
+
+	var idKey, secretKey string
+	for _, item := range p.Items {
+		switch item.Property {
+		case hydrav1alpha1.SecretPropertyClientID:
+			idKey = item.GetKey()
+		case hydrav1alpha1.SecretPropertyClientSecret:
+			secretKey = item.GetKey()
+		}
+	}
+
+	id, found := values[idKey]
+	if !found {
+		return nil, errors.Errorf(`"%s property missing"`, idKey)
+	}
+
+	psw, found := values[secretKey]
+	if !found && c.GetTokenEndpointAuthMethod() != "none" {
+		return nil, errors.Errorf(`"%s property missing"`, secretKey)
+	}
+
+	encodings := secretEncodings(secret, c, p)
+	id, err := decodeSecretValue(id, encodings[idKey])
+	if err != nil {
+		return nil, errors.Wrapf(err, `"%s property is not %s encoded"`, idKey, encodings[idKey])
+	}
+	if psw != nil {
+		if psw, err = decodeSecretValue(psw, encodings[secretKey]); err != nil {
+			return nil, errors.Wrapf(err, `"%s property is not %s encoded"`, secretKey, encodings[secretKey])
+		}
+	}
+
+	return &hydraclient.Oauth2ClientCredentials{
+		ID:       id,
+		Password: psw,
+	}, nil
+}
+
+// ensureSecretProjection keeps the Secret generated for c in line with its
+// SecretProjection and SecretType. Secrets provided by users or holding a
+// manually set client secret are left untouched.
+func (r *OAuth2ClientReconciler) ensureSecretProjection(ctx context.Context, c *hydrav1alpha1.OAuth2Client, secret *apiv1.Secret, credentials *hydraclient.Oauth2ClientCredentials) error {
+	if isManualSecret(secret) || !isOwnedBy(secret, c) {
+		return nil
+	}
+
+	data, err := r.secretData(c, credentials)
+	if err != nil {
+		return err
+	}
+	r.keepPreviousSecret(c, secret, data)
+	if secretTypeOf(c) != secret.Type && (secret.Type != "" || secretTypeOf(c) != apiv1.SecretTypeOpaque) {
+		return r.replaceSecret(ctx, c, secret, data)
+	}
+	replicationChanged := annotateReplication(secret, c)
+	controllerChanged := controlSecret(secret, c)
+	metadataChanged := propagateMetadata(secret, c)
+	encodingsChanged := annotateEncodings(secret, c)
+	if reflect.DeepEqual(secret.Data, data) && !replicationChanged && !controllerChanged && !metadataChanged && !encodingsChanged {
+		return nil
+	}
+
+	secret.Data = data
+	labelSecret(secret, c)
+	return r.Update(ctx, secret)
+}
+
+// replaceSecret replaces secret with a Secret of the type of c holding data,
+// as the type of a Secret can't be updated. The client isn't registered
+// again, as the credentials don't change.
+func (r *OAuth2ClientReconciler) replaceSecret(ctx context.Context, c *hydrav1alpha1.OAuth2Client, secret *apiv1.Secret, data map[string][]byte) error {
+	replacement := apiv1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            secret.Name,
+			Namespace:       secret.Namespace,
+			Labels:          secret.Labels,
+			Annotations:     secret.Annotations,
+			OwnerReferences: secret.OwnerReferences,
+		},
+		Type: secretTypeOf(c),
+		Data: data,
+	}
+	annotateReplication(&replacement, c)
+	controlSecret(&replacement, c)
+	propagateMetadata(&replacement, c)
+	labelSecret(&replacement, c)
+
+	if err := r.Delete(ctx, secret); err != nil && !apierrs.IsNotFound(err) {
+		return err
+	}
+	if err := r.Create(ctx, &replacement); err != nil {
+		return err
+	}
+	r.recordEvent(c, apiv1.EventTypeNormal, EventReasonSecretReplaced, fmt.Sprintf("replaced secret %s to change its type from %s to %s", secret.Name, secret.Type, replacement.Type))
+	*secret = replacement
+	return nil
+}
+
+func isOwnedBy(secret *apiv1.Secret, c *hydrav1alpha1.OAuth2Client) bool {
+	for _, ref := range secret.OwnerReferences {
+		if ref.UID == c.UID {
+			return true
+		}
+	}
+	return false
+}
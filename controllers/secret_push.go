@@ -0,0 +1,164 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	"github.com/ory/hydra-maester/pkg/hydraclient"
+	apiv1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// pushSecretGVK is the kind of the PushSecrets of the External Secrets
+// Operator, which are handled as unstructured objects so that the operator
+// is not a dependency of the controller
+var pushSecretGVK = schema.GroupVersionKind{Group: "external-secrets.io", Version: "v1alpha1", Kind: "PushSecret"}
+
+// +kubebuilder:rbac:groups=external-secrets.io,resources=pushsecrets,verbs=get;list;watch;create;update;delete
+
+// ensureSecretPush keeps the PushSecret pushing the Secret of c to an
+// external secret store in line with its SecretPush, and deletes it once
+// SecretPush is removed. Secrets provided by users are never pushed. It
+// reports whether the SecretPushed condition changed.
+func (r *OAuth2ClientReconciler) ensureSecretPush(ctx context.Context, c *hydrav1alpha1.OAuth2Client, secret *apiv1.Secret, credentials *hydraclient.Oauth2ClientCredentials) (bool, error) {
+	if c.Spec.SecretPush == nil || secret.Name == "" || !isOwnedBy(secret, c) {
+		// the PushSecret is only looked up if one was created, so that the
+		// External Secrets Operator is only required when it is used
+		if !c.Status.IsConditionTrue(hydrav1alpha1.OAuth2ClientConditionSecretPushed) {
+			return false, nil
+		}
+		pushSecret := &unstructured.Unstructured{}
+		pushSecret.SetGroupVersionKind(pushSecretGVK)
+		pushSecret.SetName(c.Spec.SecretName)
+		pushSecret.SetNamespace(c.Namespace)
+		if err := r.Delete(ctx, pushSecret); err != nil && !apierrs.IsNotFound(err) {
+			return false, err
+		}
+		return c.Status.SetCondition(hydrav1alpha1.OAuth2ClientConditionSecretPushed, apiv1.ConditionFalse, "NoSecretPush", ""), nil
+	}
+
+	data, err := r.secretData(c, credentials)
+	if err != nil {
+		return false, err
+	}
+	desired := desiredPushSecret(c, data)
+
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(pushSecretGVK)
+	err = r.Get(ctx, types.NamespacedName{Name: desired.GetName(), Namespace: desired.GetNamespace()}, current)
+	switch {
+	case apierrs.IsNotFound(err):
+		if err := r.Create(ctx, desired); err != nil {
+			return false, err
+		}
+	case err != nil:
+		return false, err
+	case !metav1.IsControlledBy(current, c):
+		return false, fmt.Errorf("PushSecret %s/%s already exists and is not managed by the controller", current.GetNamespace(), current.GetName())
+	case pushSecretDiffers(current, desired):
+		for _, field := range []string{"secretStoreRefs", "selector", "data"} {
+			if err := unstructured.SetNestedField(current.Object, desired.Object["spec"].(map[string]interface{})[field], "spec", field); err != nil {
+				return false, err
+			}
+		}
+		if err := r.Update(ctx, current); err != nil {
+			return false, err
+		}
+	}
+
+	ref := c.Spec.SecretPush.SecretStoreRef
+	message := fmt.Sprintf("secret is pushed to %s %s", storeKindOf(ref), ref.Name)
+	return c.Status.SetCondition(hydrav1alpha1.OAuth2ClientConditionSecretPushed, apiv1.ConditionTrue, "PushSecretCreated", message), nil
+}
+
+// desiredPushSecret returns the PushSecret pushing each key of data, the
+// content of the Secret of c, as a property of the remote key of c
+func desiredPushSecret(c *hydrav1alpha1.OAuth2Client, data map[string][]byte) *unstructured.Unstructured {
+	push := c.Spec.SecretPush
+	remoteKey := push.RemoteKey
+	if remoteKey == "" {
+		remoteKey = fmt.Sprintf("%s/%s", c.Namespace, c.Name)
+	}
+
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	items := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		items = append(items, map[string]interface{}{
+			"match": map[string]interface{}{
+				"secretKey": key,
+				"remoteRef": map[string]interface{}{
+					"remoteKey": remoteKey,
+					"property":  key,
+				},
+			},
+		})
+	}
+
+	pushSecret := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"secretStoreRefs": []interface{}{
+				map[string]interface{}{
+					"name": push.SecretStoreRef.Name,
+					"kind": storeKindOf(push.SecretStoreRef),
+				},
+			},
+			"selector": map[string]interface{}{
+				"secret": map[string]interface{}{"name": c.Spec.SecretName},
+			},
+			"data": items,
+		},
+	}}
+	pushSecret.SetGroupVersionKind(pushSecretGVK)
+	pushSecret.SetName(c.Spec.SecretName)
+	pushSecret.SetNamespace(c.Namespace)
+	pushSecret.SetLabels(map[string]string{OAuth2ClientLabel: c.Name})
+	pushSecret.SetOwnerReferences([]metav1.OwnerReference{ownerReferenceTo(c)})
+	return pushSecret
+}
+
+// pushSecretDiffers reports whether the fields of current set by the
+// controller differ from desired. Fields defaulted by the External Secrets
+// Operator are ignored.
+func pushSecretDiffers(current, desired *unstructured.Unstructured) bool {
+	for _, field := range []string{"secretStoreRefs", "selector", "data"} {
+		currentValue, _, _ := unstructured.NestedFieldNoCopy(current.Object, "spec", field)
+		desiredValue, _, _ := unstructured.NestedFieldNoCopy(desired.Object, "spec", field)
+		if !reflect.DeepEqual(currentValue, desiredValue) {
+			return true
+		}
+	}
+	return false
+}
+
+func storeKindOf(ref hydrav1alpha1.SecretStoreRef) string {
+	if ref.Kind == "" {
+		return "SecretStore"
+	}
+	return ref.Kind
+}
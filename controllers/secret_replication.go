@@ -0,0 +1,81 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"strings"
+
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+// The annotations understood by kubernetes-replicator and Reflector, which
+// copy Secrets to other namespaces. Both accept regular expressions as
+// namespaces.
+const (
+	replicatorReplicateToAnnotation      = "replicator.v1.mittwald.de/replicate-to"
+	reflectorAllowedAnnotation           = "reflector.v1.k8s.emberstack.com/reflection-allowed"
+	reflectorAllowedNamespacesAnnotation = "reflector.v1.k8s.emberstack.com/reflection-allowed-namespaces"
+	reflectorAutoEnabledAnnotation       = "reflector.v1.k8s.emberstack.com/reflection-auto-enabled"
+	reflectorAutoNamespacesAnnotation    = "reflector.v1.k8s.emberstack.com/reflection-auto-namespaces"
+)
+
+// replicationAnnotations returns the annotations requesting the replication
+// of the Secret of c, nothing if it is not to be replicated
+func replicationAnnotations(c *hydrav1alpha1.OAuth2Client) map[string]string {
+	if len(c.Spec.SecretReplicationNamespaces) == 0 {
+		return nil
+	}
+	namespaces := strings.Join(c.Spec.SecretReplicationNamespaces, ",")
+	return map[string]string{
+		replicatorReplicateToAnnotation:      namespaces,
+		reflectorAllowedAnnotation:           "true",
+		reflectorAllowedNamespacesAnnotation: namespaces,
+		reflectorAutoEnabledAnnotation:       "true",
+		reflectorAutoNamespacesAnnotation:    namespaces,
+	}
+}
+
+// annotateReplication sets the replication annotations of the Secret
+// generated for c, or removes them once c is no longer replicated. It reports
+// whether the annotations changed.
+func annotateReplication(secret *apiv1.Secret, c *hydrav1alpha1.OAuth2Client) bool {
+	wanted := replicationAnnotations(c)
+	changed := false
+	for _, key := range []string{
+		replicatorReplicateToAnnotation,
+		reflectorAllowedAnnotation,
+		reflectorAllowedNamespacesAnnotation,
+		reflectorAutoEnabledAnnotation,
+		reflectorAutoNamespacesAnnotation,
+	} {
+		value, ok := wanted[key]
+		current, set := secret.Annotations[key]
+		if ok == set && value == current {
+			continue
+		}
+		changed = true
+		if !ok {
+			delete(secret.Annotations, key)
+			continue
+		}
+		if secret.Annotations == nil {
+			secret.Annotations = map[string]string{}
+		}
+		secret.Annotations[key] = value
+	}
+	return changed
+}
@@ -0,0 +1,90 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	"github.com/ory/hydra-maester/pkg/hydraclient"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+// restoreSecret restores the Secret of c, which has been deleted while the
+// client is registered in ORY Hydra, instead of registering a new client.
+// The credentials are read back from the credential store if it holds them,
+// otherwise the client secret is rotated, as ORY Hydra doesn't disclose it.
+// It reports whether the client was restored.
+func (r *OAuth2ClientReconciler) restoreSecret(ctx context.Context, c *hydrav1alpha1.OAuth2Client) (bool, error) {
+	// clients with existing credentials are registered with them again
+	if c.Status.ClientID == "" || c.Spec.ExistingSecretRef != nil || c.IsPublic() {
+		return false, nil
+	}
+
+	hydra, err := r.getHydraClientForClient(ctx, *c)
+	if err != nil {
+		return false, err
+	}
+	if err := checkBudget(ctx, "fetching the client from ORY Hydra"); err != nil {
+		return false, err
+	}
+	fetched, found, err := hydra.GetOAuth2Client(c.Status.ClientID)
+	if err != nil {
+		return false, err
+	}
+	if !found || fetched.Owner != fmt.Sprintf("%s/%s", c.Name, c.Namespace) {
+		return false, nil
+	}
+
+	stored, err := r.readStoredSecret(ctx, c)
+	if err != nil {
+		return false, err
+	}
+	if stored != nil {
+		if credentials, err := r.parseSecret(*stored, c); err == nil && string(credentials.ID) == c.Status.ClientID {
+			r.recordEvent(c, apiv1.EventTypeNormal, EventReasonSecretRestored, fmt.Sprintf("secret %s was deleted, restored it from the credential store", c.Spec.SecretName))
+			return true, r.writeCredentials(ctx, c, credentials)
+		}
+	}
+
+	password, err := r.generateClientSecret()
+	if err != nil {
+		return false, err
+	}
+	rotated := &hydraclient.Oauth2ClientCredentials{ID: []byte(c.Status.ClientID), Password: password}
+	c.Status.ClientSecretExpiresAt = nil
+	startSecretLifetime(c)
+	restartSecretAge(c)
+	desired, err := desiredOAuth2Client(c, rotated, fetched)
+	if err != nil {
+		return false, err
+	}
+	if err := checkBudget(ctx, "rotating the client secret in ORY Hydra"); err != nil {
+		return false, err
+	}
+	updated, err := hydra.PutOAuth2Client(desired)
+	if err != nil {
+		if updateErr := r.updateReconciliationStatusError(ctx, c, hydrav1alpha1.StatusUpdateFailed, err); updateErr != nil {
+			return true, updateErr
+		}
+		return true, retryIfTransient(err)
+	}
+	recordWrite(c, updated)
+	secretRotations.Inc()
+	r.recordEvent(c, apiv1.EventTypeNormal, EventReasonSecretRestored, fmt.Sprintf("secret %s was deleted, rotated the client secret of client %s to restore it", c.Spec.SecretName, c.Status.ClientID))
+	return true, r.writeCredentials(ctx, c, rotated)
+}
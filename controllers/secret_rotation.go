@@ -0,0 +1,286 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	"github.com/ory/hydra-maester/pkg/hydraclient"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RotateAnnotation requests the rotation of the client secret of an
+// OAuth2Client. The secret is rotated once for each new value, e.g. the
+// current time.
+const RotateAnnotation = "hydra-maester.ory.sh/rotate"
+
+// previousSecretKeySuffix is appended to the key of the client secret to
+// form the key the previous client secret is kept under after a rotation
+const previousSecretKeySuffix = "_previous"
+
+// HydraTokensClient revokes the tokens issued to clients by ORY Hydra
+type HydraTokensClient interface {
+	DeleteOAuth2Tokens(clientID string) error
+}
+
+// pendingRotation returns the value of the rotate annotation of c if the
+// client secret has not been rotated for it yet
+func pendingRotation(c *hydrav1alpha1.OAuth2Client) string {
+	request := c.Annotations[RotateAnnotation]
+	if request == "" || request == c.Status.RotationRequest {
+		return ""
+	}
+	return request
+}
+
+// restartSecretAge records that a new client secret has just been issued to
+// c, if it has a RotationPolicy
+func restartSecretAge(c *hydrav1alpha1.OAuth2Client) {
+	c.Status.ClientSecretIssuedAt = nil
+	trackSecretAge(c)
+}
+
+// trackSecretAge records when the client secret of c was issued if it has
+// a RotationPolicy, and forgets it otherwise. A secret issued before the
+// policy was set is considered issued now. It reports whether the status
+// changed.
+func trackSecretAge(c *hydrav1alpha1.OAuth2Client) bool {
+	if c.Spec.RotationPolicy == nil || c.IsPublic() {
+		changed := c.Status.ClientSecretIssuedAt != nil
+		c.Status.ClientSecretIssuedAt = nil
+		return changed
+	}
+	if c.Status.ClientSecretIssuedAt != nil {
+		return false
+	}
+	now := metav1.NewTime(time.Now().Truncate(time.Second))
+	c.Status.ClientSecretIssuedAt = &now
+	return true
+}
+
+// scheduledRotation reports whether the client secret of c is older than
+// its RotationPolicy allows, and otherwise how long it takes until it is
+func scheduledRotation(c *hydrav1alpha1.OAuth2Client) (bool, time.Duration) {
+	if c.Spec.RotationPolicy == nil || c.Status.ClientSecretIssuedAt == nil {
+		return false, 0
+	}
+	remaining := time.Until(c.Status.ClientSecretIssuedAt.Add(c.Spec.RotationPolicy.MaxAge.Duration))
+	return remaining <= 0, remaining
+}
+
+// rotationSkipReason explains why the client secret of c, held by secret,
+// can't be rotated, nothing if it can. A secret without a name is held by
+// the credential store.
+func (r *OAuth2ClientReconciler) rotationSkipReason(c *hydrav1alpha1.OAuth2Client, secret *apiv1.Secret) string {
+	switch {
+	case c.IsPublic():
+		return "public clients have no client secret"
+	case secret.Name == "" && r.CredentialStoreOnly:
+		return ""
+	case secret.Name == "" || !isOwnedBy(secret, c):
+		return fmt.Sprintf("secret %s/%s is not generated by the controller", c.Namespace, c.Spec.SecretName)
+	case isManualSecret(secret):
+		return "the client secret is set manually"
+	}
+	return ""
+}
+
+// rotateClientSecret replaces the client secret of c, registered in ORY
+// Hydra as fetched, with a new one, first in ORY Hydra and then in the
+// credential store and secret.
+// The rotation is only recorded once both are written, so that a failure
+// in between is retried with yet another secret. request is the value of
+// the rotate annotation the rotation is carried out for, if any.
+func (r *OAuth2ClientReconciler) rotateClientSecret(ctx context.Context, c *hydrav1alpha1.OAuth2Client, secret *apiv1.Secret, credentials *hydraclient.Oauth2ClientCredentials, fetched *hydraclient.OAuth2ClientJSON, request string) error {
+	if skipped := r.rotationSkipReason(c, secret); skipped != "" {
+		r.recordEvent(c, apiv1.EventTypeWarning, EventReasonSecretRotationSkipped, "client secret not rotated, "+skipped)
+		c.Status.RotationRequest = request
+		return r.updateClientStatus(ctx, c)
+	}
+
+	hydra, err := r.getHydraClientForClient(ctx, *c)
+	if err != nil {
+		return err
+	}
+	password, err := r.generateClientSecret()
+	if err != nil {
+		return err
+	}
+	rotated := &hydraclient.Oauth2ClientCredentials{ID: credentials.ID, Password: password}
+
+	// the lifetime of the new secret is sent to ORY Hydra along with it
+	c.Status.ClientSecretExpiresAt = nil
+	startSecretLifetime(c)
+	restartSecretAge(c)
+	desired, err := desiredOAuth2Client(c, rotated, fetched)
+	if err != nil {
+		return err
+	}
+	if err := checkBudget(ctx, "rotating the client secret in ORY Hydra"); err != nil {
+		return err
+	}
+	updated, err := hydra.PutOAuth2Client(desired)
+	if err != nil {
+		if updateErr := r.updateReconciliationStatusError(ctx, c, hydrav1alpha1.StatusUpdateFailed, err); updateErr != nil {
+			return updateErr
+		}
+		return retryIfTransient(err)
+	}
+	recordWrite(c, updated)
+
+	data, err := r.secretData(c, rotated)
+	if err != nil {
+		return err
+	}
+	if err := r.storeCredentials(ctx, c, data); err != nil {
+		return err
+	}
+	if secret.Name != "" {
+		r.overlapPreviousSecret(c, data, credentials)
+		if err := r.updateRotatedSecret(ctx, c, secret, data); err != nil {
+			return err
+		}
+		if _, err := r.syncSecretTargets(ctx, c, secret); err != nil {
+			return err
+		}
+	}
+
+	secretRotations.Inc()
+	r.recordEvent(c, apiv1.EventTypeNormal, EventReasonSecretRotated, fmt.Sprintf("rotated the client secret of client %s", credentials.ID))
+	if c.Spec.RevokeTokensOnRotation {
+		r.revokeTokens(ctx, c, hydra, string(credentials.ID))
+	}
+	if request != "" {
+		c.Status.RotationRequest = request
+	}
+	trackSecretChecksum(c, secret)
+	return r.ensureEmptyStatusError(ctx, c)
+}
+
+// revokeTokens deletes the access tokens issued to the client of c with the
+// given ID from ORY Hydra, after its client secret has been rotated. A
+// failure is reported with an event, as the rotation itself has succeeded.
+func (r *OAuth2ClientReconciler) revokeTokens(ctx context.Context, c *hydrav1alpha1.OAuth2Client, hydra ClientRegistrar, clientID string) {
+	tokens, ok := hydra.(HydraTokensClient)
+	if !ok {
+		r.recordEvent(c, apiv1.EventTypeWarning, EventReasonTokenRevocationFailed, "tokens not revoked, the client registrar can't revoke tokens")
+		return
+	}
+	if err := checkBudget(ctx, "revoking the tokens of the client"); err != nil {
+		r.recordEvent(c, apiv1.EventTypeWarning, EventReasonTokenRevocationFailed, fmt.Sprintf("tokens of client %s not revoked: %s", clientID, err))
+		return
+	}
+	if err := tokens.DeleteOAuth2Tokens(clientID); err != nil {
+		r.recordEvent(c, apiv1.EventTypeWarning, EventReasonTokenRevocationFailed, fmt.Sprintf("tokens of client %s not revoked: %s", clientID, err))
+		return
+	}
+	r.recordEvent(c, apiv1.EventTypeNormal, EventReasonTokensRevoked, fmt.Sprintf("revoked the access tokens of client %s", clientID))
+}
+
+// updateRotatedSecret writes data, holding a rotated client secret of c, to
+// secret along with the expiry of the client secret
+func (r *OAuth2ClientReconciler) updateRotatedSecret(ctx context.Context, c *hydrav1alpha1.OAuth2Client, secret *apiv1.Secret, data map[string][]byte) error {
+	if c.Status.ClientSecretExpiresAt != nil {
+		if secret.Annotations == nil {
+			secret.Annotations = map[string]string{}
+		}
+		secret.Annotations[ClientSecretExpiresAtAnnotation] = c.Status.ClientSecretExpiresAt.Format(time.RFC3339)
+	} else {
+		delete(secret.Annotations, ClientSecretExpiresAtAnnotation)
+	}
+	secret.Data = data
+	labelSecret(secret, c)
+	if err := checkBudget(ctx, "updating the secret"); err != nil {
+		return err
+	}
+	return r.Update(ctx, secret)
+}
+
+// previousSecretKey returns the key of the Secret of c the previous client
+// secret is kept under, nothing if its Secret doesn't hold the client secret
+func (r *OAuth2ClientReconciler) previousSecretKey(c *hydrav1alpha1.OAuth2Client) string {
+	for _, item := range r.secretProjectionOf(c).Items {
+		if item.Property == hydrav1alpha1.SecretPropertyClientSecret {
+			return item.GetKey() + previousSecretKeySuffix
+		}
+	}
+	return ""
+}
+
+// overlapPreviousSecret adds the client secret of credentials, which has
+// just been rotated, to data, the content of the Secret of c, for the
+// overlap of its RotationPolicy
+func (r *OAuth2ClientReconciler) overlapPreviousSecret(c *hydrav1alpha1.OAuth2Client, data map[string][]byte, credentials *hydraclient.Oauth2ClientCredentials) {
+	c.Status.PreviousClientSecretExpiresAt = nil
+	key := r.previousSecretKey(c)
+	if c.Spec.RotationPolicy == nil || c.Spec.RotationPolicy.Overlap.Duration <= 0 || key == "" || credentials.Password == nil {
+		return
+	}
+	data[key] = []byte(encodeSecretValue(string(credentials.Password), encodingOf(r.secretProjectionOf(c), hydrav1alpha1.SecretPropertyClientSecret)))
+	expiresAt := metav1.NewTime(time.Now().Add(c.Spec.RotationPolicy.Overlap.Duration).Truncate(time.Second))
+	c.Status.PreviousClientSecretExpiresAt = &expiresAt
+}
+
+// keepPreviousSecret adds the previous client secret held by secret to data,
+// the content of the Secret of c, until its overlap has passed
+func (r *OAuth2ClientReconciler) keepPreviousSecret(c *hydrav1alpha1.OAuth2Client, secret *apiv1.Secret, data map[string][]byte) {
+	if untilPreviousSecretExpiry(c) <= 0 {
+		return
+	}
+	key := r.previousSecretKey(c)
+	if previous, found := secret.Data[key]; key != "" && found {
+		data[key] = previous
+	}
+}
+
+// untilPreviousSecretExpiry returns how long the previous client secret of
+// c is kept in its Secret
+func untilPreviousSecretExpiry(c *hydrav1alpha1.OAuth2Client) time.Duration {
+	if c.Status.PreviousClientSecretExpiresAt == nil {
+		return 0
+	}
+	return time.Until(c.Status.PreviousClientSecretExpiresAt.Time)
+}
+
+// expirePreviousSecret removes the previous client secret from secret once
+// the overlap has passed. It reports whether the status of c changed, and
+// otherwise how long the overlap lasts.
+func (r *OAuth2ClientReconciler) expirePreviousSecret(ctx context.Context, c *hydrav1alpha1.OAuth2Client, secret *apiv1.Secret) (bool, time.Duration, error) {
+	if c.Status.PreviousClientSecretExpiresAt == nil {
+		return false, 0, nil
+	}
+	if remaining := untilPreviousSecretExpiry(c); remaining > 0 {
+		return false, remaining, nil
+	}
+
+	key := r.previousSecretKey(c)
+	if _, found := secret.Data[key]; secret.Name != "" && isOwnedBy(secret, c) && key != "" && found {
+		delete(secret.Data, key)
+		labelSecret(secret, c)
+		if err := checkBudget(ctx, "updating the secret"); err != nil {
+			return false, 0, err
+		}
+		if err := r.Update(ctx, secret); err != nil {
+			return false, 0, err
+		}
+	}
+	c.Status.PreviousClientSecretExpiresAt = nil
+	return true, 0, nil
+}
@@ -0,0 +1,146 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	"github.com/ory/hydra-maester/pkg/hydraclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func rotatedClient(overlap time.Duration) *hydrav1alpha1.OAuth2Client {
+	return &hydrav1alpha1.OAuth2Client{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default", UID: "foo-uid"},
+		Spec: hydrav1alpha1.OAuth2ClientSpec{
+			SecretName:     "foo-secret",
+			RotationPolicy: &hydrav1alpha1.RotationPolicy{MaxAge: metav1.Duration{Duration: time.Hour}, Overlap: metav1.Duration{Duration: overlap}},
+		},
+	}
+}
+
+func TestOverlapPreviousSecret(t *testing.T) {
+
+	r := &OAuth2ClientReconciler{}
+	credentials := &hydraclient.Oauth2ClientCredentials{ID: []byte("foo-id"), Password: []byte("old")}
+
+	t.Run("case=overlap", func(t *testing.T) {
+		c := rotatedClient(10 * time.Minute)
+		data := map[string][]byte{ClientSecretKey: []byte("new")}
+		r.overlapPreviousSecret(c, data, credentials)
+
+		assert.Equal(t, "old", string(data[ClientSecretKey+previousSecretKeySuffix]))
+		require.NotNil(t, c.Status.PreviousClientSecretExpiresAt)
+		assert.InDelta(t, 10*time.Minute, untilPreviousSecretExpiry(c), float64(2*time.Second))
+	})
+
+	t.Run("case=no overlap", func(t *testing.T) {
+		c := rotatedClient(0)
+		c.Status.PreviousClientSecretExpiresAt = &metav1.Time{Time: time.Now().Add(time.Minute)}
+		data := map[string][]byte{ClientSecretKey: []byte("new")}
+		r.overlapPreviousSecret(c, data, credentials)
+
+		assert.NotContains(t, data, ClientSecretKey+previousSecretKeySuffix)
+		assert.Nil(t, c.Status.PreviousClientSecretExpiresAt)
+	})
+}
+
+func TestKeepPreviousSecret(t *testing.T) {
+
+	r := &OAuth2ClientReconciler{}
+	secret := &apiv1.Secret{Data: map[string][]byte{ClientSecretKey: []byte("new"), "client_secret_previous": []byte("old")}}
+
+	t.Run("case=within the overlap", func(t *testing.T) {
+		c := rotatedClient(10 * time.Minute)
+		c.Status.PreviousClientSecretExpiresAt = &metav1.Time{Time: time.Now().Add(time.Minute)}
+		data := map[string][]byte{ClientSecretKey: []byte("new")}
+		r.keepPreviousSecret(c, secret, data)
+		assert.Equal(t, "old", string(data["client_secret_previous"]))
+	})
+
+	t.Run("case=after the overlap", func(t *testing.T) {
+		c := rotatedClient(10 * time.Minute)
+		c.Status.PreviousClientSecretExpiresAt = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+		data := map[string][]byte{ClientSecretKey: []byte("new")}
+		r.keepPreviousSecret(c, secret, data)
+		assert.NotContains(t, data, "client_secret_previous")
+	})
+}
+
+func TestExpirePreviousSecret(t *testing.T) {
+
+	s := runtime.NewScheme()
+	require.NoError(t, apiv1.AddToScheme(s))
+	newSecret := func(c *hydrav1alpha1.OAuth2Client) *apiv1.Secret {
+		return &apiv1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "foo-secret",
+				Namespace:       "default",
+				OwnerReferences: []metav1.OwnerReference{{Name: c.Name, UID: c.UID}},
+			},
+			Data: map[string][]byte{ClientSecretKey: []byte("new"), "client_secret_previous": []byte("old")},
+		}
+	}
+
+	t.Run("case=within the overlap", func(t *testing.T) {
+		c := rotatedClient(10 * time.Minute)
+		c.Status.PreviousClientSecretExpiresAt = &metav1.Time{Time: time.Now().Add(time.Minute)}
+		secret := newSecret(c)
+		r := &OAuth2ClientReconciler{Client: fake.NewFakeClientWithScheme(s, secret.DeepCopy())}
+
+		changed, remaining, err := r.expirePreviousSecret(context.Background(), c, secret)
+		require.NoError(t, err)
+		assert.False(t, changed)
+		assert.True(t, remaining > 0 && remaining <= time.Minute)
+		assert.Contains(t, secret.Data, "client_secret_previous")
+	})
+
+	t.Run("case=after the overlap", func(t *testing.T) {
+		c := rotatedClient(10 * time.Minute)
+		c.Status.PreviousClientSecretExpiresAt = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+		secret := newSecret(c)
+		r := &OAuth2ClientReconciler{Client: fake.NewFakeClientWithScheme(s, secret.DeepCopy())}
+
+		changed, _, err := r.expirePreviousSecret(context.Background(), c, secret)
+		require.NoError(t, err)
+		assert.True(t, changed)
+		assert.Nil(t, c.Status.PreviousClientSecretExpiresAt)
+
+		var stored apiv1.Secret
+		require.NoError(t, r.Get(context.Background(), types.NamespacedName{Name: "foo-secret", Namespace: "default"}, &stored))
+		assert.NotContains(t, stored.Data, "client_secret_previous")
+		assert.Equal(t, "new", string(stored.Data[ClientSecretKey]))
+	})
+
+	t.Run("case=no previous secret", func(t *testing.T) {
+		c := rotatedClient(10 * time.Minute)
+		r := &OAuth2ClientReconciler{}
+
+		changed, remaining, err := r.expirePreviousSecret(context.Background(), c, newSecret(c))
+		require.NoError(t, err)
+		assert.False(t, changed)
+		assert.Zero(t, remaining)
+	})
+}
@@ -0,0 +1,196 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	apiv1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// AcceptSecretsFromAnnotation lists the namespaces, or `*` for all of them,
+// whose OAuth2Clients may keep copies of their Secrets in the annotated
+// namespace
+const AcceptSecretsFromAnnotation = "hydra-maester.ory.sh/accept-secrets-from"
+
+// OAuth2ClientNamespaceLabel is set, along with OAuth2ClientLabel, on the
+// copies of Secrets kept for SecretTargets, to the namespace of their
+// OAuth2Client
+const OAuth2ClientNamespaceLabel = "hydra-maester.ory.sh/oauth2client-namespace"
+
+// acceptsSecretsFrom reports whether ns accepts copies of the Secrets of the
+// OAuth2Clients in namespace
+func acceptsSecretsFrom(ns *apiv1.Namespace, namespace string) bool {
+	for _, accepted := range splitList(ns.Annotations[AcceptSecretsFromAnnotation]) {
+		if accepted == "*" || accepted == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// secretCopyLabels returns the labels of the copies of the Secret of c
+func secretCopyLabels(c *hydrav1alpha1.OAuth2Client) map[string]string {
+	return map[string]string{OAuth2ClientLabel: c.Name, OAuth2ClientNamespaceLabel: c.Namespace}
+}
+
+func isSecretCopyOf(secret *apiv1.Secret, c *hydrav1alpha1.OAuth2Client) bool {
+	return secret.Labels[OAuth2ClientLabel] == c.Name && secret.Labels[OAuth2ClientNamespaceLabel] == c.Namespace
+}
+
+// syncSecretTargets keeps copies of secret, generated for c, in the
+// SecretTargets of c, and deletes the copies which are no longer listed.
+// Targets refusing the copies are reported by the SecretTargetsSynced
+// condition, whose change it reports.
+func (r *OAuth2ClientReconciler) syncSecretTargets(ctx context.Context, c *hydrav1alpha1.OAuth2Client, secret *apiv1.Secret) (bool, error) {
+	// copies are only looked for if some were made
+	if condition := c.Status.GetCondition(hydrav1alpha1.OAuth2ClientConditionSecretTargetsSynced); len(c.Spec.SecretTargets) == 0 && (condition == nil || condition.Reason == "NoSecretTargets") {
+		return false, nil
+	}
+
+	wanted := map[types.NamespacedName]bool{}
+	var denied []string
+	if secret.Name != "" && isOwnedBy(secret, c) {
+		for _, target := range c.Spec.SecretTargets {
+			key := types.NamespacedName{Namespace: target.Namespace, Name: target.Name}
+			if key.Name == "" {
+				key.Name = c.Spec.SecretName
+			}
+			if key.Namespace == c.Namespace && key.Name == c.Spec.SecretName {
+				continue
+			}
+
+			reason, err := r.ensureSecretCopy(ctx, c, secret, key)
+			if err != nil {
+				return false, err
+			}
+			if reason != "" {
+				denied = append(denied, reason)
+				continue
+			}
+			wanted[key] = true
+		}
+	}
+
+	var copies apiv1.SecretList
+	if err := r.List(ctx, &copies, client.MatchingLabels(secretCopyLabels(c))); err != nil {
+		return false, err
+	}
+	for i := range copies.Items {
+		secretCopy := &copies.Items[i]
+		if wanted[types.NamespacedName{Namespace: secretCopy.Namespace, Name: secretCopy.Name}] {
+			continue
+		}
+		if err := r.Delete(ctx, secretCopy); err != nil && !apierrs.IsNotFound(err) {
+			return false, err
+		}
+	}
+
+	switch {
+	case len(denied) > 0:
+		message := strings.Join(denied, "; ")
+		if !c.Status.SetCondition(hydrav1alpha1.OAuth2ClientConditionSecretTargetsSynced, apiv1.ConditionFalse, "SecretTargetDenied", message) {
+			return false, nil
+		}
+		r.recordEvent(c, apiv1.EventTypeWarning, EventReasonSecretTargetDenied, message)
+		return true, nil
+	case len(wanted) == 0:
+		return c.Status.SetCondition(hydrav1alpha1.OAuth2ClientConditionSecretTargetsSynced, apiv1.ConditionFalse, "NoSecretTargets", ""), nil
+	default:
+		return c.Status.SetCondition(hydrav1alpha1.OAuth2ClientConditionSecretTargetsSynced, apiv1.ConditionTrue, "SecretCopied", ""), nil
+	}
+}
+
+// ensureSecretCopy keeps a copy of secret, generated for c, at key. It
+// explains why the copy can't be kept there, nothing if it can.
+func (r *OAuth2ClientReconciler) ensureSecretCopy(ctx context.Context, c *hydrav1alpha1.OAuth2Client, secret *apiv1.Secret, key types.NamespacedName) (string, error) {
+	if key.Namespace != c.Namespace {
+		var ns apiv1.Namespace
+		if err := r.Get(ctx, types.NamespacedName{Name: key.Namespace}, &ns); err != nil {
+			if apierrs.IsNotFound(err) {
+				return fmt.Sprintf("namespace %s does not exist", key.Namespace), nil
+			}
+			return "", err
+		}
+		if !acceptsSecretsFrom(&ns, c.Namespace) {
+			return fmt.Sprintf("namespace %s does not accept secrets from namespace %s, see the %s annotation", key.Namespace, c.Namespace, AcceptSecretsFromAnnotation), nil
+		}
+	}
+
+	var current apiv1.Secret
+	err := r.Get(ctx, key, &current)
+	if err == nil && isSecretCopyOf(&current, c) && current.Type != secret.Type {
+		// the type of a Secret can't be updated
+		if err := r.Delete(ctx, &current); err != nil && !apierrs.IsNotFound(err) {
+			return "", err
+		}
+		err = apierrs.NewNotFound(apiv1.Resource("secrets"), key.Name)
+	}
+	if err != nil {
+		if !apierrs.IsNotFound(err) {
+			return "", err
+		}
+		secretCopy := apiv1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        key.Name,
+				Namespace:   key.Namespace,
+				Labels:      secretCopyLabels(c),
+				Annotations: map[string]string{SecretChecksumAnnotation: secretChecksum(secret.Data)},
+			},
+			Type: secret.Type,
+			Data: secret.Data,
+		}
+		return "", r.Create(ctx, &secretCopy)
+	}
+
+	if !isSecretCopyOf(&current, c) {
+		return fmt.Sprintf("secret %s/%s already exists", key.Namespace, key.Name), nil
+	}
+	if reflect.DeepEqual(current.Data, secret.Data) {
+		return "", nil
+	}
+	current.Data = secret.Data
+	if current.Annotations == nil {
+		current.Annotations = map[string]string{}
+	}
+	current.Annotations[SecretChecksumAnnotation] = secretChecksum(secret.Data)
+	return "", r.Update(ctx, &current)
+}
+
+// deleteSecretCopies deletes the copies of the Secret of c
+func (r *OAuth2ClientReconciler) deleteSecretCopies(ctx context.Context, c *hydrav1alpha1.OAuth2Client) error {
+	if c.Status.GetCondition(hydrav1alpha1.OAuth2ClientConditionSecretTargetsSynced) == nil {
+		return nil
+	}
+	var copies apiv1.SecretList
+	if err := r.List(ctx, &copies, client.MatchingLabels(secretCopyLabels(c))); err != nil {
+		return err
+	}
+	for i := range copies.Items {
+		if err := r.Delete(ctx, &copies.Items[i]); err != nil && !apierrs.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
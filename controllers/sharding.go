@@ -0,0 +1,80 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// selects reports whether the OAuth2Client with the given metadata is
+// reconciled by r, rather than by another deployment of the controller or
+// not at all because of its namespace
+func (r *OAuth2ClientReconciler) selects(meta metav1.Object) bool {
+	if !r.watchesNamespace(meta.GetNamespace()) {
+		return false
+	}
+	return r.LabelSelector == nil || r.LabelSelector.Matches(labels.Set(meta.GetLabels()))
+}
+
+// watchesNamespace reports whether the OAuth2Clients of namespace may be
+// reconciled
+func (r *OAuth2ClientReconciler) watchesNamespace(namespace string) bool {
+	if len(r.WatchNamespaces) > 0 && !containsString(r.WatchNamespaces, namespace) {
+		return false
+	}
+	return !containsString(r.ExcludeNamespaces, namespace)
+}
+
+// selectedPredicate drops the events of OAuth2Clients not reconciled by r. A
+// client whose labels no longer match is left alone from then on.
+func (r *OAuth2ClientReconciler) selectedPredicate() predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return r.selects(e.Meta)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return r.selects(e.MetaNew)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return r.selects(e.Meta)
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return r.selects(e.Meta)
+		},
+	}
+}
+
+// namespacePredicate drops the events of namespaced resources, such as
+// Secrets, in the namespaces whose OAuth2Clients are not reconciled
+func (r *OAuth2ClientReconciler) namespacePredicate() predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return r.watchesNamespace(e.Meta.GetNamespace())
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return r.watchesNamespace(e.MetaNew.GetNamespace())
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return r.watchesNamespace(e.Meta.GetNamespace())
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return r.watchesNamespace(e.Meta.GetNamespace())
+		},
+	}
+}
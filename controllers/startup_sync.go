@@ -0,0 +1,131 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	"github.com/ory/hydra-maester/pkg/hydraclient"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// StartupSync compares the OAuth2Clients with the clients registered in the
+// default ORY Hydra instance once, when the controller starts, so that it
+// recovers from the changes made while it was down. Clients created by the
+// controller whose OAuth2Client was deleted meanwhile are deleted from ORY
+// Hydra, unless they are exempt from garbage collection. OAuth2Clients whose
+// client is missing from ORY Hydra are reported, they are registered again
+// by their first reconciliation.
+type StartupSync struct {
+	// Reader reads OAuth2Clients. It should not be backed by a cache, as the
+	// sync can run before the caches are synced.
+	Reader      client.Reader
+	HydraClient ClientRegistrar
+	// AuditMode, if set, only reports the differences without deleting
+	// clients from ORY Hydra
+	AuditMode bool
+	Log       logr.Logger
+}
+
+// Start implements manager.Runnable. The sync is retried with a backoff
+// while ORY Hydra is unavailable.
+func (s *StartupSync) Start(stop <-chan struct{}) error {
+	delay := hydraStartupBackoffBase
+	for {
+		err := s.sync(context.Background())
+		if err == nil {
+			return nil
+		}
+		if !hydraclient.IsUnavailable(err) {
+			s.Log.Error(err, "startup sync with ORY Hydra failed")
+			return nil
+		}
+		s.Log.Info(fmt.Sprintf("ORY Hydra is unavailable, retrying the startup sync in %s: %s", delay, err))
+
+		select {
+		case <-stop:
+			return nil
+		case <-time.After(delay):
+		}
+		delay *= 2
+		if delay > hydraStartupBackoffMax {
+			delay = hydraStartupBackoffMax
+		}
+	}
+}
+
+func (s *StartupSync) sync(ctx context.Context) error {
+	// ORY Hydra is listed first, so that the client of an OAuth2Client
+	// created meanwhile isn't mistaken for an orphan
+	registered, err := s.HydraClient.ListOAuth2Client()
+	if err != nil {
+		return err
+	}
+	var clients hydrav1alpha1.OAuth2ClientList
+	if err := s.Reader.List(ctx, &clients); err != nil {
+		return err
+	}
+
+	owners := map[types.NamespacedName]bool{}
+	for _, c := range clients.Items {
+		owners[types.NamespacedName{Name: c.Name, Namespace: c.Namespace}] = true
+	}
+
+	ids := map[string]bool{}
+	orphaned := 0
+	for _, cJSON := range registered {
+		if cJSON.ClientID != nil {
+			ids[*cJSON.ClientID] = true
+		}
+		owner, ok := collectableOwner(cJSON)
+		if !ok || owners[owner] {
+			continue
+		}
+		orphaned++
+		if s.AuditMode {
+			s.Log.Info(fmt.Sprintf("client %s of deleted OAuth2Client %s is still registered in ORY Hydra", *cJSON.ClientID, owner))
+			continue
+		}
+		s.Log.Info(fmt.Sprintf("deleting client %s of OAuth2Client %s, which was deleted while the controller was down", *cJSON.ClientID, owner))
+		if err := s.HydraClient.DeleteOAuth2Client(*cJSON.ClientID); err != nil {
+			return err
+		}
+		garbageCollectedClients.Inc()
+	}
+
+	missing := 0
+	for _, c := range clients.Items {
+		if !usesDefaultHydra(&c) || !c.DeletionTimestamp.IsZero() || c.Spec.Suspend || c.Status.ClientID == "" || ids[c.Status.ClientID] {
+			continue
+		}
+		missing++
+		s.Log.Info(fmt.Sprintf("client %s of OAuth2Client %s/%s is missing from ORY Hydra, it is registered again by its reconciliation", c.Status.ClientID, c.Namespace, c.Name))
+	}
+
+	s.Log.Info(fmt.Sprintf("startup sync compared %d OAuth2Clients with %d clients in ORY Hydra: %d orphaned, %d missing", len(clients.Items), len(registered), orphaned, missing))
+	return nil
+}
+
+// usesDefaultHydra reports whether c is registered in the default ORY Hydra
+// instance rather than one set in its spec
+func usesDefaultHydra(c *hydrav1alpha1.OAuth2Client) bool {
+	return c.Spec.HydraInstanceRef == nil && c.Spec.HydraAdmin == (hydrav1alpha1.HydraAdmin{})
+}
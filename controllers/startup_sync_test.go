@@ -0,0 +1,162 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	"github.com/ory/hydra-maester/controllers"
+	"github.com/ory/hydra-maester/controllers/mocks"
+	"github.com/ory/hydra-maester/pkg/hydraclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// registeredClient returns a client registered in ORY Hydra for owner, an
+// OAuth2Client given as name/namespace, created by the controller if
+// managed is set
+func registeredClient(id, owner string, managed, gcExempt bool) *hydraclient.OAuth2ClientJSON {
+	return &hydraclient.OAuth2ClientJSON{
+		ClientID: &id,
+		Owner:    owner,
+		Metadata: []byte(fmt.Sprintf(`{%q: %t, %q: %t}`, hydraclient.MetadataManagedKey, managed, hydraclient.MetadataGCExemptKey, gcExempt)),
+	}
+}
+
+func newStartupSync(t *testing.T, hydra *mocks.HydraClientInterface, clients ...runtime.Object) *controllers.StartupSync {
+	s := runtime.NewScheme()
+	require.NoError(t, hydrav1alpha1.AddToScheme(s))
+	return &controllers.StartupSync{
+		Reader:      fake.NewFakeClientWithScheme(s, clients...),
+		HydraClient: hydra,
+		Log:         ctrl.Log.WithName("test"),
+	}
+}
+
+func oauth2Client(name, clientID string) *hydrav1alpha1.OAuth2Client {
+	return &hydrav1alpha1.OAuth2Client{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Status:     hydrav1alpha1.OAuth2ClientStatus{ClientID: clientID},
+	}
+}
+
+func TestStartupSync(t *testing.T) {
+
+	t.Run("case=orphaned clients are deleted", func(t *testing.T) {
+		hydra := &mocks.HydraClientInterface{}
+		hydra.On("ListOAuth2Client").Return([]*hydraclient.OAuth2ClientJSON{
+			registeredClient("orphan-id", "deleted/default", true, false),
+		}, nil)
+		hydra.On("DeleteOAuth2Client", "orphan-id").Return(nil)
+
+		require.NoError(t, newStartupSync(t, hydra).Start(make(chan struct{})))
+		hydra.AssertExpectations(t)
+	})
+
+	t.Run("case=clients of existing OAuth2Clients are kept", func(t *testing.T) {
+		hydra := &mocks.HydraClientInterface{}
+		hydra.On("ListOAuth2Client").Return([]*hydraclient.OAuth2ClientJSON{
+			registeredClient("foo-id", "foo/default", true, false),
+		}, nil)
+
+		require.NoError(t, newStartupSync(t, hydra, oauth2Client("foo", "foo-id")).Start(make(chan struct{})))
+		hydra.AssertNotCalled(t, "DeleteOAuth2Client", mock.Anything)
+	})
+
+	t.Run("case=unmanaged and exempt clients are kept", func(t *testing.T) {
+		hydra := &mocks.HydraClientInterface{}
+		hydra.On("ListOAuth2Client").Return([]*hydraclient.OAuth2ClientJSON{
+			registeredClient("unmanaged-id", "deleted/default", false, false),
+			registeredClient("exempt-id", "deleted/default", true, true),
+			registeredClient("foreign-id", "someone", true, false),
+		}, nil)
+
+		require.NoError(t, newStartupSync(t, hydra).Start(make(chan struct{})))
+		hydra.AssertNotCalled(t, "DeleteOAuth2Client", mock.Anything)
+	})
+
+	t.Run("case=orphaned clients are only reported in audit mode", func(t *testing.T) {
+		hydra := &mocks.HydraClientInterface{}
+		hydra.On("ListOAuth2Client").Return([]*hydraclient.OAuth2ClientJSON{
+			registeredClient("orphan-id", "deleted/default", true, false),
+		}, nil)
+
+		sync := newStartupSync(t, hydra)
+		sync.AuditMode = true
+		require.NoError(t, sync.Start(make(chan struct{})))
+		hydra.AssertNotCalled(t, "DeleteOAuth2Client", mock.Anything)
+	})
+
+	t.Run("case=missing clients are left to their reconciliation", func(t *testing.T) {
+		hydra := &mocks.HydraClientInterface{}
+		hydra.On("ListOAuth2Client").Return([]*hydraclient.OAuth2ClientJSON{}, nil)
+
+		require.NoError(t, newStartupSync(t, hydra, oauth2Client("foo", "foo-id"), oauth2Client("new", "")).Start(make(chan struct{})))
+		hydra.AssertNotCalled(t, "PostOAuth2Client", mock.Anything)
+		hydra.AssertNotCalled(t, "DeleteOAuth2Client", mock.Anything)
+	})
+
+	t.Run("case=retried while ORY Hydra is unavailable", func(t *testing.T) {
+		hydra := &mocks.HydraClientInterface{}
+		hydra.On("ListOAuth2Client").Return(nil, &url.Error{Op: "Get", URL: "http://hydra", Err: errors.New("connection refused")}).Once()
+		hydra.On("ListOAuth2Client").Return([]*hydraclient.OAuth2ClientJSON{
+			registeredClient("orphan-id", "deleted/default", true, false),
+		}, nil).Once()
+		hydra.On("DeleteOAuth2Client", "orphan-id").Return(nil)
+
+		require.NoError(t, newStartupSync(t, hydra).Start(make(chan struct{})))
+		hydra.AssertExpectations(t)
+	})
+
+	t.Run("case=stopped while ORY Hydra is unavailable", func(t *testing.T) {
+		hydra := &mocks.HydraClientInterface{}
+		hydra.On("ListOAuth2Client").Return(nil, &url.Error{Op: "Get", URL: "http://hydra", Err: errors.New("connection refused")})
+
+		stop := make(chan struct{})
+		close(stop)
+		require.NoError(t, newStartupSync(t, hydra).Start(stop))
+		hydra.AssertNumberOfCalls(t, "ListOAuth2Client", 1)
+	})
+
+	t.Run("case=not retried on other errors", func(t *testing.T) {
+		hydra := &mocks.HydraClientInterface{}
+		hydra.On("ListOAuth2Client").Return(nil, &hydraclient.StatusError{StatusCode: 401})
+
+		require.NoError(t, newStartupSync(t, hydra).Start(make(chan struct{})))
+		hydra.AssertNumberOfCalls(t, "ListOAuth2Client", 1)
+	})
+}
+
+func TestStartupSyncDeletionFailure(t *testing.T) {
+
+	hydra := &mocks.HydraClientInterface{}
+	hydra.On("ListOAuth2Client").Return([]*hydraclient.OAuth2ClientJSON{
+		registeredClient("orphan-id", "deleted/default", true, false),
+	}, nil)
+	hydra.On("DeleteOAuth2Client", "orphan-id").Return(&hydraclient.StatusError{StatusCode: 403})
+
+	assert.NoError(t, newStartupSync(t, hydra).Start(make(chan struct{})))
+	hydra.AssertNumberOfCalls(t, "ListOAuth2Client", 1)
+}
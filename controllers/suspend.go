@@ -0,0 +1,34 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+// checkSuspended sets the Suspended condition of c. It reports whether the
+// condition has changed. Clients which have never been suspended don't get
+// the condition.
+func checkSuspended(c *hydrav1alpha1.OAuth2Client) bool {
+	if c.Spec.Suspend {
+		return c.Status.SetCondition(hydrav1alpha1.OAuth2ClientConditionSuspended, apiv1.ConditionTrue, "Suspended", "reconciliation is suspended by spec.suspend")
+	}
+	if c.Status.GetCondition(hydrav1alpha1.OAuth2ClientConditionSuspended) == nil {
+		return false
+	}
+	return c.Status.SetCondition(hydrav1alpha1.OAuth2ClientConditionSuspended, apiv1.ConditionFalse, "Resumed", "")
+}
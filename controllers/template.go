@@ -0,0 +1,71 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// templateFor returns the OAuth2ClientTemplate referenced by c, or nil if c
+// doesn't reference one
+func (r *OAuth2ClientReconciler) templateFor(ctx context.Context, c *hydrav1alpha1.OAuth2Client) (*hydrav1alpha1.OAuth2ClientTemplate, error) {
+	if c.Spec.TemplateRef == nil {
+		return nil, nil
+	}
+
+	var template hydrav1alpha1.OAuth2ClientTemplate
+	if err := r.Get(ctx, types.NamespacedName{Name: c.Spec.TemplateRef.Name, Namespace: c.Namespace}, &template); err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// templateGeneration returns the generation of template, or 0 if there is
+// no template
+func templateGeneration(template *hydrav1alpha1.OAuth2ClientTemplate) int64 {
+	if template == nil {
+		return 0
+	}
+	return template.Generation
+}
+
+// templateToOAuth2Clients maps OAuth2ClientTemplates to the OAuth2Clients
+// referencing them
+func (r *OAuth2ClientReconciler) templateToOAuth2Clients() handler.EventHandler {
+	return &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+			var clients hydrav1alpha1.OAuth2ClientList
+			if err := r.List(context.Background(), &clients, client.InNamespace(o.Meta.GetNamespace())); err != nil {
+				r.Log.Error(err, "unable to list OAuth2Clients for template", "template", o.Meta.GetName())
+				return nil
+			}
+
+			var requests []reconcile.Request
+			for _, c := range clients.Items {
+				if r.selects(&c) && c.Spec.TemplateRef != nil && c.Spec.TemplateRef.Name == o.Meta.GetName() {
+					requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: c.Name, Namespace: c.Namespace}})
+				}
+			}
+			return requests
+		}),
+	}
+}
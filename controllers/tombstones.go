@@ -0,0 +1,64 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"sync"
+	"time"
+)
+
+// tombstoneTTL is how long the ID of a client deleted from ORY Hydra by the
+// controller is remembered
+const tombstoneTTL = time.Minute
+
+// tombstones remembers the IDs of clients recently deleted from ORY Hydra by
+// the controller, so that reconciliations of stale versions of their
+// OAuth2Clients, e.g. from a lagging cache, don't register them again
+type tombstones struct {
+	mu      sync.Mutex
+	deleted map[string]time.Time
+}
+
+// add records the deletion of the client with the given ID
+func (t *tombstones) add(id string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.deleted == nil {
+		t.deleted = map[string]time.Time{}
+	}
+	t.deleted[id] = time.Now()
+}
+
+// remaining returns for how long the deletion of the client with the given
+// ID is still remembered, or 0 if it isn't
+func (t *tombstones) remaining(id string) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	for deletedID, at := range t.deleted {
+		if now.Sub(at) >= tombstoneTTL {
+			delete(t.deleted, deletedID)
+		}
+	}
+
+	at, ok := t.deleted[id]
+	if !ok {
+		return 0
+	}
+	return tombstoneTTL - now.Sub(at)
+}
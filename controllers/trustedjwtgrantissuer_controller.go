@@ -0,0 +1,168 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	"github.com/ory/hydra-maester/pkg/hydraclient"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// EventReasonIssuerTrusted is the reason of the events emitted for
+// TrustedJwtGrantIssuers when they have been trusted in ORY Hydra
+const EventReasonIssuerTrusted = "IssuerTrusted"
+
+// HydraTrustClient manages the trusted JWT grant issuers of ORY Hydra
+type HydraTrustClient interface {
+	GetTrustedJwtGrantIssuer(id string) (*hydraclient.TrustedJwtGrantIssuer, bool, error)
+	CreateTrustedJwtGrantIssuer(i *hydraclient.TrustedJwtGrantIssuer) (*hydraclient.TrustedJwtGrantIssuer, error)
+	DeleteTrustedJwtGrantIssuer(id string) error
+}
+
+// TrustedJwtGrantIssuerReconciler reconciles a TrustedJwtGrantIssuer object
+type TrustedJwtGrantIssuerReconciler struct {
+	client.Client
+	HydraClient HydraTrustClient
+	Recorder    record.EventRecorder
+	Log         logr.Logger
+}
+
+// +kubebuilder:rbac:groups=hydra.ory.sh,resources=trustedjwtgrantissuers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=hydra.ory.sh,resources=trustedjwtgrantissuers/status,verbs=get;update;patch
+
+func (r *TrustedJwtGrantIssuerReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.Background()
+	_ = r.Log.WithValues("trustedjwtgrantissuer", req.NamespacedName)
+
+	var issuer hydrav1alpha1.TrustedJwtGrantIssuer
+	if err := r.Get(ctx, req.NamespacedName, &issuer); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !issuer.DeletionTimestamp.IsZero() {
+		if !containsString(issuer.Finalizers, FinalizerName) {
+			return ctrl.Result{}, nil
+		}
+		if issuer.Status.ID != "" {
+			if err := r.HydraClient.DeleteTrustedJwtGrantIssuer(issuer.Status.ID); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		issuer.Finalizers = removeString(issuer.Finalizers, FinalizerName)
+		return ctrl.Result{}, r.Update(ctx, &issuer)
+	}
+	if !containsString(issuer.Finalizers, FinalizerName) {
+		issuer.Finalizers = append(issuer.Finalizers, FinalizerName)
+		if err := r.Update(ctx, &issuer); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if err := issuer.Validate(); err != nil {
+		// retrying doesn't help until the spec is fixed
+		issuer.Status.ReconciliationError = hydrav1alpha1.ReconciliationError{
+			Code:        hydrav1alpha1.StatusInvalidSpec,
+			Description: err.Error(),
+		}
+		return ctrl.Result{}, r.Status().Update(ctx, &issuer)
+	}
+
+	if issuer.Status.ID != "" {
+		if issuer.Status.ObservedGeneration == issuer.Generation {
+			_, found, err := r.HydraClient.GetTrustedJwtGrantIssuer(issuer.Status.ID)
+			if err != nil {
+				return ctrl.Result{}, r.updateStatusError(ctx, &issuer, hydrav1alpha1.StatusTrustFailed, err)
+			}
+			if found {
+				return ctrl.Result{}, nil
+			}
+			// the trust relationship has been deleted outside of the
+			// controller, it is created again below
+		} else {
+			// trust relationships can't be updated in ORY Hydra, so a changed
+			// spec replaces the previous one
+			if err := r.HydraClient.DeleteTrustedJwtGrantIssuer(issuer.Status.ID); err != nil {
+				return ctrl.Result{}, r.updateStatusError(ctx, &issuer, hydrav1alpha1.StatusTrustFailed, err)
+			}
+		}
+		issuer.Status.ID = ""
+	}
+
+	trusted, err := issuerForSpec(&issuer.Spec)
+	if err == nil {
+		trusted, err = r.HydraClient.CreateTrustedJwtGrantIssuer(trusted)
+	}
+	if err != nil {
+		return ctrl.Result{}, r.updateStatusError(ctx, &issuer, hydrav1alpha1.StatusTrustFailed, err)
+	}
+
+	issuer.Status.ID = trusted.ID
+	issuer.Status.ObservedGeneration = issuer.Generation
+	issuer.Status.ReconciliationError = hydrav1alpha1.ReconciliationError{}
+	if err := r.Status().Update(ctx, &issuer); err != nil {
+		return ctrl.Result{}, err
+	}
+	if r.Recorder != nil {
+		r.Recorder.Event(&issuer, apiv1.EventTypeNormal, EventReasonIssuerTrusted, fmt.Sprintf("trusted issuer %s as %s", issuer.Spec.Issuer, trusted.ID))
+	}
+	return ctrl.Result{}, nil
+}
+
+func (r *TrustedJwtGrantIssuerReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&hydrav1alpha1.TrustedJwtGrantIssuer{}).
+		Complete(r)
+}
+
+// issuerForSpec returns the trusted JWT grant issuer described by spec
+func issuerForSpec(spec *hydrav1alpha1.TrustedJwtGrantIssuerSpec) (*hydraclient.TrustedJwtGrantIssuer, error) {
+	i := &hydraclient.TrustedJwtGrantIssuer{
+		Issuer:          spec.Issuer,
+		Subject:         spec.Subject,
+		AllowAnySubject: spec.AllowAnySubject,
+		Scope:           spec.Scope,
+		ExpiresAt:       spec.ExpiresAt.UTC(),
+	}
+
+	jwk, err := json.Marshal(spec.JSONWebKey)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(jwk, &i.JSONWebKey); err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+func (r *TrustedJwtGrantIssuerReconciler) updateStatusError(ctx context.Context, i *hydrav1alpha1.TrustedJwtGrantIssuer, code hydrav1alpha1.StatusCode, err error) error {
+	r.Log.Error(err, fmt.Sprintf("error processing trusted JWT grant issuer %s/%s", i.Namespace, i.Name))
+	i.Status.ReconciliationError = hydrav1alpha1.ReconciliationError{
+		Code:        code,
+		Description: err.Error(),
+	}
+	if updateErr := r.Status().Update(ctx, i); updateErr != nil {
+		return updateErr
+	}
+	return err
+}
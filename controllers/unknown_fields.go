@@ -0,0 +1,119 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	hydrav1beta1 "github.com/ory/hydra-maester/api/v1beta1"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// unknownFields returns the paths of the spec fields of the manifest last
+// applied to c which are unknown to the controller, and thus were dropped
+// when c was stored. Without a last applied manifest nothing is detected.
+func unknownFields(c *hydrav1alpha1.OAuth2Client) ([]string, error) {
+	lastApplied, ok := c.Annotations[apiv1.LastAppliedConfigAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	var manifest struct {
+		APIVersion string      `json:"apiVersion"`
+		Spec       interface{} `json:"spec"`
+	}
+	if err := json.Unmarshal([]byte(lastApplied), &manifest); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", apiv1.LastAppliedConfigAnnotation, err)
+	}
+
+	spec := reflect.TypeOf(hydrav1alpha1.OAuth2ClientSpec{})
+	if manifest.APIVersion == hydrav1beta1.GroupVersion.String() {
+		spec = reflect.TypeOf(hydrav1beta1.OAuth2ClientSpec{})
+	}
+	return unknownFieldsOf(manifest.Spec, spec, "spec"), nil
+}
+
+// unknownFieldsOf returns the paths of the fields of value which are not
+// fields of t. Types decoding JSON themselves, and maps, accept any field.
+func unknownFieldsOf(value interface{}, t reflect.Type, path string) []string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if reflect.PtrTo(t).Implements(jsonUnmarshalerType) {
+		return nil
+	}
+
+	var unknown []string
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if t.Kind() != reflect.Struct {
+			return nil
+		}
+		fields := jsonFields(t)
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			field, ok := fields[name]
+			if !ok {
+				unknown = append(unknown, path+"."+name)
+				continue
+			}
+			unknown = append(unknown, unknownFieldsOf(v[name], field, path+"."+name)...)
+		}
+	case []interface{}:
+		if t.Kind() != reflect.Slice {
+			return nil
+		}
+		for i, item := range v {
+			unknown = append(unknown, unknownFieldsOf(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	}
+	return unknown
+}
+
+// jsonFields maps the JSON names of the fields of the struct type t to
+// their types
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" && field.Anonymous && field.Type.Kind() == reflect.Struct {
+			for embedded, ft := range jsonFields(field.Type) {
+				fields[embedded] = ft
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
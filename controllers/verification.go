@@ -0,0 +1,230 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/url"
+	"strings"
+
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	apiv1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	verificationMountPath = "/var/run/secrets/hydra-maester"
+	// verificationDeadline bounds the run time of verification Jobs, so
+	// that an unreachable token endpoint fails them
+	verificationDeadline = 120
+)
+
+// verifyCredentials checks, with a Job mounting the Secret of the client
+// behind req, that its credentials are accepted by ORY Hydra's token
+// endpoint, and reports the outcome in the CredentialsVerified condition.
+// A Job is spawned for each generation of the client and version of its
+// Secret; the Job watch brings its outcome back to the reconciler.
+func (r *OAuth2ClientReconciler) verifyCredentials(ctx context.Context, req ctrl.Request) error {
+	var c hydrav1alpha1.OAuth2Client
+	if err := r.Get(ctx, req.NamespacedName, &c); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !c.DeletionTimestamp.IsZero() || c.Status.ObservedGeneration != c.Generation || !c.Status.IsConditionTrue(hydrav1alpha1.OAuth2ClientConditionReady) {
+		return nil
+	}
+
+	tokenURL, ok := r.verifiableTokenURL(&c)
+	if !ok {
+		return nil
+	}
+
+	var secret apiv1.Secret
+	if err := r.Get(ctx, types.NamespacedName{Name: c.Spec.SecretName, Namespace: c.Namespace}, &secret); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	name := verificationJobName(&c, &secret)
+	var job batchv1.Job
+	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: c.Namespace}, &job)
+	switch {
+	case apierrs.IsNotFound(err):
+		if err := r.deleteVerificationJobs(ctx, &c); err != nil {
+			return err
+		}
+		if err := r.Create(ctx, r.verificationJob(&c, name, tokenURL)); err != nil && !apierrs.IsAlreadyExists(err) {
+			return fmt.Errorf("unable to create verification Job %s/%s: %w", c.Namespace, name, err)
+		}
+		if !c.Status.SetCondition(hydrav1alpha1.OAuth2ClientConditionCredentialsVerified, apiv1.ConditionUnknown, "VerificationPending", fmt.Sprintf("Job %s is requesting a token", name)) {
+			return nil
+		}
+	case err != nil:
+		return err
+	case job.Status.Succeeded > 0:
+		if !c.Status.SetCondition(hydrav1alpha1.OAuth2ClientConditionCredentialsVerified, apiv1.ConditionTrue, EventReasonCredentialsVerified, fmt.Sprintf("Job %s obtained a token", name)) {
+			return nil
+		}
+		r.recordEvent(&c, apiv1.EventTypeNormal, EventReasonCredentialsVerified, fmt.Sprintf("Job %s obtained a token with the credentials of Secret %s", name, secret.Name))
+	case job.Status.Failed > 0:
+		message := fmt.Sprintf("Job %s could not obtain a token, see the logs of its Pod", name)
+		if !c.Status.SetCondition(hydrav1alpha1.OAuth2ClientConditionCredentialsVerified, apiv1.ConditionFalse, EventReasonCredentialsVerificationFailed, message) {
+			return nil
+		}
+		r.recordEvent(&c, apiv1.EventTypeWarning, EventReasonCredentialsVerificationFailed, message)
+	default:
+		return nil
+	}
+	return r.updateClientStatus(ctx, &c)
+}
+
+// verifiableTokenURL returns the token endpoint c can be verified against.
+// Only confidential clients allowed to use the client credentials grant,
+// whose Secret holds their ID and secret under keys of their own, are.
+func (r *OAuth2ClientReconciler) verifiableTokenURL(c *hydrav1alpha1.OAuth2Client) (string, bool) {
+	if r.HydraPublicURL == "" || c.Spec.SecretName == "" || !c.HasGrantType("client_credentials") {
+		return "", false
+	}
+	switch c.GetTokenEndpointAuthMethod() {
+	case "", "client_secret_basic", "client_secret_post":
+	default:
+		return "", false
+	}
+	if p := r.secretProjectionOf(c); p.Format == hydrav1alpha1.SecretProjectionFormatJSON {
+		return "", false
+	}
+	if _, ok := r.projectedKey(c, hydrav1alpha1.SecretPropertyClientID); !ok {
+		return "", false
+	}
+	if _, ok := r.projectedKey(c, hydrav1alpha1.SecretPropertyClientSecret); !ok {
+		return "", false
+	}
+
+	u, err := url.Parse(r.HydraPublicURL)
+	if err != nil {
+		r.Log.Error(err, fmt.Sprintf("hydra public URL %s is invalid", r.HydraPublicURL))
+		return "", false
+	}
+	return u.ResolveReference(&url.URL{Path: tokenPath}).String(), true
+}
+
+// projectedKey returns the key of the Secret of c holding the given property
+func (r *OAuth2ClientReconciler) projectedKey(c *hydrav1alpha1.OAuth2Client, property hydrav1alpha1.SecretProperty) (string, bool) {
+	for _, item := range r.secretProjectionOf(c).Items {
+		if item.Property == property {
+			return item.GetKey(), true
+		}
+	}
+	return "", false
+}
+
+// verificationJobName returns the name of the Job verifying the current
+// generation of c with the current version of its Secret
+func verificationJobName(c *hydrav1alpha1.OAuth2Client, secret *apiv1.Secret) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d/%s", c.Generation, secret.ResourceVersion)))
+	name := c.Name
+	if len(name) > 47 {
+		name = strings.TrimRight(name[:47], "-.")
+	}
+	return fmt.Sprintf("%s-verify-%x", name, sum[:4])
+}
+
+// verificationJob returns the Job requesting a token for c with the
+// credentials mounted from its Secret
+func (r *OAuth2ClientReconciler) verificationJob(c *hydrav1alpha1.OAuth2Client, name, tokenURL string) *batchv1.Job {
+	idKey, _ := r.projectedKey(c, hydrav1alpha1.SecretPropertyClientID)
+	secretKey, _ := r.projectedKey(c, hydrav1alpha1.SecretPropertyClientSecret)
+
+	authentication := `--user "$CLIENT_ID:$CLIENT_SECRET"`
+	if c.GetTokenEndpointAuthMethod() == "client_secret_post" {
+		authentication = `--data-urlencode "client_id=$CLIENT_ID" --data-urlencode "client_secret=$CLIENT_SECRET"`
+	}
+	script := strings.Join([]string{
+		fmt.Sprintf(`CLIENT_ID="$(cat %s/client_id)"`, verificationMountPath),
+		fmt.Sprintf(`CLIENT_SECRET="$(cat %s/client_secret)"`, verificationMountPath),
+		fmt.Sprintf(`curl --fail --silent --show-error --output /dev/null %s --data grant_type=client_credentials --data-urlencode "scope=$SCOPE" "$TOKEN_URL"`, authentication),
+	}, "\n")
+
+	owner := ownerReferenceTo(c)
+	backoffLimit := int32(0)
+	deadline := int64(verificationDeadline)
+	labels := map[string]string{OAuth2ClientLabel: c.Name}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       c.Namespace,
+			Labels:          labels,
+			OwnerReferences: []metav1.OwnerReference{owner},
+		},
+		Spec: batchv1.JobSpec{
+			// a rejected token request is reported, not retried
+			BackoffLimit:          &backoffLimit,
+			ActiveDeadlineSeconds: &deadline,
+			Template: apiv1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: apiv1.PodSpec{
+					RestartPolicy: apiv1.RestartPolicyNever,
+					Containers: []apiv1.Container{{
+						Name:    "verify",
+						Image:   r.VerificationImage,
+						Command: []string{"/bin/sh", "-c", script},
+						Env: []apiv1.EnvVar{
+							{Name: "TOKEN_URL", Value: tokenURL},
+							{Name: "SCOPE", Value: c.GetScope()},
+						},
+						VolumeMounts: []apiv1.VolumeMount{{Name: "credentials", MountPath: verificationMountPath, ReadOnly: true}},
+					}},
+					Volumes: []apiv1.Volume{{
+						Name: "credentials",
+						VolumeSource: apiv1.VolumeSource{
+							Secret: &apiv1.SecretVolumeSource{
+								SecretName: c.Spec.SecretName,
+								Items: []apiv1.KeyToPath{
+									{Key: idKey, Path: ClientIDKey},
+									{Key: secretKey, Path: ClientSecretKey},
+								},
+							},
+						},
+					}},
+				},
+			},
+		},
+	}
+}
+
+// deleteVerificationJobs deletes the verification Jobs of previous
+// generations of c, along with their Pods
+func (r *OAuth2ClientReconciler) deleteVerificationJobs(ctx context.Context, c *hydrav1alpha1.OAuth2Client) error {
+	var jobs batchv1.JobList
+	if err := r.List(ctx, &jobs, client.InNamespace(c.Namespace), client.MatchingLabels(map[string]string{OAuth2ClientLabel: c.Name})); err != nil {
+		return fmt.Errorf("unable to list verification Jobs of client %s/%s: %w", c.Namespace, c.Name, err)
+	}
+	for i := range jobs.Items {
+		if !metav1.IsControlledBy(&jobs.Items[i], c) {
+			continue
+		}
+		if err := r.Delete(ctx, &jobs.Items[i], client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !apierrs.IsNotFound(err) {
+			return fmt.Errorf("unable to delete verification Job %s/%s: %w", c.Namespace, jobs.Items[i].Name, err)
+		}
+	}
+	return nil
+}
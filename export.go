@@ -0,0 +1,304 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	"github.com/ory/hydra-maester/pkg/hydraclient"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// exportSource is where the export subcommand reads the clients from
+type exportSource string
+
+const (
+	exportFromHydra   exportSource = "hydra"
+	exportFromCluster exportSource = "cluster"
+)
+
+// invalidNameChars matches the characters not allowed in resource names
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+// manifest is an OAuth2Client without its status and the metadata set by
+// the API server
+type manifest struct {
+	APIVersion string                         `json:"apiVersion"`
+	Kind       string                         `json:"kind"`
+	Metadata   manifestMetadata               `json:"metadata"`
+	Spec       hydrav1alpha1.OAuth2ClientSpec `json:"spec"`
+}
+
+type manifestMetadata struct {
+	Name        string            `json:"name"`
+	Namespace   string            `json:"namespace,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// runExport implements the export subcommand, which writes the clients
+// registered in ORY Hydra, or the OAuth2Clients in the cluster, to out as
+// OAuth2Client manifests
+func runExport(args []string, out io.Writer) error {
+	var (
+		from, hydraURL, endpoint, forwardedProto, namespace string
+		hydraPort                                           int
+	)
+
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	flags.StringVar(&from, "from", string(exportFromHydra), "Where to read the clients from, hydra or cluster")
+	flags.StringVar(&hydraURL, "hydra-url", "", "The address of ORY Hydra, required with --from=hydra")
+	flags.IntVar(&hydraPort, "hydra-port", 4445, "Port ORY Hydra is listening on")
+	flags.StringVar(&endpoint, "endpoint", "/clients", "ORY Hydra's client endpoint")
+	flags.StringVar(&forwardedProto, "forwarded-proto", "", "If set, this adds the value as the X-Forwarded-Proto header in requests to the ORY Hydra admin server")
+	flags.StringVar(&namespace, "namespace", "", "With --from=cluster, the namespace to export the OAuth2Clients of, all namespaces if not set. With --from=hydra, the namespace of the clients not created by the controller")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var manifests []manifest
+	switch exportSource(from) {
+	case exportFromHydra:
+		if hydraURL == "" {
+			return fmt.Errorf("hydra URL can't be empty")
+		}
+		spec := hydrav1alpha1.OAuth2ClientSpec{
+			HydraAdmin: hydrav1alpha1.HydraAdmin{
+				URL:            hydraURL,
+				Port:           hydraPort,
+				Endpoint:       endpoint,
+				ForwardedProto: forwardedProto,
+			},
+		}
+		hydraClient, err := getHydraClientMaker(spec, nil, 0)(spec)
+		if err != nil {
+			return err
+		}
+		clients, err := hydraClient.ListOAuth2Client()
+		if err != nil {
+			return fmt.Errorf("unable to list the clients of ORY Hydra: %w", err)
+		}
+		if namespace == "" {
+			namespace = "default"
+		}
+		for _, c := range clients {
+			manifests = append(manifests, manifestFromHydra(c, namespace))
+		}
+	case exportFromCluster:
+		k8sClient, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+		if err != nil {
+			return err
+		}
+		if manifests, err = clusterManifests(k8sClient, namespace); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown source %s, must be hydra or cluster", from)
+	}
+	return writeManifests(out, manifests)
+}
+
+// clusterManifests returns the manifests of the OAuth2Clients read by
+// reader in namespace, or in all namespaces if it is empty
+func clusterManifests(reader client.Reader, namespace string) ([]manifest, error) {
+	var clients hydrav1alpha1.OAuth2ClientList
+	if err := reader.List(context.Background(), &clients, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("unable to list the OAuth2Clients: %w", err)
+	}
+	manifests := make([]manifest, 0, len(clients.Items))
+	for i := range clients.Items {
+		manifests = append(manifests, manifestFromCluster(&clients.Items[i]))
+	}
+	return manifests, nil
+}
+
+// writeManifests writes manifests to out as a stream of YAML documents,
+// sorted by namespace and name
+func writeManifests(out io.Writer, manifests []manifest) error {
+	sort.Slice(manifests, func(i, j int) bool {
+		if manifests[i].Metadata.Namespace != manifests[j].Metadata.Namespace {
+			return manifests[i].Metadata.Namespace < manifests[j].Metadata.Namespace
+		}
+		return manifests[i].Metadata.Name < manifests[j].Metadata.Name
+	})
+	for _, m := range manifests {
+		raw, err := marshalManifest(m)
+		if err != nil {
+			return fmt.Errorf("unable to marshal the manifest of %s/%s: %w", m.Metadata.Namespace, m.Metadata.Name, err)
+		}
+		if _, err := fmt.Fprintf(out, "---\n%s", raw); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// manifestFromCluster returns the manifest of an OAuth2Client in the cluster
+func manifestFromCluster(c *hydrav1alpha1.OAuth2Client) manifest {
+	annotations := map[string]string{}
+	for key, value := range c.Annotations {
+		if key != "kubectl.kubernetes.io/last-applied-configuration" {
+			annotations[key] = value
+		}
+	}
+	return manifest{
+		APIVersion: hydrav1alpha1.GroupVersion.String(),
+		Kind:       "OAuth2Client",
+		Metadata: manifestMetadata{
+			Name:        c.Name,
+			Namespace:   c.Namespace,
+			Labels:      c.Labels,
+			Annotations: annotations,
+		},
+		Spec: c.Spec,
+	}
+}
+
+// manifestFromHydra returns the manifest of a client registered in ORY
+// Hydra. Clients created by the controller keep the name and namespace of
+// their OAuth2Client, others are named after the client in namespace. Only
+// the properties modeled by the spec are exported, as the others include
+// the ones set by ORY Hydra such as created_at.
+func manifestFromHydra(c *hydraclient.OAuth2ClientJSON, namespace string) manifest {
+	var clientID string
+	if c.ClientID != nil {
+		clientID = *c.ClientID
+	}
+
+	name := resourceName(c.ClientName, clientID)
+	if parts := strings.Split(c.Owner, "/"); c.IsManaged() && len(parts) == 2 && parts[0] != "" && parts[1] != "" {
+		name, namespace = parts[0], parts[1]
+	}
+
+	spec := hydrav1alpha1.OAuth2ClientSpec{
+		ClientName:                  c.ClientName,
+		ClientID:                    clientID,
+		Audience:                    c.Audience,
+		Scope:                       c.Scope,
+		SecretName:                  name,
+		TokenEndpointAuthMethod:     hydrav1alpha1.TokenEndpointAuthMethod(c.TokenEndpointAuthMethod),
+		TokenEndpointAuthSigningAlg: c.TokenEndpointAuthSigningAlg,
+		AccessTokenStrategy:         hydrav1alpha1.AccessTokenStrategy(c.AccessTokenStrategy),
+		SkipConsent:                 c.SkipConsent,
+		SkipLogoutConsent:           c.SkipLogoutConsent,
+		GCExempt:                    c.IsGCExempt(),
+	}
+	for _, gt := range c.GrantTypes {
+		spec.GrantTypes = append(spec.GrantTypes, hydrav1alpha1.GrantType(gt))
+	}
+	for _, rt := range c.ResponseTypes {
+		spec.ResponseTypes = append(spec.ResponseTypes, hydrav1alpha1.ResponseType(rt))
+	}
+	for _, uri := range c.RedirectURIs {
+		spec.RedirectURIs = append(spec.RedirectURIs, hydrav1alpha1.RedirectURI(uri))
+	}
+	for _, uri := range c.PostLogoutRedirectURIs {
+		spec.PostLogoutRedirectURIs = append(spec.PostLogoutRedirectURIs, hydrav1alpha1.RedirectURI(uri))
+	}
+	for _, origin := range c.AllowedCorsOrigins {
+		spec.AllowedCorsOrigins = append(spec.AllowedCorsOrigins, hydrav1alpha1.RedirectURI(origin))
+	}
+	spec.Metadata, spec.ConsentHints = splitMetadata(c.Metadata)
+
+	return manifest{
+		APIVersion: hydrav1alpha1.GroupVersion.String(),
+		Kind:       "OAuth2Client",
+		Metadata:   manifestMetadata{Name: name, Namespace: namespace},
+		Spec:       spec,
+	}
+}
+
+// splitMetadata separates the metadata of a client from the properties the
+// controller adds to it
+func splitMetadata(raw json.RawMessage) (json.RawMessage, *hydrav1alpha1.ConsentHints) {
+	var metadata map[string]json.RawMessage
+	if len(raw) == 0 || json.Unmarshal(raw, &metadata) != nil {
+		return raw, nil
+	}
+
+	var hints *hydrav1alpha1.ConsentHints
+	if value, ok := metadata[hydraclient.MetadataConsentHintsKey]; ok {
+		if json.Unmarshal(value, &hints) != nil {
+			hints = nil
+		}
+	}
+	delete(metadata, hydraclient.MetadataConsentHintsKey)
+	delete(metadata, hydraclient.MetadataGCExemptKey)
+	delete(metadata, hydraclient.MetadataManagedKey)
+	if len(metadata) == 0 {
+		return nil, hints
+	}
+
+	stripped, err := json.Marshal(metadata)
+	if err != nil {
+		return raw, hints
+	}
+	return stripped, hints
+}
+
+// resourceName derives a resource name from the name of a client, or from
+// its ID if the name is not usable
+func resourceName(clientName, clientID string) string {
+	for _, candidate := range []string{clientName, clientID} {
+		name := strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(candidate), "-"), "-.")
+		if len(name) > 253 {
+			name = strings.Trim(name[:253], "-.")
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return "oauth2client"
+}
+
+// marshalManifest marshals m to YAML, leaving out the empty properties of
+// the spec, e.g. an unset hydraAdmin
+func marshalManifest(m manifest) ([]byte, error) {
+	raw, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	var object map[string]interface{}
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return nil, err
+	}
+	if spec, ok := object["spec"].(map[string]interface{}); ok {
+		pruneEmpty(spec)
+	}
+	return yaml.Marshal(object)
+}
+
+// pruneEmpty removes the empty objects from object, recursively
+func pruneEmpty(object map[string]interface{}) {
+	for key, value := range object {
+		if nested, ok := value.(map[string]interface{}); ok {
+			pruneEmpty(nested)
+			if len(nested) == 0 {
+				delete(object, key)
+			}
+		}
+	}
+}
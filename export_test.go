@@ -0,0 +1,237 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const exportedHydraClients = `[
+	{
+		"client_id": "foo-id",
+		"client_name": "foo",
+		"owner": "foo/team",
+		"grant_types": ["client_credentials"],
+		"scope": "read write",
+		"metadata": {"hydra-maester.ory.sh/managed": true, "hydra-maester.ory.sh/gc-exempt": true, "tier": "gold"},
+		"created_at": "2020-01-01T00:00:00Z"
+	},
+	{
+		"client_id": "4c7e2b1a",
+		"client_name": "My App!",
+		"grant_types": ["authorization_code"],
+		"response_types": ["code"],
+		"redirect_uris": ["https://app.example.com/callback"],
+		"scope": "openid",
+		"metadata": {"hydra-maester.ory.sh/consent-hints": {"displayName": "My App"}}
+	}
+]`
+
+func TestRunExportFromHydra(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/clients", req.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(exportedHydraClients))
+	}))
+	defer server.Close()
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, runExport([]string{"--hydra-url=http://" + u.Hostname(), "--hydra-port=" + u.Port(), "--namespace=imported"}, &out))
+
+	assert.Equal(t, `---
+apiVersion: hydra.ory.sh/v1alpha1
+kind: OAuth2Client
+metadata:
+  name: my-app
+  namespace: imported
+spec:
+  clientID: 4c7e2b1a
+  clientName: My App!
+  consentHints:
+    displayName: My App
+  grantTypes:
+  - authorization_code
+  redirectUris:
+  - https://app.example.com/callback
+  responseTypes:
+  - code
+  scope: openid
+  secretName: my-app
+---
+apiVersion: hydra.ory.sh/v1alpha1
+kind: OAuth2Client
+metadata:
+  name: foo
+  namespace: team
+spec:
+  clientID: foo-id
+  clientName: foo
+  gcExempt: true
+  grantTypes:
+  - client_credentials
+  metadata:
+    tier: gold
+  scope: read write
+  secretName: foo
+`, out.String())
+}
+
+func TestRunExportErrors(t *testing.T) {
+
+	for name, tc := range map[string]struct {
+		args []string
+		err  string
+	}{
+		"missing hydra URL": {[]string{"--from=hydra"}, "hydra URL can't be empty"},
+		"unknown source":    {[]string{"--from=vault"}, "unknown source vault"},
+		"unreachable hydra": {[]string{"--hydra-url=http://127.0.0.1", "--hydra-port=1"}, "unable to list the clients of ORY Hydra"},
+	} {
+		t.Run("case="+name, func(t *testing.T) {
+			err := runExport(tc.args, &bytes.Buffer{})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
+
+func TestClusterManifests(t *testing.T) {
+
+	newClient := func(name, namespace string) *hydrav1alpha1.OAuth2Client {
+		return &hydrav1alpha1.OAuth2Client{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       namespace,
+				Labels:          map[string]string{"app": name},
+				Annotations:     map[string]string{"kubectl.kubernetes.io/last-applied-configuration": "{}", "team": "a"},
+				ResourceVersion: "42",
+				UID:             "uid",
+			},
+			Spec: hydrav1alpha1.OAuth2ClientSpec{
+				GrantTypes: []hydrav1alpha1.GrantType{"client_credentials"},
+				Scope:      "read",
+				SecretName: name + "-secret",
+			},
+			Status: hydrav1alpha1.OAuth2ClientStatus{ClientID: name + "-id", ObservedGeneration: 3},
+		}
+	}
+	reader := fake.NewFakeClientWithScheme(scheme, newClient("foo", "b"), newClient("bar", "b"), newClient("baz", "a"))
+
+	t.Run("case=all namespaces", func(t *testing.T) {
+		manifests, err := clusterManifests(reader, "")
+		require.NoError(t, err)
+
+		var out bytes.Buffer
+		require.NoError(t, writeManifests(&out, manifests))
+		var names []string
+		for _, m := range manifests {
+			names = append(names, m.Metadata.Namespace+"/"+m.Metadata.Name)
+		}
+		assert.Equal(t, []string{"a/baz", "b/bar", "b/foo"}, names)
+	})
+
+	t.Run("case=single namespace", func(t *testing.T) {
+		manifests, err := clusterManifests(reader, "b")
+		require.NoError(t, err)
+		require.Len(t, manifests, 2)
+		for _, m := range manifests {
+			assert.Equal(t, "b", m.Metadata.Namespace)
+		}
+	})
+
+	t.Run("case=status and server metadata are left out", func(t *testing.T) {
+		manifests, err := clusterManifests(reader, "a")
+		require.NoError(t, err)
+		require.Len(t, manifests, 1)
+
+		var out bytes.Buffer
+		require.NoError(t, writeManifests(&out, manifests))
+		assert.Equal(t, `---
+apiVersion: hydra.ory.sh/v1alpha1
+kind: OAuth2Client
+metadata:
+  annotations:
+    team: a
+  labels:
+    app: baz
+  name: baz
+  namespace: a
+spec:
+  grantTypes:
+  - client_credentials
+  scope: read
+  secretName: baz-secret
+`, out.String())
+	})
+}
+
+func TestResourceName(t *testing.T) {
+
+	for clientName, expected := range map[string]string{
+		"foo":           "foo",
+		"My App!":       "my-app",
+		"--foo.bar--":   "foo.bar",
+		"":              "client-id",
+		"!!!":           "client-id",
+		"Ünïcödé":       "n-c-d",
+		"a/b\\c":        "a-b-c",
+		"UPPER_lower 1": "upper-lower-1",
+	} {
+		t.Run(fmt.Sprintf("case=%q", clientName), func(t *testing.T) {
+			assert.Equal(t, expected, resourceName(clientName, "Client ID"))
+		})
+	}
+
+	assert.Equal(t, "oauth2client", resourceName("", "!!!"))
+	assert.Len(t, resourceName(string(bytes.Repeat([]byte("a"), 300)), ""), 253)
+}
+
+func TestSplitMetadata(t *testing.T) {
+
+	for name, tc := range map[string]struct {
+		raw      string
+		metadata string
+		hints    *hydrav1alpha1.ConsentHints
+	}{
+		"empty":                {``, ``, nil},
+		"user metadata":        {`{"tier":"gold"}`, `{"tier":"gold"}`, nil},
+		"controller metadata":  {`{"hydra-maester.ory.sh/managed":true,"hydra-maester.ory.sh/gc-exempt":true}`, ``, nil},
+		"mixed metadata":       {`{"hydra-maester.ory.sh/managed":true,"tier":"gold"}`, `{"tier":"gold"}`, nil},
+		"consent hints":        {`{"hydra-maester.ory.sh/consent-hints":{"displayName":"Foo"}}`, ``, &hydrav1alpha1.ConsentHints{DisplayName: "Foo"}},
+		"invalid consent hint": {`{"hydra-maester.ory.sh/consent-hints":"foo"}`, ``, nil},
+		"not an object":        {`["foo"]`, `["foo"]`, nil},
+	} {
+		t.Run("case="+name, func(t *testing.T) {
+			metadata, hints := splitMetadata(json.RawMessage(tc.raw))
+			assert.Equal(t, tc.metadata, string(metadata))
+			assert.Equal(t, tc.hints, hints)
+		})
+	}
+}
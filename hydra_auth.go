@@ -0,0 +1,190 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	"github.com/ory/hydra-maester/controllers"
+	"github.com/ory/hydra-maester/pkg/hydraclient"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+// hydraAuthKeys are the keys of the auth Secret NewHydraAuthCredentials
+// reads
+var hydraAuthKeys = []string{apiv1.ServiceAccountTokenKey, apiv1.BasicAuthUsernameKey, apiv1.BasicAuthPasswordKey, controllers.ClientIDKey, controllers.ClientSecretKey}
+
+// hydraAuthCheckInterval is how often the files of the auth Secret are
+// checked for changes. The kubelet takes longer to update mounted Secrets.
+const hydraAuthCheckInterval = 10 * time.Second
+
+// hydraAuth provides the credentials for ORY Hydra from an auth Secret
+// mounted in dir. They are read again whenever the files change, so that
+// they can be rotated without restarting the controller.
+type hydraAuth struct {
+	dir  string
+	auth hydrav1alpha1.HydraInstanceAuth
+	// checkInterval is how often the files are checked for changes
+	checkInterval time.Duration
+
+	mu          sync.Mutex
+	checked     time.Time
+	stamp       string
+	credentials *controllers.HydraAuthCredentials
+}
+
+// newHydraAuth returns the credentials mounted in dir, failing if they
+// can't be read yet
+func newHydraAuth(dir string, auth hydrav1alpha1.HydraInstanceAuth) (*hydraAuth, error) {
+	a := &hydraAuth{dir: dir, auth: auth, checkInterval: hydraAuthCheckInterval}
+	if _, err := a.load(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// load returns the current credentials. The files are checked for changes
+// at most once per checkInterval, and the previous credentials are kept
+// while the new ones can't be read, e.g. while the kubelet updates the
+// Secret.
+func (a *hydraAuth) load() (*controllers.HydraAuthCredentials, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.credentials != nil && time.Since(a.checked) < a.checkInterval {
+		return a.credentials, nil
+	}
+	a.checked = time.Now()
+
+	stamp, err := hydraAuthStamp(a.dir)
+	if err != nil {
+		return a.previous(err)
+	}
+	if a.credentials != nil && stamp == a.stamp {
+		return a.credentials, nil
+	}
+
+	data, err := readHydraAuth(a.dir)
+	if err != nil {
+		return a.previous(err)
+	}
+	credentials, err := controllers.NewHydraAuthCredentials(data, a.auth)
+	if err != nil {
+		return a.previous(err)
+	}
+	a.credentials, a.stamp = credentials, stamp
+	return a.credentials, nil
+}
+
+func (a *hydraAuth) previous(err error) (*controllers.HydraAuthCredentials, error) {
+	if a.credentials == nil {
+		return nil, err
+	}
+	return a.credentials, nil
+}
+
+// Option authenticates the requests of a client with the current
+// credentials. It wraps the transport of the HTTP client, so it is given
+// after WithHTTPClient.
+func (a *hydraAuth) Option() hydraclient.Option {
+	return func(c *hydraclient.Client) {
+		var httpClient http.Client
+		if c.HTTPClient != nil {
+			httpClient = *c.HTTPClient
+		}
+		httpClient.Transport = &hydraAuthTransport{auth: a, base: httpClient.Transport}
+		c.HTTPClient = &httpClient
+	}
+}
+
+// hydraAuthTransport authenticates requests with the credentials of auth
+// before sending them with base
+type hydraAuthTransport struct {
+	auth *hydraAuth
+	base http.RoundTripper
+}
+
+func (t *hydraAuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	credentials, err := t.auth.load()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the credentials for ORY Hydra: %w", err)
+	}
+
+	req = req.Clone(req.Context())
+	if credentials.TokenSource != nil {
+		token, err := credentials.TokenSource.Token()
+		if err != nil {
+			return nil, fmt.Errorf("unable to obtain an access token for ORY Hydra: %w", err)
+		}
+		token.SetAuthHeader(req)
+	} else {
+		header := credentials.Header
+		if header == "" {
+			header = "Authorization"
+		}
+		req.Header.Set(header, credentials.Authorization)
+	}
+
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
+}
+
+// hydraAuthStamp returns the modification times and sizes of the files of
+// the auth Secret mounted in dir, which change whenever the kubelet updates
+// the Secret
+func hydraAuthStamp(dir string) (string, error) {
+	var stamp strings.Builder
+	for _, key := range hydraAuthKeys {
+		info, err := os.Stat(filepath.Join(dir, key))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&stamp, "%s:%d:%d;", key, info.ModTime().UnixNano(), info.Size())
+	}
+	return stamp.String(), nil
+}
+
+// readHydraAuth reads the credentials for ORY Hydra from dir, where an auth
+// Secret is mounted, as NewHydraAuthCredentials expects them
+func readHydraAuth(dir string) (map[string][]byte, error) {
+	data := map[string][]byte{}
+	for _, key := range hydraAuthKeys {
+		value, err := ioutil.ReadFile(filepath.Join(dir, key))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		data[key] = bytes.TrimSpace(value)
+	}
+	return data, nil
+}
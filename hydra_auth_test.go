@@ -0,0 +1,104 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	"github.com/ory/hydra-maester/pkg/hydraclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHydraAuthReload(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "hydra-auth")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	writeFile := func(key, value string) {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, key), []byte(value), 0600))
+	}
+
+	var authorization, apiKey string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		authorization = req.Header.Get("Authorization")
+		apiKey = req.Header.Get("X-API-Key")
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+	u, err := url.Parse(server.URL + "/clients")
+	require.NoError(t, err)
+
+	t.Run("case=missing credentials", func(t *testing.T) {
+		_, err := newHydraAuth(dir, hydrav1alpha1.HydraInstanceAuth{})
+		assert.Error(t, err)
+	})
+
+	writeFile("token", "first\n")
+	auth, err := newHydraAuth(dir, hydrav1alpha1.HydraInstanceAuth{})
+	require.NoError(t, err)
+	c := hydraclient.New(*u, hydraclient.WithHTTPClient(&http.Client{}), auth.Option())
+
+	throttled, err := newHydraAuth(dir, hydrav1alpha1.HydraInstanceAuth{})
+	require.NoError(t, err)
+	throttledClient := hydraclient.New(*u, throttled.Option())
+	// the files are checked on every request
+	auth.checkInterval = 0
+
+	t.Run("case=initial credentials", func(t *testing.T) {
+		_, _, err := c.GetOAuth2Client("foo")
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer first", authorization)
+	})
+
+	t.Run("case=rotated credentials", func(t *testing.T) {
+		writeFile("token", "second-token\n")
+		_, _, err := c.GetOAuth2Client("foo")
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer second-token", authorization)
+	})
+
+	t.Run("case=rotated credentials within the check interval", func(t *testing.T) {
+		_, _, err := throttledClient.GetOAuth2Client("foo")
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer first", authorization)
+	})
+
+	t.Run("case=unreadable credentials keep the previous ones", func(t *testing.T) {
+		require.NoError(t, os.Remove(filepath.Join(dir, "token")))
+		_, _, err := c.GetOAuth2Client("foo")
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer second-token", authorization)
+	})
+
+	t.Run("case=api key header", func(t *testing.T) {
+		writeFile("token", "key")
+		auth, err := newHydraAuth(dir, hydrav1alpha1.HydraInstanceAuth{Header: "X-API-Key"})
+		require.NoError(t, err)
+		c := hydraclient.New(*u, auth.Option())
+		_, _, err = c.GetOAuth2Client("foo")
+		require.NoError(t, err)
+		assert.Equal(t, "key", apiKey)
+		assert.Empty(t, authorization)
+	})
+}
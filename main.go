@@ -16,22 +16,32 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 	"os"
+	"strings"
+	"text/template"
 	"time"
 
-	"github.com/ory/hydra-maester/hydra"
+	"github.com/ory/hydra-maester/pkg/hydraclient"
+	"github.com/ory/hydra-maester/pkg/tracing"
+	"github.com/ory/hydra-maester/pkg/vault"
 
 	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	hydrav1beta1 "github.com/ory/hydra-maester/api/v1beta1"
 	"github.com/ory/hydra-maester/controllers"
+	batchv1 "k8s.io/api/batch/v1"
 	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -43,46 +53,167 @@ var (
 func init() {
 
 	apiv1.AddToScheme(scheme)
+	batchv1.AddToScheme(scheme)
 	hydrav1alpha1.AddToScheme(scheme)
+	hydrav1beta1.AddToScheme(scheme)
 	// +kubebuilder:scaffold:scheme
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		if err := runExport(os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	var (
-		metricsAddr, hydraURL, endpoint, forwardedProto, syncPeriod string
-		hydraPort                                                   int
-		enableLeaderElection                                        bool
+		metricsAddr, hydraURL, hydraPublicURL, endpoint, forwardedProto, syncPeriod, driftDetectionInterval, gcInterval, redirectURIAllowPattern              string
+		tlsCertFile, tlsKeyFile, tlsCAFile, hydraSPIFFEID, reconcileTimeout, verificationImage, labelSelector, hydraAPIVersion, hydraAuthDir, hydraAuthHeader string
+		watchNamespaces, excludeNamespaces, secretClientIDKey, secretClientSecretKey, hydraAuthTokenURL, hydraAuthScopes                                      string
+		vaultAddress, vaultMount, vaultPathTemplate, vaultTokenFile, vaultKubernetesRole, vaultKubernetesMount, hydraHeaders                                  string
+		clientSecretCharset, leaderElectionID, leaderElectionNamespace, healthProbeAddr, hydraStartupTimeout, tracingExporter, configFile, hydraCacheTTL      string
+		hydraPort, clientSecretLength, maxConcurrentReconciles                                                                                                int
+		clientSecretMinEntropy, tracingSamplingRatio                                                                                                          float64
+		enableLeaderElection, enableWebhooks, strictFields, audit, vaultOnly, generateClientSecrets, syncOnStartup, tlsInsecureSkipVerify                     bool
 	)
 
+	flag.StringVar(&configFile, configFlag, "", "If set, a YAML file setting the flags of the controller, by their names. Flags set on the command line or by environment variables override it")
 	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
+	flag.StringVar(&healthProbeAddr, "health-probe-addr", ":8081", "The address the liveness (/healthz) and readiness (/readyz) probes bind to, empty to disable them. The controller is only ready while ORY Hydra is")
 	flag.StringVar(&hydraURL, "hydra-url", "", "The address of ORY Hydra")
 	flag.IntVar(&hydraPort, "hydra-port", 4445, "Port ORY Hydra is listening on")
-	flag.StringVar(&endpoint, "endpoint", "/clients", "ORY Hydra's client endpoint")
+	flag.StringVar(&hydraPublicURL, "hydra-public-url", "", "ORY Hydra's public address, used to report device flow endpoints in the status of OAuth2 clients")
+	flag.StringVar(&endpoint, "endpoint", "", "ORY Hydra's client endpoint, /clients for ORY Hydra 1.x and /admin/clients for 2.x. If not set, it follows --hydra-api-version")
+	flag.StringVar(&hydraAPIVersion, "hydra-api-version", "v1", "The version of ORY Hydra's admin API, v1 or v2, or auto to detect it from the version ORY Hydra reports at startup. It sets the client endpoint unless --endpoint is set")
+	flag.StringVar(&hydraHeaders, "hydra-headers", "", "A comma-separated list of headers sent with every request to ORY Hydra, as name=value, e.g. X-Tenant-ID=acme for an API gateway ORY Hydra is multiplexed behind")
 	flag.StringVar(&forwardedProto, "forwarded-proto", "", "If set, this adds the value as the X-Forwarded-Proto header in requests to the ORY Hydra admin server")
 	flag.StringVar(&syncPeriod, "sync-period", "10h", "Determines the minimum frequency at which watched resources are reconciled")
+	flag.StringVar(&driftDetectionInterval, "drift-detection-interval", "0", "If not 0, the interval at which registered clients are compared with ORY Hydra and restored if they were modified or deleted outside of the controller")
+	flag.IntVar(&maxConcurrentReconciles, "max-concurrent-reconciles", 1, "The number of OAuth2 clients reconciled in parallel")
+	flag.StringVar(&reconcileTimeout, "reconcile-timeout", "0", "If not 0, the time budget of a reconciliation, shared by all its requests to ORY Hydra and Kubernetes. Reconciliations running out of time are requeued")
+	flag.StringVar(&hydraStartupTimeout, "hydra-startup-timeout", "5m", "How long to wait at startup for ORY Hydra to become ready before reconciling anyway; 0 disables the wait")
+	flag.StringVar(&tracingExporter, "tracing-exporter", "", "If set, reconciliations and requests to ORY Hydra are traced with OpenTelemetry and exported with \"otlp\", configured with the OTEL_EXPORTER_OTLP_* environment variables, or \"stdout\"")
+	flag.Float64Var(&tracingSamplingRatio, "tracing-sampling-ratio", 1, "The ratio of reconciliations traced, between 0 and 1")
+	flag.StringVar(&hydraCacheTTL, "hydra-cache-ttl", "0", "If not 0, how long clients fetched from ORY Hydra are cached, so that frequent reconciliations of unchanged OAuth2 clients don't request them over and over. Clients modified outside of the controller may go unnoticed for that long")
+	flag.BoolVar(&syncOnStartup, "sync-on-startup", false, "Compare all OAuth2 clients with ORY Hydra when the controller starts, and delete the clients created by the controller whose OAuth2Client was deleted while it was down")
+	flag.StringVar(&gcInterval, "gc-interval", "0", "If not 0, the interval at which clients created by the controller are deleted from ORY Hydra if their OAuth2Client no longer exists")
+	flag.StringVar(&redirectURIAllowPattern, "redirect-uri-allow-pattern", "", "If set, a regular expression all redirect URIs of OAuth2 clients must match. Namespaces can override it with the "+controllers.RedirectURIAllowPatternAnnotation+" annotation")
+	flag.StringVar(&tlsCertFile, "hydra-tls-cert-file", "", "If set, the client certificate used for mutual TLS with ORY Hydra, e.g. an X.509-SVID written by the SPIFFE helper. It is reloaded whenever it changes")
+	flag.StringVar(&tlsKeyFile, "hydra-tls-key-file", "", "The private key of the client certificate used for mutual TLS with ORY Hydra")
+	flag.StringVar(&tlsCAFile, "hydra-tls-ca-file", "", "The bundle of CA certificates trusted for ORY Hydra's certificate")
+	flag.StringVar(&hydraAuthDir, "hydra-auth-secret-dir", "", "If set, the directory an auth Secret is mounted in, holding either a token under token or a user name and password under username and password, sent to ORY Hydra as bearer token or with basic authentication, e.g. when it is behind an authenticating proxy")
+	flag.StringVar(&hydraAuthHeader, "hydra-auth-header", "", "If set, the header the token of --hydra-auth-secret-dir is sent in as it is, e.g. X-API-Key, instead of as a bearer token")
+	flag.StringVar(&hydraAuthTokenURL, "hydra-auth-token-url", "", "If set, the token endpoint access tokens are obtained from with the client credentials grant and sent to ORY Hydra as bearer tokens, e.g. when it is behind an OAuth2 aware gateway. The client ID and secret are read from client_id and client_secret in --hydra-auth-secret-dir")
+	flag.StringVar(&hydraAuthScopes, "hydra-auth-scopes", "", "A comma-separated list of the scopes requested with the client credentials grant of --hydra-auth-token-url")
+	flag.BoolVar(&tlsInsecureSkipVerify, "hydra-tls-insecure-skip-verify", false, "Accept any certificate presented by ORY Hydra, without verifying it. This is insecure and only meant for tests")
+	flag.StringVar(&hydraSPIFFEID, "hydra-spiffe-id", "", "If set, the SPIFFE ID ORY Hydra's certificate must carry, verified instead of its host name")
+	flag.StringVar(&verificationImage, "verification-image", "", "If set, the image of the Jobs spawned to verify that a token can be obtained with the credentials of OAuth2 clients using the client credentials grant. It must provide sh and curl, and requires --hydra-public-url")
+	flag.StringVar(&labelSelector, "label-selector", "", "If set, only OAuth2 clients matching this label selector are reconciled, e.g. team=payments, so that several deployments of the controller can share them out")
+	flag.StringVar(&watchNamespaces, "watch-namespaces", "", "If set, a comma-separated list of the only namespaces whose OAuth2 clients are reconciled")
+	flag.StringVar(&excludeNamespaces, "exclude-namespaces", "", "A comma-separated list of namespaces whose OAuth2 clients are never reconciled, even if they are in --watch-namespaces")
+	flag.StringVar(&secretClientIDKey, "secret-client-id-key", controllers.ClientIDKey, "The key holding the client ID in the Secrets of OAuth2 clients without a secretProjection, e.g. CLIENT_ID to consume them with envFrom")
+	flag.StringVar(&secretClientSecretKey, "secret-client-secret-key", controllers.ClientSecretKey, "The key holding the client secret in the Secrets of OAuth2 clients without a secretProjection, e.g. CLIENT_SECRET")
+	flag.StringVar(&vaultAddress, "vault-address", "", "If set, the address of the HashiCorp Vault server the credentials of OAuth2 clients are written to, in a KV version 2 secrets engine")
+	flag.StringVar(&vaultMount, "vault-mount", "secret", "The mount path of the KV version 2 secrets engine of HashiCorp Vault")
+	flag.StringVar(&vaultPathTemplate, "vault-path-template", controllers.DefaultCredentialStorePath, "The template of the paths the credentials of OAuth2 clients are written to in HashiCorp Vault, rendered with the OAuth2Client, e.g. its .Namespace and .Name")
+	flag.StringVar(&vaultTokenFile, "vault-token-file", "", "The file holding the token to authenticate to HashiCorp Vault with, instead of the Kubernetes auth method")
+	flag.StringVar(&vaultKubernetesRole, "vault-kubernetes-role", "hydra-maester", "The role to log in to HashiCorp Vault as with the Kubernetes auth method")
+	flag.StringVar(&vaultKubernetesMount, "vault-kubernetes-mount", "kubernetes", "The mount path of the Kubernetes auth method of HashiCorp Vault")
+	flag.BoolVar(&vaultOnly, "vault-only", false, "Write the credentials of OAuth2 clients to HashiCorp Vault only, without creating Secrets for them")
+	flag.BoolVar(&generateClientSecrets, "generate-client-secrets", false, "Generate the client secrets of new OAuth2 clients in the controller, following --client-secret-length and --client-secret-charset, instead of leaving it to ORY Hydra")
+	flag.IntVar(&clientSecretLength, "client-secret-length", 43, "The number of characters of the client secrets generated by the controller, on rotations and with --generate-client-secrets")
+	flag.StringVar(&clientSecretCharset, "client-secret-charset", "base64url", "The characters the client secrets generated by the controller are drawn from, either alphanumeric, base64url, hex, printable or the characters themselves")
+	flag.Float64Var(&clientSecretMinEntropy, "client-secret-min-entropy", 128, "The minimum entropy, in bits, of the client secrets generated by the controller. The controller refuses to start if --client-secret-length and --client-secret-charset don't reach it")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", false, "Serve the conversion webhook between the versions of the OAuth2Client API and the webhook protecting OAuth2Clients from deletion. It requires a certificate in /tmp/k8s-webhook-server/serving-certs")
+	flag.BoolVar(&strictFields, "strict-fields", false, "Fail the reconciliation of OAuth2 clients whose last applied manifest has spec fields unknown to the controller, e.g. because the CRDs were upgraded before the controller")
+	flag.BoolVar(&audit, "audit", false, "Only compare OAuth2 clients with ORY Hydra and report their drift through conditions, events and metrics, without registering, updating or deleting clients in ORY Hydra")
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&leaderElectionID, "leader-election-id", "hydra-maester-leader-election", "The name of the ConfigMap holding the leader lock, which must differ between deployments of the controller sharing a namespace")
+	flag.StringVar(&leaderElectionNamespace, "leader-election-namespace", "", "The namespace of the ConfigMap holding the leader lock, the namespace of the controller by default. It is required when running outside of a cluster")
 	flag.Parse()
 
 	ctrl.SetLogger(zap.Logger(true))
 
+	if err := applyEnv(flag.CommandLine, os.LookupEnv); err != nil {
+		setupLog.Error(err, "unable to load the configuration")
+		os.Exit(1)
+	}
+	if configFile != "" {
+		if err := applyConfigFile(flag.CommandLine, configFile); err != nil {
+			setupLog.Error(err, "unable to load the configuration")
+			os.Exit(1)
+		}
+	}
+
 	syncPeriodParsed, err := time.ParseDuration(syncPeriod)
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
+	driftDetectionIntervalParsed, err := time.ParseDuration(driftDetectionInterval)
+	if err != nil {
+		setupLog.Error(err, "invalid drift detection interval")
+		os.Exit(1)
+	}
+
+	reconcileTimeoutParsed, err := time.ParseDuration(reconcileTimeout)
+	if err != nil {
+		setupLog.Error(err, "invalid reconcile timeout")
+		os.Exit(1)
+	}
+
+	hydraCacheTTLParsed, err := time.ParseDuration(hydraCacheTTL)
+	if err != nil {
+		setupLog.Error(err, "invalid ORY Hydra cache TTL")
+		os.Exit(1)
+	}
+
+	hydraStartupTimeoutParsed, err := time.ParseDuration(hydraStartupTimeout)
+	if err != nil {
+		setupLog.Error(err, "invalid ORY Hydra startup timeout")
+		os.Exit(1)
+	}
+
+	if hydraAPIVersion != "auto" && hydraAPIVersion != "v1" && hydraAPIVersion != "v2" {
+		setupLog.Error(fmt.Errorf("expected v1, v2 or auto, got %q", hydraAPIVersion), "invalid ORY Hydra API version")
+		os.Exit(1)
+	}
+
+	gcIntervalParsed, err := time.ParseDuration(gcInterval)
+	if err != nil {
+		setupLog.Error(err, "invalid garbage collection interval")
+		os.Exit(1)
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
-		Scheme:             scheme,
-		MetricsBindAddress: metricsAddr,
-		LeaderElection:     enableLeaderElection,
-		SyncPeriod:         &syncPeriodParsed,
+		Scheme:                  scheme,
+		MetricsBindAddress:      metricsAddr,
+		LeaderElection:          enableLeaderElection,
+		LeaderElectionID:        leaderElectionID,
+		LeaderElectionNamespace: leaderElectionNamespace,
+		SyncPeriod:              &syncPeriodParsed,
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
+	allowPattern, err := controllers.CompileRedirectURIAllowPattern(redirectURIAllowPattern)
+	if err != nil {
+		setupLog.Error(err, "invalid redirect URI allow pattern")
+		os.Exit(1)
+	}
+
+	labelSelectorParsed, err := labels.Parse(labelSelector)
+	if err != nil {
+		setupLog.Error(err, "invalid label selector")
+		os.Exit(1)
+	}
+
 	if hydraURL == "" {
 		setupLog.Error(fmt.Errorf("hydra URL can't be empty"), "unable to create controller", "controller", "OAuth2Client")
 		os.Exit(1)
@@ -96,7 +227,94 @@ func main() {
 			ForwardedProto: forwardedProto,
 		},
 	}
-	hydraClientMaker := getHydraClientMaker(defaultSpec)
+	var rotatingCertificate *hydraclient.RotatingCertificate
+	if tlsCertFile != "" || tlsKeyFile != "" || tlsCAFile != "" || tlsInsecureSkipVerify {
+		rotatingCertificate = &hydraclient.RotatingCertificate{
+			CertFile:           tlsCertFile,
+			KeyFile:            tlsKeyFile,
+			CAFile:             tlsCAFile,
+			SPIFFEID:           hydraSPIFFEID,
+			InsecureSkipVerify: tlsInsecureSkipVerify,
+		}
+		if tlsInsecureSkipVerify {
+			setupLog.Info("the certificate of ORY Hydra is not verified, this is insecure and only meant for tests")
+		}
+	}
+
+	headers, err := parseHeaders(hydraHeaders)
+	if err != nil {
+		setupLog.Error(err, "invalid ORY Hydra headers")
+		os.Exit(1)
+	}
+	hydraDefaultOptions := []hydraclient.Option{hydraclient.WithHeaders(headers)}
+	if hydraAuthDir != "" {
+		auth, err := newHydraAuth(hydraAuthDir, hydrav1alpha1.HydraInstanceAuth{
+			Header:   hydraAuthHeader,
+			TokenURL: hydraAuthTokenURL,
+			Scopes:   splitList(hydraAuthScopes),
+		})
+		if err != nil {
+			setupLog.Error(err, "unable to read the credentials for ORY Hydra")
+			os.Exit(1)
+		}
+		hydraDefaultOptions = append(hydraDefaultOptions, auth.Option())
+	} else if hydraAuthHeader != "" || hydraAuthTokenURL != "" {
+		setupLog.Error(fmt.Errorf("--hydra-auth-header and --hydra-auth-token-url require --hydra-auth-secret-dir"), "unable to create controller", "controller", "OAuth2Client")
+		os.Exit(1)
+	}
+
+	stop := ctrl.SetupSignalHandler()
+
+	// ORY Hydra's health and version endpoints don't depend on the client
+	// endpoint, which may only be known once ORY Hydra is ready
+	startupSpec := defaultSpec
+	if startupSpec.HydraAdmin.Endpoint == "" {
+		startupSpec.HydraAdmin.Endpoint = hydraclient.ClientsEndpointV1
+	}
+	startupClient, err := getHydraClientMaker(startupSpec, rotatingCertificate, 0, hydraDefaultOptions...)(startupSpec)
+	if err != nil {
+		setupLog.Error(err, "making default hydra client", "controller", "OAuth2Client")
+		os.Exit(1)
+	}
+	healthClient, _ := startupClient.(controllers.HydraHealthClient)
+
+	// the probes are served before the manager starts, so the controller
+	// isn't restarted by its liveness probe while it waits for ORY Hydra
+	if healthProbeAddr != "" {
+		probes := &controllers.HealthProbes{
+			Addr:  healthProbeAddr,
+			Hydra: healthClient,
+			Log:   ctrl.Log.WithName("controllers").WithName("HealthProbes"),
+		}
+		go func() {
+			if err := probes.Start(stop); err != nil {
+				setupLog.Error(err, "unable to serve health probes")
+				os.Exit(1)
+			}
+		}()
+	}
+
+	if healthClient != nil && hydraStartupTimeoutParsed > 0 {
+		err := controllers.WaitForHydra(stop, healthClient, hydraStartupTimeoutParsed, ctrl.Log.WithName("controllers").WithName("HydraStartup"))
+		if err != nil {
+			setupLog.Error(err, "starting without ORY Hydra, reconciliations are retried until it is ready")
+		}
+	}
+
+	if endpoint == "" {
+		endpoint, err = hydraClientsEndpoint(stop, hydraAPIVersion, startupClient, hydraStartupTimeoutParsed)
+		if err != nil {
+			setupLog.Error(err, "unable to detect ORY Hydra's client endpoint, set --hydra-api-version or --endpoint")
+			os.Exit(1)
+		}
+		if endpoint == "" {
+			// stopped while detecting it
+			return
+		}
+		defaultSpec.HydraAdmin.Endpoint = endpoint
+	}
+
+	hydraClientMaker := getHydraClientMaker(defaultSpec, rotatingCertificate, hydraCacheTTLParsed, hydraDefaultOptions...)
 	hydraClient, err := hydraClientMaker(defaultSpec)
 	if err != nil {
 		setupLog.Error(err, "making default hydra client", "controller", "OAuth2Client")
@@ -104,28 +322,211 @@ func main() {
 
 	}
 
-	err = (&controllers.OAuth2ClientReconciler{
-		Client:           mgr.GetClient(),
-		Log:              ctrl.Log.WithName("controllers").WithName("OAuth2Client"),
-		HydraClient:      hydraClient,
-		HydraClientMaker: hydraClientMaker,
-	}).SetupWithManager(mgr)
+	var credentialStore controllers.CredentialStore
+	if vaultAddress != "" {
+		vaultClient, err := newVaultClient(vaultAddress, vaultMount, vaultTokenFile, vaultKubernetesRole, vaultKubernetesMount)
+		if err != nil {
+			setupLog.Error(err, "unable to create the HashiCorp Vault client")
+			os.Exit(1)
+		}
+		credentialStore = vaultClient
+	} else if vaultOnly {
+		setupLog.Error(fmt.Errorf("--vault-only requires --vault-address"), "unable to create controller", "controller", "OAuth2Client")
+		os.Exit(1)
+	}
+	credentialStorePath, err := template.New("path").Parse(vaultPathTemplate)
+	if err != nil {
+		setupLog.Error(err, "invalid HashiCorp Vault path template")
+		os.Exit(1)
+	}
+
+	clientSecretPolicy, err := controllers.NewClientSecretPolicy(clientSecretLength, clientSecretCharset, clientSecretMinEntropy)
+	if err != nil {
+		setupLog.Error(err, "invalid client secret policy")
+		os.Exit(1)
+	}
+
+	_, err = controllers.New(mgr,
+		controllers.WithHydraClient(hydraClient),
+		controllers.WithHydraClientMaker(hydraClientMaker),
+		controllers.WithHydraPublicURL(hydraPublicURL),
+		controllers.WithRedirectURIAllowPattern(allowPattern),
+		controllers.WithDriftDetectionInterval(driftDetectionIntervalParsed),
+		controllers.WithReconcileTimeout(reconcileTimeoutParsed),
+		controllers.WithMaxConcurrentReconciles(maxConcurrentReconciles),
+		controllers.WithStrictFields(strictFields),
+		controllers.WithVerificationImage(verificationImage),
+		controllers.WithAuditMode(audit),
+		controllers.WithLabelSelector(labelSelectorParsed),
+		controllers.WithWatchNamespaces(splitList(watchNamespaces)),
+		controllers.WithExcludeNamespaces(splitList(excludeNamespaces)),
+		controllers.WithSecretKeys(secretClientIDKey, secretClientSecretKey),
+		controllers.WithCredentialStore(credentialStore, credentialStorePath, vaultOnly),
+		controllers.WithClientSecretPolicy(clientSecretPolicy, generateClientSecrets),
+	)
 	if err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "OAuth2Client")
 		os.Exit(1)
 	}
+	err = (&controllers.OAuth2ClientSummaryReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("OAuth2ClientSummary"),
+	}).SetupWithManager(mgr)
+	if err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OAuth2ClientSummary")
+		os.Exit(1)
+	}
+	err = (&controllers.HydraMaesterConfigurationReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("HydraMaesterConfiguration"),
+	}).SetupWithManager(mgr)
+	if err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "HydraMaesterConfiguration")
+		os.Exit(1)
+	}
+	err = (&controllers.OAuth2ClientPolicyReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("OAuth2ClientPolicy"),
+	}).SetupWithManager(mgr)
+	if err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OAuth2ClientPolicy")
+		os.Exit(1)
+	}
+	// the key sets and trust relationships are written to ORY Hydra, so
+	// their controllers are left out in audit mode
+	if keysClient, ok := hydraClient.(controllers.HydraKeysClient); ok && !audit {
+		err = (&controllers.JsonWebKeySetReconciler{
+			Client:      mgr.GetClient(),
+			HydraClient: keysClient,
+			Recorder:    mgr.GetEventRecorderFor("hydra-maester"),
+			Log:         ctrl.Log.WithName("controllers").WithName("JsonWebKeySet"),
+		}).SetupWithManager(mgr)
+		if err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "JsonWebKeySet")
+			os.Exit(1)
+		}
+	}
+	if trustClient, ok := hydraClient.(controllers.HydraTrustClient); ok && !audit {
+		err = (&controllers.TrustedJwtGrantIssuerReconciler{
+			Client:      mgr.GetClient(),
+			HydraClient: trustClient,
+			Recorder:    mgr.GetEventRecorderFor("hydra-maester"),
+			Log:         ctrl.Log.WithName("controllers").WithName("TrustedJwtGrantIssuer"),
+		}).SetupWithManager(mgr)
+		if err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "TrustedJwtGrantIssuer")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
+	if enableWebhooks {
+		// the webhook server serves the conversion webhook on /convert
+		mgr.GetWebhookServer().Register(controllers.DeletionProtectionPath, &admission.Webhook{Handler: &controllers.DeletionProtector{}})
+	}
+
+	if versionClient, ok := hydraClient.(controllers.HydraVersionClient); ok {
+		err = mgr.Add(&controllers.HydraVersionProbe{
+			Client: versionClient,
+			Log:    ctrl.Log.WithName("controllers").WithName("HydraVersionProbe"),
+		})
+		if err != nil {
+			setupLog.Error(err, "unable to add ORY Hydra version probe")
+			os.Exit(1)
+		}
+	}
+
+	err = mgr.Add(&controllers.SecretMigrator{
+		Reader:   mgr.GetAPIReader(),
+		Client:   mgr.GetClient(),
+		Recorder: mgr.GetEventRecorderFor("hydra-maester"),
+		Log:      ctrl.Log.WithName("controllers").WithName("SecretMigrator"),
+	})
+	if err != nil {
+		setupLog.Error(err, "unable to add secret migrator")
+		os.Exit(1)
+	}
+
+	if syncOnStartup {
+		err = mgr.Add(&controllers.StartupSync{
+			Reader:      mgr.GetAPIReader(),
+			HydraClient: hydraClient,
+			AuditMode:   audit,
+			Log:         ctrl.Log.WithName("controllers").WithName("StartupSync"),
+		})
+		if err != nil {
+			setupLog.Error(err, "unable to add startup sync")
+			os.Exit(1)
+		}
+	}
+
+	// the garbage collector runs even if it is disabled by the flags, as the
+	// HydraMaesterConfiguration can enable it, except in audit mode
+	if !audit {
+		err = mgr.Add(&controllers.GarbageCollector{
+			Reader:      mgr.GetAPIReader(),
+			HydraClient: hydraClient,
+			Interval:    gcIntervalParsed,
+			Log:         ctrl.Log.WithName("controllers").WithName("GarbageCollector"),
+		})
+		if err != nil {
+			setupLog.Error(err, "unable to add garbage collector")
+			os.Exit(1)
+		}
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracingExporter, tracingSamplingRatio)
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	if err := mgr.Start(stop); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdownTracing(ctx); err != nil {
+		setupLog.Error(err, "unable to export the remaining spans")
+	}
+}
+
+// hydraClientsEndpoint returns the client endpoint of the default ORY Hydra
+// instance for the given version of its admin API. With auto, the version
+// is detected with hydraClient, retrying for at most timeout while ORY Hydra
+// doesn't report it. The endpoint is empty if stop is closed meanwhile.
+func hydraClientsEndpoint(stop <-chan struct{}, apiVersion string, hydraClient controllers.ClientRegistrar, timeout time.Duration) (string, error) {
+	switch apiVersion {
+	case "v1":
+		return hydraclient.ClientsEndpointV1, nil
+	case "v2":
+		return hydraclient.ClientsEndpointV2, nil
+	}
+
+	versionClient, ok := hydraClient.(controllers.HydraVersionClient)
+	if !ok {
+		return hydraclient.ClientsEndpointV1, nil
+	}
+	endpoint, err := controllers.DetectHydraClientsEndpoint(stop, versionClient, timeout, ctrl.Log.WithName("controllers").WithName("HydraVersionProbe"))
+	if err != nil {
+		return "", err
+	}
+	if endpoint != "" {
+		setupLog.Info("detected ORY Hydra's client endpoint", "endpoint", endpoint)
+	}
+	return endpoint, nil
 }
 
-func getHydraClientMaker(defaultSpec hydrav1alpha1.OAuth2ClientSpec) controllers.HydraClientMakerFunc {
+// getHydraClientMaker returns the maker of the clients of ORY Hydra, which
+// fall back to defaultSpec. The default options, e.g. the credentials and
+// headers, only apply to the clients of the default ORY Hydra instance, so
+// that they are never sent to the URLs set in OAuth2Clients.
+func getHydraClientMaker(defaultSpec hydrav1alpha1.OAuth2ClientSpec, rotatingCertificate *hydraclient.RotatingCertificate, cacheTTL time.Duration, defaultOptions ...hydraclient.Option) controllers.HydraClientMakerFunc {
 
-	return controllers.HydraClientMakerFunc(func(spec hydrav1alpha1.OAuth2ClientSpec) (controllers.HydraClientInterface, error) {
+	return controllers.HydraClientMakerFunc(func(spec hydrav1alpha1.OAuth2ClientSpec) (controllers.ClientRegistrar, error) {
 
 		if spec.HydraAdmin.URL == "" {
 			spec.HydraAdmin.URL = defaultSpec.HydraAdmin.URL
@@ -146,16 +547,76 @@ func getHydraClientMaker(defaultSpec hydrav1alpha1.OAuth2ClientSpec) controllers
 			return nil, fmt.Errorf("unable to parse ORY Hydra's URL: %w", err)
 		}
 
-		client := &hydra.Client{
-			HydraURL:   *u.ResolveReference(&url.URL{Path: spec.HydraAdmin.Endpoint}),
-			HTTPClient: &http.Client{},
+		client := hydraclient.New(*u.ResolveReference(&url.URL{Path: spec.HydraAdmin.Endpoint}),
+			hydraclient.WithHTTPClient(&http.Client{Transport: controllers.InstrumentHydraTransport(nil)}),
+			hydraclient.WithForwardedProto(spec.HydraAdmin.ForwardedProto),
+			hydraclient.WithCache(cacheTTL),
+		)
+
+		// the TLS credentials, authentication and headers of the controller
+		// are only for the default instance, not for any host an
+		// OAuth2Client sets
+		if spec.HydraAdmin.URL != defaultSpec.HydraAdmin.URL || spec.HydraAdmin.Port != defaultSpec.HydraAdmin.Port {
+			return client, nil
+		}
+
+		if rotatingCertificate != nil {
+			tlsConfig, err := rotatingCertificate.TLSConfig(u.Hostname())
+			if err != nil {
+				return nil, fmt.Errorf("unable to load the TLS credentials for ORY Hydra: %w", err)
+			}
+			client.HTTPClient.Transport = controllers.InstrumentHydraTransport(&http.Transport{TLSClientConfig: tlsConfig})
 		}
 
-		if spec.HydraAdmin.ForwardedProto != "" && spec.HydraAdmin.ForwardedProto != "off" {
-			client.ForwardedProto = spec.HydraAdmin.ForwardedProto
+		for _, opt := range defaultOptions {
+			opt(client)
 		}
 
 		return client, nil
 	})
 
 }
+
+// parseHeaders parses a comma-separated list of headers given as name=value
+func parseHeaders(value string) (http.Header, error) {
+	headers := http.Header{}
+	for _, header := range splitList(value) {
+		i := strings.Index(header, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("header %q is not given as name=value", header)
+		}
+		headers.Add(strings.TrimSpace(header[:i]), strings.TrimSpace(header[i+1:]))
+	}
+	return headers, nil
+}
+
+// newVaultClient returns a client of the HashiCorp Vault server at address,
+// authenticating with the token in tokenFile if it is set, and with the
+// Kubernetes auth method otherwise
+func newVaultClient(address, mount, tokenFile, kubernetesRole, kubernetesMount string) (*vault.Client, error) {
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf("vault address %s is invalid: %w", address, err)
+	}
+
+	var auth vault.Authenticator = vault.KubernetesAuth{Role: kubernetesRole, Mount: kubernetesMount}
+	if tokenFile != "" {
+		token, err := ioutil.ReadFile(tokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the vault token: %w", err)
+		}
+		auth = vault.Token(strings.TrimSpace(string(token)))
+	}
+	return &vault.Client{Address: *u, HTTPClient: &http.Client{Timeout: 10 * time.Second}, Mount: mount, Auth: auth}, nil
+}
+
+// splitList splits a comma-separated flag value, dropping empty elements
+func splitList(value string) []string {
+	var elems []string
+	for _, elem := range strings.Split(value, ",") {
+		if elem = strings.TrimSpace(elem); elem != "" {
+			elems = append(elems, elem)
+		}
+	}
+	return elems
+}
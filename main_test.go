@@ -0,0 +1,57 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"testing"
+
+	hydrav1alpha1 "github.com/ory/hydra-maester/api/v1alpha1"
+	"github.com/ory/hydra-maester/pkg/hydraclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetHydraClientMakerCredentials(t *testing.T) {
+
+	defaultSpec := hydrav1alpha1.OAuth2ClientSpec{HydraAdmin: hydrav1alpha1.HydraAdmin{
+		URL:      "https://hydra-admin",
+		Port:     4445,
+		Endpoint: hydraclient.ClientsEndpointV1,
+	}}
+	// loading it fails, so any attempt to apply it is reported
+	certificate := &hydraclient.RotatingCertificate{CertFile: "tls.crt"}
+
+	for _, tc := range []struct {
+		desc     string
+		admin    hydrav1alpha1.HydraAdmin
+		expected bool
+	}{
+		{desc: "default instance", expected: true},
+		{desc: "default URL and port set", admin: hydrav1alpha1.HydraAdmin{URL: "https://hydra-admin", Port: 4445}, expected: true},
+		{desc: "other URL", admin: hydrav1alpha1.HydraAdmin{URL: "https://attacker.example.com"}},
+		{desc: "other port", admin: hydrav1alpha1.HydraAdmin{Port: 443}},
+	} {
+		t.Run("case="+tc.desc, func(t *testing.T) {
+			_, err := getHydraClientMaker(defaultSpec, certificate, 0)(hydrav1alpha1.OAuth2ClientSpec{HydraAdmin: tc.admin})
+			assert.Equal(t, tc.expected, err != nil)
+
+			var applied bool
+			_, err = getHydraClientMaker(defaultSpec, nil, 0, func(*hydraclient.Client) { applied = true })(hydrav1alpha1.OAuth2ClientSpec{HydraAdmin: tc.admin})
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, applied)
+		})
+	}
+}
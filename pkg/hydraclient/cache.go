@@ -0,0 +1,100 @@
+package hydraclient
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// ClientCache remembers the clients fetched from ORY Hydra, and the IDs of
+// clients which don't exist, for a short time, so that frequent
+// reconciliations of unchanged clients don't request them over and over.
+// The writes of the Client using it evict the clients they change. It is
+// safe for concurrent use.
+type ClientCache struct {
+	// TTL is how long fetched clients are remembered
+	TTL time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cachedClient
+	now     func() time.Time
+}
+
+type cachedClient struct {
+	client  []byte
+	found   bool
+	expires time.Time
+}
+
+// NewClientCache returns a cache remembering clients for ttl
+func NewClientCache(ttl time.Duration) *ClientCache {
+	return &ClientCache{TTL: ttl}
+}
+
+// get returns a copy of the cached client with the given ID, whether it
+// exists, and whether it was cached
+func (cc *ClientCache) get(id string) (*OAuth2ClientJSON, bool, bool) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	entry, ok := cc.entries[id]
+	if !ok {
+		return nil, false, false
+	}
+	if !cc.clock().Before(entry.expires) {
+		delete(cc.entries, id)
+		return nil, false, false
+	}
+	if !entry.found {
+		return nil, false, true
+	}
+	// callers may modify the client, so each gets its own copy
+	var c *OAuth2ClientJSON
+	if err := json.Unmarshal(entry.client, &c); err != nil {
+		delete(cc.entries, id)
+		return nil, false, false
+	}
+	return c, true, true
+}
+
+// put remembers the client with the given ID, nil if it doesn't exist
+func (cc *ClientCache) put(id string, c *OAuth2ClientJSON) {
+	entry := cachedClient{found: c != nil}
+	if c != nil {
+		data, err := json.Marshal(c)
+		if err != nil {
+			return
+		}
+		entry.client = data
+	}
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	if cc.entries == nil {
+		cc.entries = map[string]cachedClient{}
+	}
+	now := cc.clock()
+	// expired entries are dropped on writes, so the cache doesn't grow
+	// with clients which are never requested again
+	for key, cached := range cc.entries {
+		if !now.Before(cached.expires) {
+			delete(cc.entries, key)
+		}
+	}
+	entry.expires = now.Add(cc.TTL)
+	cc.entries[id] = entry
+}
+
+// evict forgets the client with the given ID
+func (cc *ClientCache) evict(id string) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	delete(cc.entries, id)
+}
+
+func (cc *ClientCache) clock() time.Time {
+	if cc.now != nil {
+		return cc.now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,101 @@
+package hydraclient_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ory/hydra-maester/pkg/hydraclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientCache(t *testing.T) {
+
+	assert := assert.New(t)
+
+	gets := map[string]int{}
+	h := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		id := strings.TrimPrefix(req.URL.Path, clientsEndpoint+"/")
+		switch req.Method {
+		case http.MethodGet:
+			gets[id]++
+			if id == "missing" {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(statusNotFoundBody))
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"client_id":"` + id + `","client_name":"test"}`))
+		case http.MethodPut:
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"client_id":"` + id + `"}`))
+		}
+	})
+
+	newClient := func(ttl time.Duration) *hydraclient.Client {
+		c := &hydraclient.Client{
+			HTTPClient: &http.Client{},
+			HydraURL:   url.URL{Scheme: schemeHTTP},
+			Cache:      hydraclient.NewClientCache(ttl),
+		}
+		runServer(c, h)
+		return c
+	}
+
+	t.Run("case=fetched clients are cached", func(t *testing.T) {
+		gets = map[string]int{}
+		c := newClient(time.Minute)
+
+		for i := 0; i < 3; i++ {
+			fetched, found, err := c.WithContext(context.Background()).GetOAuth2Client("test-id")
+			require.NoError(t, err)
+			require.True(t, found)
+			assert.Equal("test", fetched.ClientName)
+			// modifying a cached client doesn't change the cache
+			fetched.ClientName = ""
+		}
+		assert.Equal(1, gets["test-id"])
+	})
+
+	t.Run("case=missing clients are cached", func(t *testing.T) {
+		gets = map[string]int{}
+		c := newClient(time.Minute)
+
+		for i := 0; i < 2; i++ {
+			_, found, err := c.GetOAuth2Client("missing")
+			require.NoError(t, err)
+			assert.False(found)
+		}
+		assert.Equal(1, gets["missing"])
+	})
+
+	t.Run("case=updates evict clients", func(t *testing.T) {
+		gets = map[string]int{}
+		c := newClient(time.Minute)
+
+		_, _, err := c.GetOAuth2Client("test-id")
+		require.NoError(t, err)
+		id := "test-id"
+		_, err = c.PutOAuth2Client(&hydraclient.OAuth2ClientJSON{ClientID: &id})
+		require.NoError(t, err)
+		_, _, err = c.GetOAuth2Client("test-id")
+		require.NoError(t, err)
+		assert.Equal(2, gets["test-id"])
+	})
+
+	t.Run("case=clients expire", func(t *testing.T) {
+		gets = map[string]int{}
+		c := newClient(10 * time.Millisecond)
+
+		_, _, err := c.GetOAuth2Client("test-id")
+		require.NoError(t, err)
+		time.Sleep(20 * time.Millisecond)
+		_, _, err = c.GetOAuth2Client("test-id")
+		require.NoError(t, err)
+		assert.Equal(2, gets["test-id"])
+	})
+}
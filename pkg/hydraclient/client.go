@@ -0,0 +1,320 @@
+package hydraclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const versionPath = "/version"
+
+// Client is a client of ORY Hydra's admin API. It is safe for concurrent
+// use: requests build their URLs from copies of HydraURL, which is never
+// modified after the Client is created.
+type Client struct {
+	HydraURL       url.URL
+	HTTPClient     *http.Client
+	ForwardedProto string
+	// Authorization, if set, is sent as the Authorization header of all
+	// requests, e.g. for ORY Hydra behind an authenticating proxy
+	Authorization string
+	// AuthorizationHeader, if set, is the header Authorization is sent in
+	// instead, e.g. X-API-Key
+	AuthorizationHeader string
+	// Headers are sent with all requests, e.g. the routing headers of an API
+	// gateway ORY Hydra is multiplexed behind. They don't replace the
+	// headers set by the Client.
+	Headers http.Header
+	// Cache, if set, remembers the clients fetched by GetOAuth2Client. It is
+	// shared by the copies returned by WithContext.
+	Cache *ClientCache
+
+	ctx context.Context
+}
+
+// WithContext returns a copy of c whose requests are bound to ctx, so they
+// are cancelled once ctx is done
+func (c *Client) WithContext(ctx context.Context) *Client {
+	bound := *c
+	bound.ctx = ctx
+	return &bound
+}
+
+func (c *Client) GetOAuth2Client(id string) (*OAuth2ClientJSON, bool, error) {
+
+	if c.Cache != nil {
+		if cached, found, ok := c.Cache.get(id); ok {
+			return cached, found, nil
+		}
+	}
+
+	var jsonClient *OAuth2ClientJSON
+
+	req, err := c.newRequest(http.MethodGet, id, nil)
+	if err != nil {
+		return nil, false, err
+	}
+
+	resp, err := c.do(req, &jsonClient)
+	if err != nil {
+		return nil, false, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		if c.Cache != nil {
+			c.Cache.put(id, jsonClient)
+		}
+		return jsonClient, true, nil
+	case http.StatusNotFound:
+		if c.Cache != nil {
+			c.Cache.put(id, nil)
+		}
+		return nil, false, nil
+	default:
+		return nil, false, newStatusError(req, resp)
+	}
+}
+
+func (c *Client) ListOAuth2Client() ([]*OAuth2ClientJSON, error) {
+
+	var jsonClientList []*OAuth2ClientJSON
+
+	req, err := c.newRequest(http.MethodGet, "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do(req, &jsonClientList)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return jsonClientList, nil
+	default:
+		return nil, newStatusError(req, resp)
+	}
+}
+
+func (c *Client) PostOAuth2Client(o *OAuth2ClientJSON) (*OAuth2ClientJSON, error) {
+
+	var jsonClient *OAuth2ClientJSON
+
+	if c.Cache != nil && o.ClientID != nil {
+		defer c.Cache.evict(*o.ClientID)
+	}
+
+	req, err := c.newRequest(http.MethodPost, "", o)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do(req, &jsonClient)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusCreated:
+		return jsonClient, nil
+	case http.StatusConflict:
+		return nil, fmt.Errorf("%s %s http request failed: requested ID already exists", req.Method, req.URL)
+	default:
+		return nil, newStatusError(req, resp)
+	}
+}
+
+func (c *Client) PutOAuth2Client(o *OAuth2ClientJSON) (*OAuth2ClientJSON, error) {
+
+	var jsonClient *OAuth2ClientJSON
+
+	if c.Cache != nil {
+		defer c.Cache.evict(*o.ClientID)
+	}
+
+	req, err := c.newRequest(http.MethodPut, *o.ClientID, o)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do(req, &jsonClient)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newStatusError(req, resp)
+	}
+
+	return jsonClient, nil
+}
+
+func (c *Client) DeleteOAuth2Client(id string) error {
+
+	if c.Cache != nil {
+		defer c.Cache.evict(id)
+	}
+
+	req, err := c.newRequest(http.MethodDelete, id, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.do(req, nil)
+	if err != nil {
+		return err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusNoContent, http.StatusNotFound:
+		// a client which doesn't exist is deleted already
+		return nil
+	default:
+		return newStatusError(req, resp)
+	}
+}
+
+// GetVersion returns the version reported by ORY Hydra's version endpoint
+func (c *Client) GetVersion() (string, error) {
+
+	var version struct {
+		Version string `json:"version"`
+	}
+
+	req, err := c.newRequestURL(http.MethodGet, *c.HydraURL.ResolveReference(&url.URL{Path: versionPath}), nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := c.do(req, &version)
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", newStatusError(req, resp)
+	}
+
+	return version.Version, nil
+}
+
+func (c *Client) newRequest(method, id string, body interface{}) (*http.Request, error) {
+	return c.newRequestURL(method, joinURL(c.HydraURL, id), body)
+}
+
+// adminURL returns the URL of the admin endpoint at p, e.g. keysPath,
+// which ORY Hydra 2.x serves under /admin like its clients endpoint
+func (c *Client) adminURL(p string) url.URL {
+	if strings.HasSuffix(strings.TrimSuffix(c.HydraURL.Path, "/"), ClientsEndpointV2) {
+		p = "/admin" + p
+	}
+	return *c.HydraURL.ResolveReference(&url.URL{Path: p})
+}
+
+// joinURL returns a copy of base with elem appended to its path, each as a
+// single escaped path segment, so that IDs containing slashes or dots can't
+// address another endpoint. Empty elements are skipped.
+func joinURL(base url.URL, elem ...string) url.URL {
+	u := base
+	decoded := strings.TrimSuffix(base.Path, "/")
+	escaped := strings.TrimSuffix(base.EscapedPath(), "/")
+	for _, e := range elem {
+		if e == "" {
+			continue
+		}
+		decoded += "/" + e
+		if e == "." || e == ".." {
+			escaped += "/" + strings.Replace(e, ".", "%2E", -1)
+		} else {
+			escaped += "/" + url.PathEscape(e)
+		}
+	}
+	u.Path = decoded
+	u.RawPath = ""
+	// RawPath is only needed if the path isn't escaped the default way
+	if (&url.URL{Path: decoded}).EscapedPath() != escaped {
+		u.RawPath = escaped
+	}
+	return u
+}
+
+func (c *Client) newRequestURL(method string, u url.URL, body interface{}) (*http.Request, error) {
+
+	var buf io.ReadWriter
+	if body != nil {
+		buf = new(bytes.Buffer)
+		err := json.NewEncoder(buf).Encode(body)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ctx := c.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), buf)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, values := range c.Headers {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+	if c.ForwardedProto != "" {
+		req.Header.Set("X-Forwarded-Proto", c.ForwardedProto)
+	}
+	if c.Authorization != "" {
+		header := c.AuthorizationHeader
+		if header == "" {
+			header = "Authorization"
+		}
+		req.Header.Set(header, c.Authorization)
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+
+	return req, nil
+
+}
+
+func (c *Client) do(req *http.Request, v interface{}) (*http.Response, error) {
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		// keep the beginning of the body, which tells why ORY Hydra rejected
+		// the request, for newStatusError
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+		return resp, nil
+	}
+	if v == nil {
+		return resp, nil
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err == nil {
+		err = json.Unmarshal(body, v)
+	}
+	if err != nil {
+		return resp, newDecodeError(req, resp, body, err)
+	}
+	return resp, nil
+}
@@ -1,6 +1,7 @@
-package hydra_test
+package hydraclient_test
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -8,11 +9,13 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"k8s.io/utils/pointer"
 
-	"github.com/ory/hydra-maester/hydra"
+	"github.com/ory/hydra-maester/pkg/hydraclient"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -31,6 +34,7 @@ const (
 
 	statusNotFoundBody            = `{"error":"Not Found","error_description":"Unable to locate the requested resource","status_code":404,"request_id":"id"}`
 	statusUnauthorizedBody        = `{"error":"The request could not be authorized","error_description":"The requested OAuth 2.0 client does not exist or you did not provide the necessary credentials","status_code":401,"request_id":"id"}`
+	statusForbiddenBody           = `{"error":"Forbidden","error_description":"The request is not allowed","status_code":403,"request_id":"id"}`
 	statusConflictBody            = `{"error":"Unable to insert or update resource because a resource with that value exists already","error_description":"","status_code":409,"request_id":"id"`
 	statusInternalServerErrorBody = "the server encountered an internal error or misconfiguration and was unable to complete your request"
 )
@@ -41,14 +45,14 @@ type server struct {
 	err        error
 }
 
-var testOAuthJSONPost = &hydra.OAuth2ClientJSON{
+var testOAuthJSONPost = &hydraclient.OAuth2ClientJSON{
 	Scope:      "some,other,scopes",
 	GrantTypes: []string{"type2"},
 	Owner:      "test-name-2",
 	Audience:   []string{"audience-a", "audience-b"},
 }
 
-var testOAuthJSONPut = &hydra.OAuth2ClientJSON{
+var testOAuthJSONPut = &hydraclient.OAuth2ClientJSON{
 	ClientID:   pointer.StringPtr("test-id-3"),
 	Scope:      "yet,another,scope",
 	GrantTypes: []string{"type3"},
@@ -60,7 +64,7 @@ func TestCRUD(t *testing.T) {
 
 	assert := assert.New(t)
 
-	c := hydra.Client{
+	c := hydraclient.Client{
 		HTTPClient: &http.Client{},
 		HydraURL:   url.URL{Scheme: schemeHTTP},
 	}
@@ -81,7 +85,12 @@ func TestCRUD(t *testing.T) {
 			"getting unauthorized request": {
 				http.StatusUnauthorized,
 				statusUnauthorizedBody,
-				nil,
+				errors.New("http request returned unexpected status code"),
+			},
+			"getting forbidden request": {
+				http.StatusForbidden,
+				statusForbiddenBody,
+				errors.New("http request returned unexpected status code"),
 			},
 			"internal server error when requesting": {
 				http.StatusInternalServerError,
@@ -119,7 +128,7 @@ func TestCRUD(t *testing.T) {
 				assert.Equal(shouldFind, found)
 				if shouldFind {
 					require.NotNil(t, o)
-					var expected hydra.OAuth2ClientJSON
+					var expected hydraclient.OAuth2ClientJSON
 					json.Unmarshal([]byte(testClient), &expected)
 					assert.Equal(&expected, o)
 				}
@@ -154,8 +163,8 @@ func TestCRUD(t *testing.T) {
 			t.Run(fmt.Sprintf("case/%s", d), func(t *testing.T) {
 				var (
 					err      error
-					o        *hydra.OAuth2ClientJSON
-					expected *hydra.OAuth2ClientJSON
+					o        *hydraclient.OAuth2ClientJSON
+					expected *hydraclient.OAuth2ClientJSON
 				)
 				//given
 				new := tc.statusCode == http.StatusCreated
@@ -178,7 +187,7 @@ func TestCRUD(t *testing.T) {
 						"property1": float64(1),
 						"property2": "2",
 					})
-					var testOAuthJSONPost2 = &hydra.OAuth2ClientJSON{
+					var testOAuthJSONPost2 = &hydraclient.OAuth2ClientJSON{
 						Scope:      "some,other,scopes",
 						GrantTypes: []string{"type2"},
 						Owner:      "test-name-21",
@@ -363,7 +372,7 @@ func TestCRUD(t *testing.T) {
 				if tc.err == nil {
 					require.NoError(t, err)
 					require.NotNil(t, list)
-					var expectedList []*hydra.OAuth2ClientJSON
+					var expectedList []*hydraclient.OAuth2ClientJSON
 					json.Unmarshal([]byte(tc.respBody), &expectedList)
 					assert.Equal(expectedList, list)
 				} else {
@@ -374,8 +383,211 @@ func TestCRUD(t *testing.T) {
 		}
 	})
 
+	t.Run("method=version", func(t *testing.T) {
+
+		for d, tc := range map[string]server{
+			"version reported": {
+				http.StatusOK,
+				`{"version":"v1.4.10"}`,
+				nil,
+			},
+			"internal server error when requesting": {
+				http.StatusInternalServerError,
+				statusInternalServerErrorBody,
+				errors.New("http request returned unexpected status code"),
+			},
+		} {
+			t.Run(fmt.Sprintf("case/%s", d), func(t *testing.T) {
+
+				//given
+				h := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+					assert.Equal("/version", req.URL.Path)
+					assert.Equal(http.MethodGet, req.Method)
+					w.WriteHeader(tc.statusCode)
+					w.Write([]byte(tc.respBody))
+				})
+				runServer(&c, h)
+
+				//when
+				version, err := c.GetVersion()
+
+				//then
+				if tc.err == nil {
+					require.NoError(t, err)
+					assert.Equal("v1.4.10", version)
+				} else {
+					require.Error(t, err)
+					assert.Contains(err.Error(), tc.err.Error())
+				}
+			})
+		}
+	})
+
+	t.Run("method=get with malformed response", func(t *testing.T) {
+
+		for d, tc := range map[string]struct {
+			contentType string
+			respBody    string
+		}{
+			"truncated JSON": {
+				"application/json",
+				testClient[:len(testClient)/2],
+			},
+			"proxy error page": {
+				"text/html",
+				"<html><body><h1>502 Bad Gateway</h1></body></html>",
+			},
+			"empty body": {
+				"",
+				"",
+			},
+		} {
+			t.Run(fmt.Sprintf("case/%s", d), func(t *testing.T) {
+
+				//given
+				h := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+					if tc.contentType != "" {
+						w.Header().Set("Content-type", tc.contentType)
+					}
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte(tc.respBody))
+				})
+				runServer(&c, h)
+
+				//when
+				o, found, err := c.GetOAuth2Client(testID)
+
+				//then
+				require.Error(t, err)
+				assert.Nil(o)
+				assert.False(found)
+
+				decodeErr, ok := err.(*hydraclient.DecodeError)
+				require.True(t, ok, "expected a *hydraclient.DecodeError, got %T", err)
+				assert.Equal(http.MethodGet, decodeErr.Method)
+				assert.Equal(tc.respBody, decodeErr.Body)
+				assert.Contains(err.Error(), "not valid JSON")
+				assert.True(hydraclient.IsRetryable(err))
+			})
+		}
+
+		t.Run("case/unexpected status code is not retryable", func(t *testing.T) {
+			h := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			})
+			runServer(&c, h)
+
+			_, _, err := c.GetOAuth2Client(testID)
+			require.Error(t, err)
+			assert.False(hydraclient.IsRetryable(err))
+			assert.True(hydraclient.IsUnavailable(err))
+		})
+
+		t.Run("case/rate limited with Retry-After", func(t *testing.T) {
+			for value, expected := range map[string]time.Duration{
+				"120": 2 * time.Minute,
+				time.Now().Add(time.Hour).UTC().Format(http.TimeFormat): time.Hour,
+				"":        0,
+				"invalid": 0,
+			} {
+				h := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+					if value != "" {
+						w.Header().Set("Retry-After", value)
+					}
+					w.WriteHeader(http.StatusTooManyRequests)
+				})
+				runServer(&c, h)
+
+				_, _, err := c.GetOAuth2Client(testID)
+				require.Error(t, err)
+				assert.True(hydraclient.IsUnavailable(err), value)
+				retryAfter, ok := hydraclient.RetryAfter(err)
+				assert.Equal(expected > 0, ok, value)
+				assert.InDelta(float64(expected), float64(retryAfter), float64(time.Second), value)
+			}
+		})
+	})
+
+	t.Run("method=post with a rejected client", func(t *testing.T) {
+
+		for d, tc := range map[string]struct {
+			respBody string
+			excerpt  string
+		}{
+			"with the explanation of ORY Hydra": {
+				`{"error":"invalid_redirect_uri","error_description":"The value of one or more redirect_uris is invalid."}`,
+				`{"error":"invalid_redirect_uri","error_description":"The value of one or more redirect_uris is invalid."}`,
+			},
+			"with a secret echoed": {
+				`{"error":"invalid_client_metadata","client_secret":"TmGkvcY7k526","registration_access_token": "abc"}`,
+				`{"error":"invalid_client_metadata","client_secret":"[REDACTED]","registration_access_token": "[REDACTED]"}`,
+			},
+			"with a long body": {
+				strings.Repeat("a", 1000),
+				strings.Repeat("a", 256) + "...",
+			},
+		} {
+			t.Run(fmt.Sprintf("case/%s", d), func(t *testing.T) {
+
+				//given
+				h := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+					w.WriteHeader(http.StatusBadRequest)
+					w.Write([]byte(tc.respBody))
+				})
+				runServer(&c, h)
+
+				//when
+				_, err := c.PostOAuth2Client(testOAuthJSONPost)
+
+				//then
+				require.Error(t, err)
+				statusErr, ok := err.(*hydraclient.StatusError)
+				require.True(t, ok, "expected a *hydraclient.StatusError, got %T", err)
+				assert.Equal(http.StatusBadRequest, statusErr.StatusCode)
+				assert.Equal(tc.excerpt, statusErr.Body)
+				assert.Contains(err.Error(), "http request returned unexpected status code 400 Bad Request: "+tc.excerpt)
+				assert.False(hydraclient.IsRetryable(err))
+				assert.False(hydraclient.IsUnavailable(err))
+			})
+		}
+	})
+
+	t.Run("method=get with an expired context", func(t *testing.T) {
+		h := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(testClient))
+		})
+		runServer(&c, h)
+
+		ctx, cancel := context.WithTimeout(context.Background(), -time.Second)
+		defer cancel()
+
+		_, _, err := c.WithContext(ctx).GetOAuth2Client(testID)
+		require.Error(t, err)
+		assert.True(errors.Is(err, context.DeadlineExceeded))
+
+		_, found, err := c.GetOAuth2Client(testID)
+		require.NoError(t, err)
+		assert.True(found)
+	})
+
+	t.Run("method=get with authorization", func(t *testing.T) {
+		h := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			assert.Equal("Bearer token", req.Header.Get("Authorization"))
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(testClient))
+		})
+		runServer(&c, h)
+
+		authorized := c
+		authorized.Authorization = "Bearer token"
+		_, found, err := authorized.GetOAuth2Client(testID)
+		require.NoError(t, err)
+		assert.True(found)
+	})
+
 	t.Run("default parameters", func(t *testing.T) {
-		var input = &hydra.OAuth2ClientJSON{
+		var input = &hydraclient.OAuth2ClientJSON{
 			Scope:      "some,other,scopes",
 			GrantTypes: []string{"type2"},
 			Owner:      "test-name-2",
@@ -385,7 +597,7 @@ func TestCRUD(t *testing.T) {
 		payload := string(b)
 		assert.Equal(strings.Index(payload, "token_endpoint_auth_method"), -1)
 
-		input = &hydra.OAuth2ClientJSON{
+		input = &hydraclient.OAuth2ClientJSON{
 			Scope:                   "some,other,scopes",
 			GrantTypes:              []string{"type2"},
 			Owner:                   "test-name-3",
@@ -397,7 +609,49 @@ func TestCRUD(t *testing.T) {
 	})
 }
 
-func runServer(c *hydra.Client, h http.HandlerFunc) {
+func TestConcurrentRequests(t *testing.T) {
+
+	c := hydraclient.Client{
+		HTTPClient: &http.Client{},
+		HydraURL:   url.URL{Scheme: schemeHTTP},
+	}
+
+	var mu sync.Mutex
+	paths := map[string]bool{}
+	runServer(&c, func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		paths[req.URL.EscapedPath()] = true
+		mu.Unlock()
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(statusNotFoundBody))
+	})
+	hydraURL := c.HydraURL.String()
+
+	ids := []string{"a", "b", "c/d", "..", "e?f"}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		for _, id := range ids {
+			wg.Add(1)
+			go func(id string) {
+				defer wg.Done()
+				_, _, err := c.GetOAuth2Client(id)
+				assert.NoError(t, err)
+			}(id)
+		}
+	}
+	wg.Wait()
+
+	assert.Equal(t, hydraURL, c.HydraURL.String(), "the URL of the client must not be modified")
+	assert.Equal(t, map[string]bool{
+		"/clients/a":      true,
+		"/clients/b":      true,
+		"/clients/c%2Fd":  true,
+		"/clients/%2E%2E": true,
+		"/clients/e%3Ff":  true,
+	}, paths, "each ID must be requested as a single path segment")
+}
+
+func runServer(c *hydraclient.Client, h http.HandlerFunc) {
 	s := httptest.NewServer(h)
 	serverUrl, _ := url.Parse(s.URL)
 	c.HydraURL = *serverUrl.ResolveReference(&url.URL{Path: clientsEndpoint})
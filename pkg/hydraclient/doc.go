@@ -0,0 +1,14 @@
+// Package hydraclient is a client of ORY Hydra's admin API, along with the
+// logic the controller uses to compare OAuth2 clients with the ones
+// registered in ORY Hydra and to merge them. Other operators and tools can
+// use it to manage clients the same way; its exported API is kept
+// backwards compatible.
+//
+// Clients are created with New and configured with options:
+//
+//	c := hydraclient.New(hydraURL,
+//		hydraclient.WithAuthorization("Bearer "+token),
+//		hydraclient.WithCache(5*time.Second),
+//	)
+//	client, found, err := c.GetOAuth2Client(id)
+package hydraclient
@@ -0,0 +1,170 @@
+package hydraclient
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxBodySnippet is the maximum length of the response body quoted in errors
+const maxBodySnippet = 256
+
+// maxErrorBody is the maximum length of the body of error responses which is
+// read, secrets are redacted in it before it is cut to maxBodySnippet
+const maxErrorBody = 4096
+
+// secretValue matches the values of JSON properties and form fields whose
+// names end with secret, password or token, e.g. client_secret
+var secretValue = regexp.MustCompile(`(?i)("?[\w-]*(?:secret|password|token)"?\s*[:=]\s*"?)[^"&,\s}]+`)
+
+// DecodeError is returned when a successful response from ORY Hydra can't be
+// decoded, such as a truncated body or an error page of a proxy in front of
+// ORY Hydra
+type DecodeError struct {
+	Method      string
+	URL         string
+	Status      string
+	ContentType string
+	// Body is the beginning of the response body
+	Body string
+	Err  error
+}
+
+func newDecodeError(req *http.Request, resp *http.Response, body []byte, err error) *DecodeError {
+	return &DecodeError{
+		Method:      req.Method,
+		URL:         req.URL.String(),
+		Status:      resp.Status,
+		ContentType: resp.Header.Get("Content-Type"),
+		Body:        excerpt(body),
+		Err:         err,
+	}
+}
+
+func (e *DecodeError) Error() string {
+	contentType := e.ContentType
+	if contentType == "" {
+		contentType = "none"
+	}
+	return fmt.Sprintf("%s %s http request returned a response that is not valid JSON (status %s, content type %s): %s: %q",
+		e.Method, e.URL, e.Status, contentType, e.Err, e.Body)
+}
+
+// Unwrap returns the underlying decoding error
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// StatusError is returned when ORY Hydra answers with an unexpected status
+// code. It quotes the beginning of the response body, which usually tells why
+// ORY Hydra rejected the request.
+type StatusError struct {
+	Method     string
+	URL        string
+	Status     string
+	StatusCode int
+	// Body is the beginning of the response body, with secrets redacted
+	Body string
+	// RetryAfter is how long ORY Hydra, or a gateway in front of it, asked
+	// to wait before retrying with the Retry-After header, e.g. along with
+	// 429 Too Many Requests
+	RetryAfter time.Duration
+}
+
+func newStatusError(req *http.Request, resp *http.Response) *StatusError {
+	var body []byte
+	if resp.Body != nil {
+		body, _ = ioutil.ReadAll(resp.Body)
+	}
+
+	return &StatusError{
+		Method:     req.Method,
+		URL:        req.URL.String(),
+		Status:     resp.Status,
+		StatusCode: resp.StatusCode,
+		Body:       excerpt(body),
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+	}
+}
+
+// parseRetryAfter returns the delay of a Retry-After header, given either
+// in seconds or as an HTTP date, or 0 if there is none
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
+
+// RetryAfter returns how long ORY Hydra asked to wait before retrying the
+// request which failed with err, if it did
+func RetryAfter(err error) (time.Duration, bool) {
+	if statusErr, ok := err.(*StatusError); ok && statusErr.RetryAfter > 0 {
+		return statusErr.RetryAfter, true
+	}
+	return 0, false
+}
+
+func (e *StatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("%s %s http request returned unexpected status code %s", e.Method, e.URL, e.Status)
+	}
+	return fmt.Sprintf("%s %s http request returned unexpected status code %s: %s", e.Method, e.URL, e.Status, e.Body)
+}
+
+// excerpt returns the beginning of a response body to be quoted in errors,
+// with the values of properties which look like secrets redacted
+func excerpt(body []byte) string {
+	snippet := secretValue.ReplaceAllString(string(body), "${1}[REDACTED]")
+	if len(snippet) > maxBodySnippet {
+		snippet = snippet[:maxBodySnippet]
+		for !utf8.ValidString(snippet) {
+			snippet = snippet[:len(snippet)-1]
+		}
+		snippet += "..."
+	}
+	return strings.TrimSpace(snippet)
+}
+
+// IsRetryable reports whether err is likely to be transient, so the request
+// which failed with it is worth retrying. Failures to reach ORY Hydra and
+// responses which can't be decoded are considered transient, as the latter
+// are usually caused by proxies or interrupted connections rather than by
+// ORY Hydra itself.
+func IsRetryable(err error) bool {
+	switch e := err.(type) {
+	case *DecodeError, *url.Error:
+		return true
+	case net.Error:
+		return e.Timeout()
+	}
+	return false
+}
+
+// IsUnavailable reports whether err shows that ORY Hydra is unavailable,
+// e.g. while it restarts or because a gateway in front of it rate-limits the
+// controller: it is either retryable, a server error or 429 Too Many
+// Requests. Unlike retryable errors, these may have been caused by the
+// request itself, so they are better retried after a while than right away.
+func IsUnavailable(err error) bool {
+	if statusErr, ok := err.(*StatusError); ok {
+		return statusErr.StatusCode >= http.StatusInternalServerError || statusErr.StatusCode == http.StatusTooManyRequests
+	}
+	return IsRetryable(err)
+}
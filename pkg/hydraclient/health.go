@@ -0,0 +1,30 @@
+package hydraclient
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// readyPath is the endpoint reporting whether ORY Hydra and its database
+// are ready to serve requests
+const readyPath = "/health/ready"
+
+// CheckReady returns an error unless ORY Hydra reports that it is ready to
+// serve requests
+func (c *Client) CheckReady() error {
+
+	req, err := c.newRequestURL(http.MethodGet, *c.HydraURL.ResolveReference(&url.URL{Path: readyPath}), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.do(req, nil)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return newStatusError(req, resp)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package hydraclient_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/ory/hydra-maester/pkg/hydraclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckReady(t *testing.T) {
+
+	assert := assert.New(t)
+
+	c := hydraclient.Client{
+		HTTPClient: &http.Client{},
+		HydraURL:   url.URL{Scheme: schemeHTTP},
+	}
+
+	for d, tc := range map[string]server{
+		"ready": {
+			http.StatusOK,
+			`{"status":"ok"}`,
+			nil,
+		},
+		"database unavailable": {
+			http.StatusServiceUnavailable,
+			`{"errors":{"database":"not alive"}}`,
+			errors.New("http request returned unexpected status code"),
+		},
+	} {
+		t.Run(fmt.Sprintf("case/%s", d), func(t *testing.T) {
+
+			//given
+			h := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				assert.Equal("/health/ready", req.URL.Path)
+				assert.Equal(http.MethodGet, req.Method)
+				w.WriteHeader(tc.statusCode)
+				w.Write([]byte(tc.respBody))
+			})
+			runServer(&c, h)
+
+			//when
+			err := c.CheckReady()
+
+			//then
+			if tc.err != nil {
+				require.Error(t, err)
+				assert.Contains(err.Error(), tc.err.Error())
+				assert.True(hydraclient.IsUnavailable(err))
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package hydraclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const keysPath = "/keys"
+
+// JSONWebKeySet is a JSON web key set managed by ORY Hydra
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// JSONWebKey is a JSON web key. Only the properties the controller relies on
+// are modeled, the key is kept as returned by ORY Hydra in Raw.
+type JSONWebKey struct {
+	KeyID     string
+	Algorithm string
+	Use       string
+	Raw       json.RawMessage
+}
+
+type jsonWebKeyHeader struct {
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg,omitempty"`
+	Use       string `json:"use,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (k *JSONWebKey) UnmarshalJSON(data []byte) error {
+	var header jsonWebKeyHeader
+	if err := json.Unmarshal(data, &header); err != nil {
+		return err
+	}
+	k.KeyID, k.Algorithm, k.Use = header.KeyID, header.Algorithm, header.Use
+	k.Raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (k JSONWebKey) MarshalJSON() ([]byte, error) {
+	if len(k.Raw) > 0 {
+		return k.Raw, nil
+	}
+	return json.Marshal(jsonWebKeyHeader{KeyID: k.KeyID, Algorithm: k.Algorithm, Use: k.Use})
+}
+
+// BaseKeyID returns the ID of the key without the private: or public:
+// prefix older ORY Hydra versions store the halves of key pairs with
+func (k JSONWebKey) BaseKeyID() string {
+	return strings.TrimPrefix(strings.TrimPrefix(k.KeyID, "private:"), "public:")
+}
+
+// JSONWebKeyGeneratorRequest is the request generating a key in a set
+type JSONWebKeyGeneratorRequest struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+}
+
+// GetJSONWebKeySet returns the JSON web key set with the given name, and
+// whether it exists
+func (c *Client) GetJSONWebKeySet(set string) (*JSONWebKeySet, bool, error) {
+
+	var keySet *JSONWebKeySet
+
+	req, err := c.newRequestURL(http.MethodGet, c.keysURL(set), nil)
+	if err != nil {
+		return nil, false, err
+	}
+
+	resp, err := c.do(req, &keySet)
+	if err != nil {
+		return nil, false, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return keySet, true, nil
+	case http.StatusNotFound:
+		return nil, false, nil
+	default:
+		return nil, false, newStatusError(req, resp)
+	}
+}
+
+// GenerateJSONWebKey generates a key in the JSON web key set with the given
+// name, which is created if it doesn't exist. It returns the generated key.
+func (c *Client) GenerateJSONWebKey(set string, r *JSONWebKeyGeneratorRequest) (*JSONWebKeySet, error) {
+
+	var keySet *JSONWebKeySet
+
+	req, err := c.newRequestURL(http.MethodPost, c.keysURL(set), r)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do(req, &keySet)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusCreated {
+		return nil, newStatusError(req, resp)
+	}
+
+	return keySet, nil
+}
+
+// DeleteJSONWebKey deletes the key with the given ID from the JSON web key
+// set with the given name
+func (c *Client) DeleteJSONWebKey(set, kid string) error {
+	return c.deleteKeys(c.keysURL(set, kid))
+}
+
+// DeleteJSONWebKeySet deletes the JSON web key set with the given name
+func (c *Client) DeleteJSONWebKeySet(set string) error {
+	return c.deleteKeys(c.keysURL(set))
+}
+
+func (c *Client) deleteKeys(u url.URL) error {
+
+	req, err := c.newRequestURL(http.MethodDelete, u, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.do(req, nil)
+	if err != nil {
+		return err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusNoContent, http.StatusNotFound:
+		return nil
+	default:
+		return newStatusError(req, resp)
+	}
+}
+
+// keysURL returns the URL of the keys API for the given path elements. The
+// keys API is served next to the client endpoint.
+func (c *Client) keysURL(elem ...string) url.URL {
+	return joinURL(c.adminURL(keysPath), elem...)
+}
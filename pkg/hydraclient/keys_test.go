@@ -0,0 +1,175 @@
+package hydraclient_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/ory/hydra-maester/pkg/hydraclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testKeySet           = "test-set"
+	testKeySetBody       = `{"keys":[{"kid":"private:key-1","alg":"RS256","use":"sig","kty":"RSA","n":"n","e":"AQAB","d":"d"},{"kid":"public:key-1","alg":"RS256","use":"sig","kty":"RSA","n":"n","e":"AQAB"}]}`
+	testKeyGeneratedBody = `{"keys":[{"kid":"key-2","alg":"ES256","use":"sig","kty":"EC","crv":"P-256","x":"x","y":"y","d":"d"}]}`
+)
+
+func TestKeys(t *testing.T) {
+
+	assert := assert.New(t)
+
+	c := hydraclient.Client{
+		HTTPClient: &http.Client{},
+		HydraURL:   url.URL{Scheme: schemeHTTP},
+	}
+
+	t.Run("method=get", func(t *testing.T) {
+
+		for d, tc := range map[string]server{
+			"getting existing set": {
+				http.StatusOK,
+				testKeySetBody,
+				nil,
+			},
+			"getting missing set": {
+				http.StatusNotFound,
+				statusNotFoundBody,
+				nil,
+			},
+			"internal server error when requesting": {
+				http.StatusInternalServerError,
+				statusInternalServerErrorBody,
+				errors.New("http request returned unexpected status code"),
+			},
+		} {
+			t.Run(fmt.Sprintf("case/%s", d), func(t *testing.T) {
+
+				//given
+				h := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+					assert.Equal("/keys/"+testKeySet, req.URL.Path)
+					assert.Equal(http.MethodGet, req.Method)
+					w.WriteHeader(tc.statusCode)
+					w.Write([]byte(tc.respBody))
+				})
+				runServer(&c, h)
+
+				//when
+				keySet, found, err := c.GetJSONWebKeySet(testKeySet)
+
+				//then
+				if tc.err != nil {
+					require.Error(t, err)
+					assert.Contains(err.Error(), tc.err.Error())
+					return
+				}
+				require.NoError(t, err)
+				assert.Equal(tc.statusCode == http.StatusOK, found)
+				if found {
+					require.Len(t, keySet.Keys, 2)
+					assert.Equal("private:key-1", keySet.Keys[0].KeyID)
+					assert.Equal("key-1", keySet.Keys[0].BaseKeyID())
+					assert.Equal("RS256", keySet.Keys[0].Algorithm)
+
+					raw, err := json.Marshal(keySet)
+					require.NoError(t, err)
+					assert.JSONEq(testKeySetBody, string(raw))
+				}
+			})
+		}
+	})
+
+	t.Run("method=generate", func(t *testing.T) {
+
+		for d, tc := range map[string]server{
+			"generating a key": {
+				http.StatusCreated,
+				testKeyGeneratedBody,
+				nil,
+			},
+			"internal server error when requesting": {
+				http.StatusInternalServerError,
+				statusInternalServerErrorBody,
+				errors.New("http request returned unexpected status code"),
+			},
+		} {
+			t.Run(fmt.Sprintf("case/%s", d), func(t *testing.T) {
+
+				//given
+				h := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+					assert.Equal("/keys/"+testKeySet, req.URL.Path)
+					assert.Equal(http.MethodPost, req.Method)
+
+					var body map[string]string
+					require.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+					assert.Equal(map[string]string{"alg": "ES256", "kid": "key-2", "use": "sig"}, body)
+
+					w.WriteHeader(tc.statusCode)
+					w.Write([]byte(tc.respBody))
+				})
+				runServer(&c, h)
+
+				//when
+				keySet, err := c.GenerateJSONWebKey(testKeySet, &hydraclient.JSONWebKeyGeneratorRequest{Algorithm: "ES256", KeyID: "key-2", Use: "sig"})
+
+				//then
+				if tc.err != nil {
+					require.Error(t, err)
+					assert.Contains(err.Error(), tc.err.Error())
+					return
+				}
+				require.NoError(t, err)
+				require.Len(t, keySet.Keys, 1)
+				assert.Equal("key-2", keySet.Keys[0].KeyID)
+			})
+		}
+	})
+
+	t.Run("method=delete", func(t *testing.T) {
+
+		for d, tc := range map[string]server{
+			"deleting existing key": {
+				http.StatusNoContent,
+				"",
+				nil,
+			},
+			"deleting missing key": {
+				http.StatusNotFound,
+				statusNotFoundBody,
+				nil,
+			},
+			"internal server error when requesting": {
+				http.StatusInternalServerError,
+				statusInternalServerErrorBody,
+				errors.New("http request returned unexpected status code"),
+			},
+		} {
+			t.Run(fmt.Sprintf("case/%s", d), func(t *testing.T) {
+
+				//given
+				h := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+					assert.Equal("/keys/"+testKeySet+"/key-1", req.URL.Path)
+					assert.Equal(http.MethodDelete, req.Method)
+					w.WriteHeader(tc.statusCode)
+					w.Write([]byte(tc.respBody))
+				})
+				runServer(&c, h)
+
+				//when
+				err := c.DeleteJSONWebKey(testKeySet, "key-1")
+
+				//then
+				if tc.err != nil {
+					require.Error(t, err)
+					assert.Contains(err.Error(), tc.err.Error())
+					return
+				}
+				require.NoError(t, err)
+			})
+		}
+	})
+}
@@ -0,0 +1,104 @@
+package hydraclient
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// Option configures a Client created by New
+type Option func(*Client)
+
+// New returns a client of the admin API of the ORY Hydra instance whose
+// clients endpoint is hydraURL, e.g. http://hydra-admin:4445/clients.
+// Unless WithHTTPClient is given, requests are sent with a client of its
+// own rather than http.DefaultClient.
+func New(hydraURL url.URL, opts ...Option) *Client {
+	c := &Client{HydraURL: hydraURL}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.HTTPClient == nil {
+		c.HTTPClient = &http.Client{}
+	}
+	return c
+}
+
+// WithHTTPClient sends the requests of the Client with httpClient, e.g. to
+// set its transport or timeout
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.HTTPClient = httpClient
+	}
+}
+
+// WithForwardedProto sets the X-Forwarded-Proto header of all requests,
+// which ORY Hydra requires to serve its admin API over plain HTTP behind a
+// TLS terminating proxy. "off" and "" don't set it.
+func WithForwardedProto(proto string) Option {
+	return func(c *Client) {
+		if proto == "off" {
+			proto = ""
+		}
+		c.ForwardedProto = proto
+	}
+}
+
+// WithAuthorization sends authorization as the Authorization header of all
+// requests
+func WithAuthorization(authorization string) Option {
+	return func(c *Client) {
+		c.Authorization = authorization
+	}
+}
+
+// WithAPIKey sends key as the given header of all requests, e.g. X-API-Key
+func WithAPIKey(header, key string) Option {
+	return func(c *Client) {
+		c.AuthorizationHeader = header
+		c.Authorization = key
+	}
+}
+
+// WithTokenSource authenticates all requests with the access tokens of ts,
+// e.g. obtained with the client credentials grant for ORY Hydra behind an
+// OAuth2 aware gateway. It wraps the transport of the HTTP client, so it is
+// given after WithHTTPClient.
+func WithTokenSource(ts oauth2.TokenSource) Option {
+	return func(c *Client) {
+		var httpClient http.Client
+		if c.HTTPClient != nil {
+			httpClient = *c.HTTPClient
+		}
+		httpClient.Transport = &oauth2.Transport{Source: ts, Base: httpClient.Transport}
+		c.HTTPClient = &httpClient
+	}
+}
+
+// WithHeaders sends headers with all requests, in addition to the ones set
+// by the Client
+func WithHeaders(headers http.Header) Option {
+	return func(c *Client) {
+		if c.Headers == nil {
+			c.Headers = http.Header{}
+		}
+		for name, values := range headers {
+			for _, value := range values {
+				c.Headers.Add(name, value)
+			}
+		}
+	}
+}
+
+// WithCache remembers the clients fetched by GetOAuth2Client for ttl. A ttl
+// of 0 disables the cache.
+func WithCache(ttl time.Duration) Option {
+	return func(c *Client) {
+		c.Cache = nil
+		if ttl > 0 {
+			c.Cache = NewClientCache(ttl)
+		}
+	}
+}
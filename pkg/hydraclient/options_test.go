@@ -0,0 +1,135 @@
+package hydraclient_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/ory/hydra-maester/pkg/hydraclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+func TestNew(t *testing.T) {
+
+	t.Run("case=defaults", func(t *testing.T) {
+		c := hydraclient.New(url.URL{Scheme: schemeHTTP, Host: "hydra:4445", Path: clientsEndpoint})
+
+		assert.Equal(t, "http://hydra:4445/clients", c.HydraURL.String())
+		require.NotNil(t, c.HTTPClient)
+		assert.NotSame(t, http.DefaultClient, c.HTTPClient)
+		assert.Empty(t, c.ForwardedProto)
+		assert.Empty(t, c.Authorization)
+		assert.Nil(t, c.Cache)
+	})
+
+	t.Run("case=options", func(t *testing.T) {
+		httpClient := &http.Client{Timeout: time.Second}
+		c := hydraclient.New(url.URL{Scheme: schemeHTTP},
+			hydraclient.WithHTTPClient(httpClient),
+			hydraclient.WithForwardedProto("https"),
+			hydraclient.WithAuthorization("Bearer token"),
+			hydraclient.WithCache(time.Minute),
+		)
+
+		assert.Same(t, httpClient, c.HTTPClient)
+		assert.Equal(t, "https", c.ForwardedProto)
+		assert.Equal(t, "Bearer token", c.Authorization)
+		assert.NotNil(t, c.Cache)
+	})
+
+	t.Run("case=disabled options", func(t *testing.T) {
+		c := hydraclient.New(url.URL{Scheme: schemeHTTP},
+			hydraclient.WithForwardedProto("off"),
+			hydraclient.WithCache(0),
+		)
+
+		assert.Empty(t, c.ForwardedProto)
+		assert.Nil(t, c.Cache)
+	})
+
+	t.Run("case=requests carry the options", func(t *testing.T) {
+		var header http.Header
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			header = req.Header
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[]`))
+		}))
+		defer srv.Close()
+		u, err := url.Parse(srv.URL + clientsEndpoint)
+		require.NoError(t, err)
+
+		c := hydraclient.New(*u,
+			hydraclient.WithForwardedProto("https"),
+			hydraclient.WithAuthorization("Bearer token"),
+		)
+		_, err = c.ListOAuth2Client()
+		require.NoError(t, err)
+
+		assert.Equal(t, "https", header.Get("X-Forwarded-Proto"))
+		assert.Equal(t, "Bearer token", header.Get("Authorization"))
+	})
+
+	t.Run("case=requests carry the custom headers", func(t *testing.T) {
+		var header http.Header
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			header = req.Header
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[]`))
+		}))
+		defer srv.Close()
+		u, err := url.Parse(srv.URL + clientsEndpoint)
+		require.NoError(t, err)
+
+		c := hydraclient.New(*u,
+			hydraclient.WithHeaders(http.Header{"X-Tenant-Id": {"acme"}, "Accept": {"text/plain"}}),
+			hydraclient.WithHeaders(http.Header{"X-Route": {"hydra"}}),
+		)
+		_, err = c.ListOAuth2Client()
+		require.NoError(t, err)
+
+		assert.Equal(t, "acme", header.Get("X-Tenant-Id"))
+		assert.Equal(t, "hydra", header.Get("X-Route"))
+		// the headers of the Client win
+		assert.Equal(t, "application/json", header.Get("Accept"))
+	})
+
+	t.Run("case=requests carry the access token", func(t *testing.T) {
+		var header http.Header
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			header = req.Header
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[]`))
+		}))
+		defer srv.Close()
+		u, err := url.Parse(srv.URL + clientsEndpoint)
+		require.NoError(t, err)
+
+		ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "access-token"})
+		_, err = hydraclient.New(*u, hydraclient.WithTokenSource(ts)).ListOAuth2Client()
+		require.NoError(t, err)
+
+		assert.Equal(t, "Bearer access-token", header.Get("Authorization"))
+	})
+
+	t.Run("case=requests carry the API key", func(t *testing.T) {
+		var header http.Header
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			header = req.Header
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[]`))
+		}))
+		defer srv.Close()
+		u, err := url.Parse(srv.URL + clientsEndpoint)
+		require.NoError(t, err)
+
+		_, err = hydraclient.New(*u, hydraclient.WithAPIKey("X-API-Key", "key")).ListOAuth2Client()
+		require.NoError(t, err)
+
+		assert.Equal(t, "key", header.Get("X-API-Key"))
+		assert.Empty(t, header.Get("Authorization"))
+	})
+}
@@ -0,0 +1,179 @@
+package hydraclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+)
+
+// RotatingCertificate provides the client certificate and trust bundle used
+// for (mutual) TLS with ORY Hydra from files that are rotated externally,
+// such as the X.509-SVIDs and bundles the SPIFFE helper writes from the
+// SPIFFE workload API, or the Secrets of cert-manager mounted by the
+// kubelet. The files are reloaded whenever they change.
+type RotatingCertificate struct {
+	// CertFile and KeyFile, if set, hold the client certificate presented to
+	// ORY Hydra. They are set together.
+	CertFile string
+	KeyFile  string
+	// CAFile, if set, holds the CA certificates trusted for ORY Hydra's
+	// certificate, instead of the ones of the system
+	CAFile string
+
+	// SPIFFEID, if set, is the SPIFFE ID ORY Hydra's certificate must carry.
+	// It replaces the verification of the host name, as X.509-SVIDs usually
+	// don't carry DNS names.
+	SPIFFEID string
+
+	// InsecureSkipVerify accepts any certificate presented by ORY Hydra,
+	// leaving the connection open to man-in-the-middle attacks. It is only
+	// meant for tests.
+	InsecureSkipVerify bool
+
+	mu     sync.Mutex
+	loaded bool
+	stamp  string
+	cert   *tls.Certificate
+	roots  *x509.CertPool
+}
+
+// TLSConfig returns a TLS configuration for connections to serverName
+func (rc *RotatingCertificate) TLSConfig(serverName string) (*tls.Config, error) {
+	if (rc.CertFile == "") != (rc.KeyFile == "") {
+		return nil, errors.New("the client certificate and its key must be set together")
+	}
+	if _, _, err := rc.load(); err != nil {
+		return nil, err
+	}
+
+	config := &tls.Config{
+		ServerName: serverName,
+		// the chain is verified by VerifyPeerCertificate against the current
+		// trust bundle, which can't be swapped in RootCAs
+		InsecureSkipVerify: true,
+	}
+	if !rc.InsecureSkipVerify {
+		config.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			return rc.verify(serverName, rawCerts)
+		}
+	}
+	if rc.CertFile != "" {
+		config.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, _, err := rc.load()
+			return cert, err
+		}
+	}
+	return config, nil
+}
+
+func (rc *RotatingCertificate) verify(serverName string, rawCerts [][]byte) error {
+	_, roots, err := rc.load()
+	if err != nil {
+		return err
+	}
+	if len(rawCerts) == 0 {
+		return errors.New("ORY Hydra presented no certificate")
+	}
+
+	certs := make([]*x509.Certificate, len(rawCerts))
+	for i, raw := range rawCerts {
+		if certs[i], err = x509.ParseCertificate(raw); err != nil {
+			return err
+		}
+	}
+
+	opts := x509.VerifyOptions{Roots: roots, Intermediates: x509.NewCertPool()}
+	for _, cert := range certs[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	if rc.SPIFFEID == "" {
+		opts.DNSName = serverName
+	}
+	if _, err := certs[0].Verify(opts); err != nil {
+		return err
+	}
+
+	if rc.SPIFFEID != "" {
+		for _, uri := range certs[0].URIs {
+			if uri.String() == rc.SPIFFEID {
+				return nil
+			}
+		}
+		return fmt.Errorf("ORY Hydra's certificate does not carry the SPIFFE ID %s", rc.SPIFFEID)
+	}
+	return nil
+}
+
+// load returns the current certificate and trust bundle, reloading them if
+// any of the files has changed since they were last loaded. They are nil if
+// their files are not set, the trust bundle standing for the CA
+// certificates of the system. If the files can't be loaded once they have
+// been, e.g. because the certificate has been rotated but not its key yet,
+// the previous certificate and trust bundle are kept until they can.
+func (rc *RotatingCertificate) load() (*tls.Certificate, *x509.CertPool, error) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	stamp, err := fileStamp(rc.CertFile, rc.KeyFile, rc.CAFile)
+	if err != nil {
+		return rc.previous(err)
+	}
+	if rc.loaded && stamp == rc.stamp {
+		return rc.cert, rc.roots, nil
+	}
+
+	var cert *tls.Certificate
+	if rc.CertFile != "" {
+		pair, err := tls.LoadX509KeyPair(rc.CertFile, rc.KeyFile)
+		if err != nil {
+			return rc.previous(err)
+		}
+		cert = &pair
+	}
+	var roots *x509.CertPool
+	if rc.CAFile != "" {
+		bundle, err := ioutil.ReadFile(rc.CAFile)
+		if err != nil {
+			return rc.previous(err)
+		}
+		roots = x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(bundle) {
+			return rc.previous(fmt.Errorf("no certificates found in %s", rc.CAFile))
+		}
+	}
+
+	rc.cert, rc.roots, rc.stamp, rc.loaded = cert, roots, stamp, true
+	return rc.cert, rc.roots, nil
+}
+
+// previous returns the certificate and trust bundle loaded last, or err if
+// none have been loaded yet
+func (rc *RotatingCertificate) previous(err error) (*tls.Certificate, *x509.CertPool, error) {
+	if !rc.loaded {
+		return nil, nil, err
+	}
+	return rc.cert, rc.roots, nil
+}
+
+// fileStamp returns the modification times and sizes of the files which are
+// set, which change whenever any of them is written or replaced, e.g. by
+// the kubelet updating a mounted Secret
+func fileStamp(files ...string) (string, error) {
+	var stamp strings.Builder
+	for _, file := range files {
+		if file == "" {
+			continue
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&stamp, "%d:%d;", info.ModTime().UnixNano(), info.Size())
+	}
+	return stamp.String(), nil
+}
@@ -0,0 +1,226 @@
+package hydraclient_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ory/hydra-maester/pkg/hydraclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	raw, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(raw)
+	require.NoError(t, err)
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: raw})}
+}
+
+// issue returns a PEM encoded certificate and key for the given SPIFFE ID
+func (ca *testCA) issue(t *testing.T, serial int64, spiffeID string, usage x509.ExtKeyUsage) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	id, err := url.Parse(spiffeID)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		URIs:         []*url.URL{id},
+	}
+	raw, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyRaw, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: raw}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyRaw})
+}
+
+func TestRotatingCertificate(t *testing.T) {
+
+	ca := newTestCA(t)
+	serverCert, serverKey := ca.issue(t, 2, "spiffe://example.org/hydra", x509.ExtKeyUsageServerAuth)
+	serverPair, err := tls.X509KeyPair(serverCert, serverKey)
+	require.NoError(t, err)
+
+	var presented *big.Int
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		presented = req.TLS.PeerCertificates[0].SerialNumber
+	}))
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverPair},
+		ClientAuth:   tls.RequireAnyClientCert,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "svid")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeSVID := func(serial int64, modTime time.Time) {
+		cert, key := ca.issue(t, serial, "spiffe://example.org/hydra-maester", x509.ExtKeyUsageClientAuth)
+		for name, content := range map[string][]byte{"svid.pem": cert, "svid_key.pem": key, "bundle.pem": ca.pem} {
+			file := filepath.Join(dir, name)
+			require.NoError(t, ioutil.WriteFile(file, content, 0600))
+			require.NoError(t, os.Chtimes(file, modTime, modTime))
+		}
+	}
+
+	get := func(rc *hydraclient.RotatingCertificate) error {
+		config, err := rc.TLSConfig("127.0.0.1")
+		if err != nil {
+			return err
+		}
+		c := &http.Client{Transport: &http.Transport{TLSClientConfig: config, DisableKeepAlives: true}}
+		res, err := c.Get(server.URL)
+		if err == nil {
+			res.Body.Close()
+		}
+		return err
+	}
+
+	newRotatingCertificate := func(spiffeID string) *hydraclient.RotatingCertificate {
+		return &hydraclient.RotatingCertificate{
+			CertFile: filepath.Join(dir, "svid.pem"),
+			KeyFile:  filepath.Join(dir, "svid_key.pem"),
+			CAFile:   filepath.Join(dir, "bundle.pem"),
+			SPIFFEID: spiffeID,
+		}
+	}
+
+	t.Run("case=presents the client certificate", func(t *testing.T) {
+		writeSVID(10, time.Now().Add(-time.Minute))
+		require.NoError(t, get(newRotatingCertificate("spiffe://example.org/hydra")))
+		assert.Equal(t, big.NewInt(10), presented)
+	})
+
+	t.Run("case=reloads rotated certificates", func(t *testing.T) {
+		rc := newRotatingCertificate("spiffe://example.org/hydra")
+		writeSVID(20, time.Now().Add(-time.Minute))
+		require.NoError(t, get(rc))
+		assert.Equal(t, big.NewInt(20), presented)
+
+		writeSVID(21, time.Now())
+		require.NoError(t, get(rc))
+		assert.Equal(t, big.NewInt(21), presented)
+	})
+
+	t.Run("case=keeps the previous certificate while the rotation is incomplete", func(t *testing.T) {
+		rc := newRotatingCertificate("spiffe://example.org/hydra")
+		writeSVID(25, time.Now().Add(-time.Minute))
+		require.NoError(t, get(rc))
+		assert.Equal(t, big.NewInt(25), presented)
+
+		// the certificate is rotated before its key
+		cert, _ := ca.issue(t, 26, "spiffe://example.org/hydra-maester", x509.ExtKeyUsageClientAuth)
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "svid.pem"), cert, 0600))
+		require.NoError(t, get(rc))
+		assert.Equal(t, big.NewInt(25), presented)
+
+		writeSVID(27, time.Now())
+		require.NoError(t, get(rc))
+		assert.Equal(t, big.NewInt(27), presented)
+	})
+
+	t.Run("case=rejects an unexpected SPIFFE ID", func(t *testing.T) {
+		writeSVID(30, time.Now())
+		assert.Error(t, get(newRotatingCertificate("spiffe://example.org/other")))
+	})
+
+	t.Run("case=rejects an untrusted server", func(t *testing.T) {
+		writeSVID(40, time.Now())
+		other := newTestCA(t)
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "bundle.pem"), other.pem, 0600))
+		assert.Error(t, get(newRotatingCertificate("spiffe://example.org/hydra")))
+	})
+}
+
+func TestRotatingCertificateWithoutClientCertificate(t *testing.T) {
+
+	ca := newTestCA(t)
+	serverCert, serverKey := ca.issue(t, 2, "spiffe://example.org/hydra", x509.ExtKeyUsageServerAuth)
+	serverPair, err := tls.X509KeyPair(serverCert, serverKey)
+	require.NoError(t, err)
+
+	var presented bool
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		presented = len(req.TLS.PeerCertificates) > 0
+	}))
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverPair},
+		ClientAuth:   tls.RequestClientCert,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "bundle")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	bundle := filepath.Join(dir, "bundle.pem")
+	require.NoError(t, ioutil.WriteFile(bundle, ca.pem, 0600))
+
+	get := func(rc *hydraclient.RotatingCertificate) error {
+		config, err := rc.TLSConfig("127.0.0.1")
+		if err != nil {
+			return err
+		}
+		c := &http.Client{Transport: &http.Transport{TLSClientConfig: config, DisableKeepAlives: true}}
+		res, err := c.Get(server.URL)
+		if err == nil {
+			res.Body.Close()
+		}
+		return err
+	}
+
+	t.Run("case=trusts the CA bundle", func(t *testing.T) {
+		presented = true
+		require.NoError(t, get(&hydraclient.RotatingCertificate{CAFile: bundle, SPIFFEID: "spiffe://example.org/hydra"}))
+		assert.False(t, presented)
+	})
+
+	t.Run("case=trusts the CA certificates of the system by default", func(t *testing.T) {
+		assert.Error(t, get(&hydraclient.RotatingCertificate{SPIFFEID: "spiffe://example.org/hydra"}))
+	})
+
+	t.Run("case=skips the verification if insecure", func(t *testing.T) {
+		require.NoError(t, get(&hydraclient.RotatingCertificate{InsecureSkipVerify: true}))
+	})
+
+	t.Run("case=rejects a client certificate without key", func(t *testing.T) {
+		assert.Error(t, get(&hydraclient.RotatingCertificate{CertFile: bundle, CAFile: bundle}))
+	})
+}
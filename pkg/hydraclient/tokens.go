@@ -0,0 +1,33 @@
+package hydraclient
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// tokensPath is the admin endpoint deleting the access tokens of a client
+const tokensPath = "/oauth2/tokens"
+
+// DeleteOAuth2Tokens deletes the access tokens issued to the client with
+// the given ID, so that ORY Hydra rejects them from now on
+func (c *Client) DeleteOAuth2Tokens(clientID string) error {
+
+	u := c.adminURL(tokensPath)
+	u.RawQuery = url.Values{"client_id": {clientID}}.Encode()
+	req, err := c.newRequestURL(http.MethodDelete, u, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.do(req, nil)
+	if err != nil {
+		return err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusNoContent, http.StatusNotFound:
+		return nil
+	default:
+		return newStatusError(req, resp)
+	}
+}
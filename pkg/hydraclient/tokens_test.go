@@ -0,0 +1,83 @@
+package hydraclient_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/ory/hydra-maester/pkg/hydraclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteOAuth2Tokens(t *testing.T) {
+
+	assert := assert.New(t)
+
+	c := hydraclient.Client{
+		HTTPClient: &http.Client{},
+		HydraURL:   url.URL{Scheme: schemeHTTP},
+	}
+
+	for d, tc := range map[string]server{
+		"deleting the tokens of an existing client": {
+			http.StatusNoContent,
+			"",
+			nil,
+		},
+		"deleting the tokens of a missing client": {
+			http.StatusNotFound,
+			statusNotFoundBody,
+			nil,
+		},
+		"internal server error when requesting": {
+			http.StatusInternalServerError,
+			statusInternalServerErrorBody,
+			errors.New("http request returned unexpected status code"),
+		},
+	} {
+		t.Run(fmt.Sprintf("case/%s", d), func(t *testing.T) {
+
+			//given
+			h := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				assert.Equal("/oauth2/tokens", req.URL.Path)
+				assert.Equal("test-id", req.URL.Query().Get("client_id"))
+				assert.Equal(http.MethodDelete, req.Method)
+				w.WriteHeader(tc.statusCode)
+				w.Write([]byte(tc.respBody))
+			})
+			runServer(&c, h)
+
+			//when
+			err := c.DeleteOAuth2Tokens("test-id")
+
+			//then
+			if tc.err != nil {
+				require.Error(t, err)
+				assert.Contains(err.Error(), tc.err.Error())
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestDeleteOAuth2TokensHydraV2(t *testing.T) {
+
+	var path string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		path = req.URL.Path
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer s.Close()
+	serverURL, err := url.Parse(s.URL)
+	require.NoError(t, err)
+
+	c := hydraclient.New(*serverURL.ResolveReference(&url.URL{Path: hydraclient.ClientsEndpointV2}))
+
+	require.NoError(t, c.DeleteOAuth2Tokens("test-id"))
+	assert.Equal(t, "/admin/oauth2/tokens", path)
+}
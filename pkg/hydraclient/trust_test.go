@@ -0,0 +1,181 @@
+package hydraclient_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/ory/hydra-maester/pkg/hydraclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testIssuerID   = "issuer-1"
+	testIssuerBody = `{"id":"issuer-1","issuer":"https://issuer.example.com","subject":"alice","scope":["read"],"expires_at":"2030-01-01T00:00:00Z","public_key":{"set":"https://issuer.example.com","kid":"key-1"}}`
+	testIssuerJWK  = `{"kty":"RSA","kid":"key-1","alg":"RS256","use":"sig","n":"n","e":"AQAB"}`
+)
+
+func TestTrustedJwtGrantIssuers(t *testing.T) {
+
+	assert := assert.New(t)
+
+	c := hydraclient.Client{
+		HTTPClient: &http.Client{},
+		HydraURL:   url.URL{Scheme: schemeHTTP},
+	}
+
+	t.Run("method=get", func(t *testing.T) {
+
+		for d, tc := range map[string]server{
+			"getting existing issuer": {
+				http.StatusOK,
+				testIssuerBody,
+				nil,
+			},
+			"getting missing issuer": {
+				http.StatusNotFound,
+				statusNotFoundBody,
+				nil,
+			},
+			"internal server error when requesting": {
+				http.StatusInternalServerError,
+				statusInternalServerErrorBody,
+				errors.New("http request returned unexpected status code"),
+			},
+		} {
+			t.Run(fmt.Sprintf("case/%s", d), func(t *testing.T) {
+
+				//given
+				h := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+					assert.Equal("/trust/grants/jwt-bearer/issuers/"+testIssuerID, req.URL.Path)
+					assert.Equal(http.MethodGet, req.Method)
+					w.WriteHeader(tc.statusCode)
+					w.Write([]byte(tc.respBody))
+				})
+				runServer(&c, h)
+
+				//when
+				issuer, found, err := c.GetTrustedJwtGrantIssuer(testIssuerID)
+
+				//then
+				if tc.err != nil {
+					require.Error(t, err)
+					assert.Contains(err.Error(), tc.err.Error())
+					return
+				}
+				require.NoError(t, err)
+				assert.Equal(tc.statusCode == http.StatusOK, found)
+				if found {
+					assert.Equal(testIssuerID, issuer.ID)
+					assert.Equal("alice", issuer.Subject)
+					require.NotNil(t, issuer.PublicKey)
+					assert.Equal("key-1", issuer.PublicKey.KeyID)
+				}
+			})
+		}
+	})
+
+	t.Run("method=create", func(t *testing.T) {
+
+		for d, tc := range map[string]server{
+			"trusting an issuer": {
+				http.StatusCreated,
+				testIssuerBody,
+				nil,
+			},
+			"conflicting issuer": {
+				http.StatusConflict,
+				`{"error":"Unable to insert or update resource because a resource with that value exists already"}`,
+				errors.New("http request returned unexpected status code"),
+			},
+		} {
+			t.Run(fmt.Sprintf("case/%s", d), func(t *testing.T) {
+
+				//given
+				h := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+					assert.Equal("/trust/grants/jwt-bearer/issuers", req.URL.Path)
+					assert.Equal(http.MethodPost, req.Method)
+
+					var body map[string]json.RawMessage
+					require.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+					assert.JSONEq(testIssuerJWK, string(body["jwk"]))
+					assert.JSONEq(`[]`, string(body["scope"]))
+					assert.JSONEq(`"2030-01-01T00:00:00Z"`, string(body["expires_at"]))
+
+					w.WriteHeader(tc.statusCode)
+					w.Write([]byte(tc.respBody))
+				})
+				runServer(&c, h)
+
+				var jwk hydraclient.JSONWebKey
+				require.NoError(t, json.Unmarshal([]byte(testIssuerJWK), &jwk))
+
+				//when
+				issuer, err := c.CreateTrustedJwtGrantIssuer(&hydraclient.TrustedJwtGrantIssuer{
+					Issuer:     "https://issuer.example.com",
+					Subject:    "alice",
+					ExpiresAt:  time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+					JSONWebKey: jwk,
+				})
+
+				//then
+				if tc.err != nil {
+					require.Error(t, err)
+					assert.Contains(err.Error(), tc.err.Error())
+					return
+				}
+				require.NoError(t, err)
+				assert.Equal(testIssuerID, issuer.ID)
+			})
+		}
+	})
+
+	t.Run("method=delete", func(t *testing.T) {
+
+		for d, tc := range map[string]server{
+			"deleting existing issuer": {
+				http.StatusNoContent,
+				"",
+				nil,
+			},
+			"deleting missing issuer": {
+				http.StatusNotFound,
+				statusNotFoundBody,
+				nil,
+			},
+			"internal server error when requesting": {
+				http.StatusInternalServerError,
+				statusInternalServerErrorBody,
+				errors.New("http request returned unexpected status code"),
+			},
+		} {
+			t.Run(fmt.Sprintf("case/%s", d), func(t *testing.T) {
+
+				//given
+				h := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+					assert.Equal("/trust/grants/jwt-bearer/issuers/"+testIssuerID, req.URL.Path)
+					assert.Equal(http.MethodDelete, req.Method)
+					w.WriteHeader(tc.statusCode)
+					w.Write([]byte(tc.respBody))
+				})
+				runServer(&c, h)
+
+				//when
+				err := c.DeleteTrustedJwtGrantIssuer(testIssuerID)
+
+				//then
+				if tc.err != nil {
+					require.Error(t, err)
+					assert.Contains(err.Error(), tc.err.Error())
+					return
+				}
+				require.NoError(t, err)
+			})
+		}
+	})
+}
@@ -0,0 +1,293 @@
+package hydraclient
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"k8s.io/utils/pointer"
+)
+
+const (
+	// MetadataGCExemptKey is the metadata property marking a client as exempt
+	// from garbage collection
+	MetadataGCExemptKey = "hydra-maester.ory.sh/gc-exempt"
+	// MetadataConsentHintsKey is the metadata property holding the consent
+	// hints of a client
+	MetadataConsentHintsKey = "hydra-maester.ory.sh/consent-hints"
+	// MetadataManagedKey is the metadata property marking a client as
+	// created by the controller
+	MetadataManagedKey = "hydra-maester.ory.sh/managed"
+)
+
+// OAuth2ClientJSON represents an OAuth2 client digestible by ORY Hydra
+type OAuth2ClientJSON struct {
+	ClientName                  string          `json:"client_name,omitempty"`
+	ClientID                    *string         `json:"client_id,omitempty"`
+	Secret                      *string         `json:"client_secret,omitempty"`
+	GrantTypes                  []string        `json:"grant_types"`
+	RedirectURIs                []string        `json:"redirect_uris,omitempty"`
+	PostLogoutRedirectURIs      []string        `json:"post_logout_redirect_uris,omitempty"`
+	AllowedCorsOrigins          []string        `json:"allowed_cors_origins,omitempty"`
+	ResponseTypes               []string        `json:"response_types,omitempty"`
+	Audience                    []string        `json:"audience,omitempty"`
+	Scope                       string          `json:"scope"`
+	Owner                       string          `json:"owner"`
+	TokenEndpointAuthMethod     string          `json:"token_endpoint_auth_method,omitempty"`
+	TokenEndpointAuthSigningAlg string          `json:"token_endpoint_auth_signing_alg,omitempty"`
+	AccessTokenStrategy         string          `json:"access_token_strategy,omitempty"`
+	Metadata                    json.RawMessage `json:"metadata,omitempty"`
+	SkipConsent                 bool            `json:"skip_consent,omitempty"`
+	SkipLogoutConsent           bool            `json:"skip_logout_consent,omitempty"`
+	ClientSecretExpiresAt       int64           `json:"client_secret_expires_at,omitempty"`
+	UpdatedAt                   string          `json:"updated_at,omitempty"`
+
+	// Extra holds client properties not modeled above. They are flattened
+	// into the JSON representation and never override modeled properties.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// oauth2ClientJSON has the fields of OAuth2ClientJSON without its methods
+type oauth2ClientJSON OAuth2ClientJSON
+
+// modeledProperties holds the JSON names of the modeled client properties
+var modeledProperties = func() map[string]struct{} {
+	properties := map[string]struct{}{}
+	t := reflect.TypeOf(oauth2ClientJSON{})
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			properties[name] = struct{}{}
+		}
+	}
+	return properties
+}()
+
+// MarshalJSON marshals the modeled properties along with the extra ones
+func (oj OAuth2ClientJSON) MarshalJSON() ([]byte, error) {
+	raw, err := json.Marshal(oauth2ClientJSON(oj))
+	if err != nil || len(oj.Extra) == 0 {
+		return raw, err
+	}
+
+	var properties map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &properties); err != nil {
+		return nil, err
+	}
+	for name, value := range oj.Extra {
+		if _, ok := modeledProperties[name]; !ok {
+			properties[name] = value
+		}
+	}
+	return json.Marshal(properties)
+}
+
+// UnmarshalJSON unmarshals the modeled properties and keeps all other ones
+// in Extra
+func (oj *OAuth2ClientJSON) UnmarshalJSON(data []byte) error {
+	var modeled oauth2ClientJSON
+	if err := json.Unmarshal(data, &modeled); err != nil {
+		return err
+	}
+
+	var properties map[string]json.RawMessage
+	if err := json.Unmarshal(data, &properties); err != nil {
+		return err
+	}
+	for name := range modeledProperties {
+		delete(properties, name)
+	}
+	if len(properties) > 0 {
+		modeled.Extra = properties
+	}
+
+	*oj = OAuth2ClientJSON(modeled)
+	return nil
+}
+
+// Oauth2ClientCredentials represents client ID and password fetched from a Kubernetes secret
+type Oauth2ClientCredentials struct {
+	ID       []byte
+	Password []byte
+}
+
+func (oj *OAuth2ClientJSON) WithCredentials(credentials *Oauth2ClientCredentials) *OAuth2ClientJSON {
+	oj.ClientID = pointer.StringPtr(string(credentials.ID))
+	if credentials.Password != nil {
+		oj.Secret = pointer.StringPtr(string(credentials.Password))
+	}
+	return oj
+}
+
+// IsGCExempt reports whether the client is marked as exempt from garbage collection
+func (oj *OAuth2ClientJSON) IsGCExempt() bool {
+	return oj.metadataFlag(MetadataGCExemptKey)
+}
+
+// IsManaged reports whether the client is marked as created by the controller
+func (oj *OAuth2ClientJSON) IsManaged() bool {
+	return oj.metadataFlag(MetadataManagedKey)
+}
+
+func (oj *OAuth2ClientJSON) metadataFlag(key string) bool {
+	var metadata map[string]interface{}
+	if err := json.Unmarshal(oj.Metadata, &metadata); err != nil {
+		return false
+	}
+	flag, _ := metadata[key].(bool)
+	return flag
+}
+
+// SetGCExempt marks the client as exempt from garbage collection, keeping
+// its other metadata. Metadata which is not a JSON object is replaced.
+func (oj *OAuth2ClientJSON) SetGCExempt() error {
+	var metadata map[string]json.RawMessage
+	if err := json.Unmarshal(oj.Metadata, &metadata); err != nil || metadata == nil {
+		metadata = map[string]json.RawMessage{}
+	}
+	metadata[MetadataGCExemptKey] = json.RawMessage("true")
+
+	raw, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+	oj.Metadata = raw
+	return nil
+}
+
+// WithFieldsFrom replaces the given properties, identified by their JSON
+// name, with their values in other
+func (oj *OAuth2ClientJSON) WithFieldsFrom(other *OAuth2ClientJSON, fields []string) (*OAuth2ClientJSON, error) {
+	if len(fields) == 0 || other == nil {
+		return oj, nil
+	}
+
+	var target, source map[string]json.RawMessage
+	if err := remarshal(oj, &target); err != nil {
+		return nil, err
+	}
+	if err := remarshal(other, &source); err != nil {
+		return nil, err
+	}
+
+	for _, field := range fields {
+		if value, ok := source[field]; ok {
+			target[field] = value
+		} else {
+			delete(target, field)
+		}
+	}
+
+	var merged OAuth2ClientJSON
+	if err := remarshal(target, &merged); err != nil {
+		return nil, err
+	}
+	return &merged, nil
+}
+
+// MergeInto returns a copy of base with the properties set in oj applied on
+// top of it. Properties with empty values in oj are considered unset.
+func (oj *OAuth2ClientJSON) MergeInto(base *OAuth2ClientJSON) (*OAuth2ClientJSON, error) {
+	if base == nil {
+		return oj, nil
+	}
+
+	var target, source map[string]json.RawMessage
+	if err := remarshal(base, &target); err != nil {
+		return nil, err
+	}
+	if err := remarshal(oj, &source); err != nil {
+		return nil, err
+	}
+
+	for field, value := range source {
+		if !isEmptyJSON(value) {
+			target[field] = value
+		}
+	}
+
+	var merged OAuth2ClientJSON
+	if err := remarshal(target, &merged); err != nil {
+		return nil, err
+	}
+	return &merged, nil
+}
+
+// Desired returns the client to write to ORY Hydra to apply desired to
+// current, the client registered in ORY Hydra or nil. With merge, the
+// properties unset in desired keep their current values. The properties in
+// unmanagedFields, by their JSON name, always keep them.
+func Desired(desired, current *OAuth2ClientJSON, merge bool, unmanagedFields []string) (*OAuth2ClientJSON, error) {
+	if merge {
+		var err error
+		if desired, err = desired.MergeInto(current); err != nil {
+			return nil, err
+		}
+	}
+	return desired.WithFieldsFrom(current, unmanagedFields)
+}
+
+// serverManagedProperties are client properties ORY Hydra either manages
+// itself or does not return, so they are not compared by DiffersFrom
+var serverManagedProperties = map[string]struct{}{
+	"client_secret": {},
+	"created_at":    {},
+	"updated_at":    {},
+}
+
+// DiffersFrom reports whether applying oj would change other. Only the
+// properties set in oj are compared, empty values matching unset ones.
+func (oj *OAuth2ClientJSON) DiffersFrom(other *OAuth2ClientJSON) (bool, error) {
+	if other == nil {
+		return true, nil
+	}
+
+	var desired, current map[string]json.RawMessage
+	if err := remarshal(oj, &desired); err != nil {
+		return false, err
+	}
+	if err := remarshal(other, &current); err != nil {
+		return false, err
+	}
+
+	for field, value := range desired {
+		if _, ok := serverManagedProperties[field]; ok {
+			continue
+		}
+		currentValue, ok := current[field]
+		if !ok {
+			if isEmptyJSON(value) {
+				continue
+			}
+			return true, nil
+		}
+
+		var a, b interface{}
+		if err := json.Unmarshal(value, &a); err != nil {
+			return false, err
+		}
+		if err := json.Unmarshal(currentValue, &b); err != nil {
+			return false, err
+		}
+		if !reflect.DeepEqual(a, b) && !(isEmptyJSON(value) && isEmptyJSON(currentValue)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func isEmptyJSON(value json.RawMessage) bool {
+	switch string(value) {
+	case "null", `""`, "[]", "{}", "false":
+		return true
+	}
+	return false
+}
+
+func remarshal(in, out interface{}) error {
+	raw, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, out)
+}
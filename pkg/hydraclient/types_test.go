@@ -0,0 +1,245 @@
+package hydraclient_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ory/hydra-maester/pkg/hydraclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/pointer"
+)
+
+func TestOAuth2ClientJSON(t *testing.T) {
+
+	t.Run("method=WithFieldsFrom", func(t *testing.T) {
+
+		desired := &hydraclient.OAuth2ClientJSON{
+			Scope:        "a b",
+			GrantTypes:   []string{"client_credentials"},
+			RedirectURIs: []string{"https://desired"},
+			Owner:        "test-name",
+		}
+		fetched := &hydraclient.OAuth2ClientJSON{
+			Scope:      "c",
+			GrantTypes: []string{"client_credentials"},
+			Audience:   []string{"audience-a"},
+			Owner:      "test-name",
+		}
+
+		t.Run("case=no fields", func(t *testing.T) {
+			merged, err := desired.WithFieldsFrom(fetched, nil)
+			require.NoError(t, err)
+			assert.Equal(t, desired, merged)
+		})
+
+		t.Run("case=preserves fetched values", func(t *testing.T) {
+			merged, err := desired.WithFieldsFrom(fetched, []string{"scope", "audience", "redirect_uris"})
+			require.NoError(t, err)
+			assert.Equal(t, "c", merged.Scope)
+			assert.Equal(t, []string{"audience-a"}, merged.Audience)
+			assert.Empty(t, merged.RedirectURIs)
+			assert.Equal(t, desired.GrantTypes, merged.GrantTypes)
+		})
+	})
+
+	t.Run("method=MergeInto", func(t *testing.T) {
+
+		desired := &hydraclient.OAuth2ClientJSON{
+			Scope:      "a b",
+			GrantTypes: []string{"client_credentials"},
+			Owner:      "test-name",
+		}
+		fetched := &hydraclient.OAuth2ClientJSON{
+			Scope:         "c",
+			GrantTypes:    []string{"authorization_code"},
+			RedirectURIs:  []string{"https://fetched"},
+			Audience:      []string{"audience-a"},
+			Owner:         "test-name",
+			SkipConsent:   true,
+			ResponseTypes: []string{"code"},
+		}
+
+		merged, err := desired.MergeInto(fetched)
+		require.NoError(t, err)
+		assert.Equal(t, "a b", merged.Scope)
+		assert.Equal(t, []string{"client_credentials"}, merged.GrantTypes)
+		assert.Equal(t, []string{"https://fetched"}, merged.RedirectURIs)
+		assert.Equal(t, []string{"audience-a"}, merged.Audience)
+		assert.Equal(t, []string{"code"}, merged.ResponseTypes)
+		assert.True(t, merged.SkipConsent)
+	})
+
+	t.Run("function=Desired", func(t *testing.T) {
+
+		desired := &hydraclient.OAuth2ClientJSON{
+			Scope:      "a b",
+			GrantTypes: []string{"client_credentials"},
+			Owner:      "test-name",
+		}
+		current := &hydraclient.OAuth2ClientJSON{
+			Scope:        "c",
+			GrantTypes:   []string{"authorization_code"},
+			RedirectURIs: []string{"https://current"},
+			Owner:        "test-name",
+		}
+
+		t.Run("case=replace", func(t *testing.T) {
+			result, err := hydraclient.Desired(desired, current, false, []string{"grant_types"})
+			require.NoError(t, err)
+			assert.Equal(t, "a b", result.Scope)
+			assert.Equal(t, []string{"authorization_code"}, result.GrantTypes)
+			assert.Empty(t, result.RedirectURIs)
+		})
+
+		t.Run("case=merge", func(t *testing.T) {
+			result, err := hydraclient.Desired(desired, current, true, []string{"scope"})
+			require.NoError(t, err)
+			assert.Equal(t, "c", result.Scope)
+			assert.Equal(t, []string{"client_credentials"}, result.GrantTypes)
+			assert.Equal(t, []string{"https://current"}, result.RedirectURIs)
+		})
+
+		t.Run("case=not registered", func(t *testing.T) {
+			result, err := hydraclient.Desired(desired, nil, true, []string{"scope"})
+			require.NoError(t, err)
+			assert.Equal(t, desired, result)
+		})
+	})
+
+	t.Run("method=DiffersFrom", func(t *testing.T) {
+
+		fetched := &hydraclient.OAuth2ClientJSON{
+			ClientID:   pointer.StringPtr("id"),
+			Scope:      "a b",
+			GrantTypes: []string{"client_credentials"},
+			Owner:      "test-name",
+			Metadata:   json.RawMessage(`{"a": 1, "b": 2}`),
+			UpdatedAt:  "2019-07-01T00:00:00Z",
+			Extra:      map[string]json.RawMessage{"subject_type": json.RawMessage(`"public"`)},
+		}
+
+		for d, tc := range map[string]struct {
+			desired *hydraclient.OAuth2ClientJSON
+			differs bool
+		}{
+			"same content": {&hydraclient.OAuth2ClientJSON{
+				ClientID:   pointer.StringPtr("id"),
+				Secret:     pointer.StringPtr("secret"),
+				Scope:      "a b",
+				GrantTypes: []string{"client_credentials"},
+				Owner:      "test-name",
+				Metadata:   json.RawMessage(`{"b":2,"a":1}`),
+			}, false},
+			"empty values": {&hydraclient.OAuth2ClientJSON{
+				ClientID:     pointer.StringPtr("id"),
+				Scope:        "a b",
+				GrantTypes:   []string{"client_credentials"},
+				Owner:        "test-name",
+				Metadata:     json.RawMessage(`{"a":1,"b":2}`),
+				RedirectURIs: []string{},
+			}, false},
+			"different scope": {&hydraclient.OAuth2ClientJSON{
+				ClientID:   pointer.StringPtr("id"),
+				Scope:      "a",
+				GrantTypes: []string{"client_credentials"},
+				Owner:      "test-name",
+				Metadata:   json.RawMessage(`{"a":1,"b":2}`),
+			}, true},
+			"new property": {&hydraclient.OAuth2ClientJSON{
+				ClientID:   pointer.StringPtr("id"),
+				Scope:      "a b",
+				GrantTypes: []string{"client_credentials"},
+				Owner:      "test-name",
+				Metadata:   json.RawMessage(`{"a":1,"b":2}`),
+				Audience:   []string{"audience-a"},
+			}, true},
+		} {
+			t.Run("case="+d, func(t *testing.T) {
+				differs, err := tc.desired.DiffersFrom(fetched)
+				require.NoError(t, err)
+				assert.Equal(t, tc.differs, differs)
+			})
+		}
+	})
+
+	t.Run("method=MarshalJSON", func(t *testing.T) {
+
+		o := hydraclient.OAuth2ClientJSON{
+			Scope:      "a",
+			GrantTypes: []string{"client_credentials"},
+			Owner:      "test-name",
+			Extra: map[string]json.RawMessage{
+				"backchannel_logout_uri": json.RawMessage(`"https://logout"`),
+				"owner":                  json.RawMessage(`"overridden"`),
+			},
+		}
+
+		raw, err := json.Marshal(o)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"grant_types":["client_credentials"],"scope":"a","owner":"test-name","backchannel_logout_uri":"https://logout"}`, string(raw))
+	})
+
+	t.Run("method=UnmarshalJSON", func(t *testing.T) {
+
+		var o hydraclient.OAuth2ClientJSON
+		require.NoError(t, json.Unmarshal([]byte(`{"scope":"a","owner":"test-name","backchannel_logout_uri":"https://logout"}`), &o))
+		assert.Equal(t, "a", o.Scope)
+		assert.Equal(t, "test-name", o.Owner)
+		assert.Equal(t, map[string]json.RawMessage{"backchannel_logout_uri": json.RawMessage(`"https://logout"`)}, o.Extra)
+	})
+
+	t.Run("method=IsGCExempt", func(t *testing.T) {
+
+		for d, tc := range map[string]struct {
+			metadata json.RawMessage
+			exempt   bool
+		}{
+			"no metadata":     {nil, false},
+			"without marker":  {json.RawMessage(`{"property1":1}`), false},
+			"with marker":     {json.RawMessage(`{"hydra-maester.ory.sh/gc-exempt":true}`), true},
+			"invalid marker":  {json.RawMessage(`{"hydra-maester.ory.sh/gc-exempt":"yes"}`), false},
+			"non-object JSON": {json.RawMessage(`[1,2]`), false},
+		} {
+			t.Run("case="+d, func(t *testing.T) {
+				o := &hydraclient.OAuth2ClientJSON{Metadata: tc.metadata}
+				assert.Equal(t, tc.exempt, o.IsGCExempt())
+			})
+		}
+	})
+	t.Run("method=SetGCExempt", func(t *testing.T) {
+
+		for d, tc := range map[string]struct {
+			metadata json.RawMessage
+			expected string
+		}{
+			"no metadata":     {nil, `{"hydra-maester.ory.sh/gc-exempt":true}`},
+			"with metadata":   {json.RawMessage(`{"property1":1}`), `{"hydra-maester.ory.sh/gc-exempt":true,"property1":1}`},
+			"already exempt":  {json.RawMessage(`{"hydra-maester.ory.sh/gc-exempt":true}`), `{"hydra-maester.ory.sh/gc-exempt":true}`},
+			"non-object JSON": {json.RawMessage(`[1,2]`), `{"hydra-maester.ory.sh/gc-exempt":true}`},
+		} {
+			t.Run("case="+d, func(t *testing.T) {
+				o := &hydraclient.OAuth2ClientJSON{Metadata: tc.metadata}
+				require.NoError(t, o.SetGCExempt())
+				assert.JSONEq(t, tc.expected, string(o.Metadata))
+				assert.True(t, o.IsGCExempt())
+			})
+		}
+	})
+	t.Run("method=IsManaged", func(t *testing.T) {
+
+		for d, tc := range map[string]struct {
+			metadata json.RawMessage
+			managed  bool
+		}{
+			"no metadata":    {nil, false},
+			"without marker": {json.RawMessage(`{"property1":1}`), false},
+			"with marker":    {json.RawMessage(`{"hydra-maester.ory.sh/managed":true}`), true},
+		} {
+			t.Run("case="+d, func(t *testing.T) {
+				o := &hydraclient.OAuth2ClientJSON{Metadata: tc.metadata}
+				assert.Equal(t, tc.managed, o.IsManaged())
+			})
+		}
+	})
+}
@@ -0,0 +1,81 @@
+package hydraclient
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// ClientsEndpointV1 is the clients endpoint of ORY Hydra 1.x
+	ClientsEndpointV1 = "/clients"
+	// ClientsEndpointV2 is the clients endpoint of ORY Hydra 2.x, which
+	// serves its whole admin API under /admin
+	ClientsEndpointV2 = "/admin/clients"
+)
+
+// ClientsEndpoint returns the clients endpoint of the given version of ORY
+// Hydra, ClientsEndpointV2 from v2.0.0 and ClientsEndpointV1 before
+func ClientsEndpoint(version string) (string, error) {
+	v, err := parseVersion(version)
+	if err != nil {
+		return "", err
+	}
+	if v[0] >= 2 {
+		return ClientsEndpointV2, nil
+	}
+	return ClientsEndpointV1, nil
+}
+
+// IsVersionInRange reports whether version lies within [min, max). Versions
+// are expected in the `vMAJOR.MINOR.PATCH` format used by ORY Hydra releases,
+// pre-release and build suffixes are ignored.
+func IsVersionInRange(version, min, max string) (bool, error) {
+	v, err := parseVersion(version)
+	if err != nil {
+		return false, err
+	}
+	lower, err := parseVersion(min)
+	if err != nil {
+		return false, err
+	}
+	upper, err := parseVersion(max)
+	if err != nil {
+		return false, err
+	}
+	return compareVersions(v, lower) >= 0 && compareVersions(v, upper) < 0, nil
+}
+
+func parseVersion(version string) ([3]int, error) {
+	var parsed [3]int
+
+	trimmed := strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexAny(trimmed, "-+"); i >= 0 {
+		trimmed = trimmed[:i]
+	}
+
+	parts := strings.Split(trimmed, ".")
+	if len(parts) == 0 || len(parts) > 3 {
+		return parsed, fmt.Errorf("invalid version %q", version)
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return parsed, fmt.Errorf("invalid version %q", version)
+		}
+		parsed[i] = n
+	}
+	return parsed, nil
+}
+
+func compareVersions(a, b [3]int) int {
+	for i := range a {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
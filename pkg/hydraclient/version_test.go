@@ -0,0 +1,62 @@
+package hydraclient_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ory/hydra-maester/pkg/hydraclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsVersionInRange(t *testing.T) {
+
+	for _, tc := range []struct {
+		version string
+		inRange bool
+		err     bool
+	}{
+		{"v1.0.0", true, false},
+		{"v1.4.10", true, false},
+		{"1.9.2-alpha.1", true, false},
+		{"v0.11.14", false, false},
+		{"v2.0.0", false, false},
+		{"v1", true, false},
+		{"master", false, true},
+		{"", false, true},
+	} {
+		t.Run(fmt.Sprintf("case=%s", tc.version), func(t *testing.T) {
+			inRange, err := hydraclient.IsVersionInRange(tc.version, "v1.0.0", "v2.0.0")
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.inRange, inRange)
+		})
+	}
+}
+
+func TestClientsEndpoint(t *testing.T) {
+
+	for _, tc := range []struct {
+		version  string
+		endpoint string
+		err      bool
+	}{
+		{"v1.11.10", hydraclient.ClientsEndpointV1, false},
+		{"v2.0.0", hydraclient.ClientsEndpointV2, false},
+		{"v2.2.0-rc.3", hydraclient.ClientsEndpointV2, false},
+		{"master", "", true},
+	} {
+		t.Run(fmt.Sprintf("case=%s", tc.version), func(t *testing.T) {
+			endpoint, err := hydraclient.ClientsEndpoint(tc.version)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.endpoint, endpoint)
+		})
+	}
+}
@@ -0,0 +1,4 @@
+// Package tracing sets up the OpenTelemetry tracing of the controller, and
+// traces the requests of HTTP clients, e.g. the client of ORY Hydra's admin
+// API, as children of the spans of their context.
+package tracing
@@ -0,0 +1,67 @@
+package tracing_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ory/hydra-maester/pkg/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+)
+
+func TestSetup(t *testing.T) {
+
+	t.Run("case=no exporter", func(t *testing.T) {
+		shutdown, err := tracing.Setup(context.Background(), tracing.ExporterNone, 1)
+		require.NoError(t, err)
+		assert.NoError(t, shutdown(context.Background()))
+	})
+
+	t.Run("case=unknown exporter", func(t *testing.T) {
+		_, err := tracing.Setup(context.Background(), "zipkin", 1)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unknown trace exporter "zipkin"`)
+	})
+}
+
+func TestTransport(t *testing.T) {
+
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		traceparent = req.Header.Get("traceparent")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ctx, parent := tracing.Tracer().Start(context.Background(), "Reconcile")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/clients", nil)
+	require.NoError(t, err)
+
+	resp, err := (&http.Client{Transport: tracing.Transport(nil)}).Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	parent.End()
+
+	assert.Empty(t, req.Header.Get("traceparent"), "the request of the caller must not be modified")
+	assert.Contains(t, traceparent, parent.SpanContext().TraceID().String())
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	span := spans[0]
+	assert.Equal(t, "HTTP GET", span.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+	assert.Equal(t, codes.Error, span.Status().Code)
+	assert.Contains(t, span.Attributes(), semconv.HTTPStatusCodeKey.Int(http.StatusServiceUnavailable))
+}
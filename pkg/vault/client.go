@@ -0,0 +1,247 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+)
+
+// maxErrorBody is the maximum length of the body of error responses quoted
+// in errors
+const maxErrorBody = 256
+
+// Authenticator obtains the token the requests to Vault are made with
+type Authenticator interface {
+	Login(ctx context.Context, c *Client) (string, error)
+}
+
+// Token authenticates with a fixed token
+type Token string
+
+// Login returns the token itself
+func (t Token) Login(context.Context, *Client) (string, error) {
+	return string(t), nil
+}
+
+// KubernetesAuth authenticates with Vault's Kubernetes auth method, using
+// the service account token of the controller
+type KubernetesAuth struct {
+	// Role is the Vault role to log in as
+	Role string
+	// Mount is the mount path of the auth method, kubernetes by default
+	Mount string
+	// JWTFile is the file holding the service account token, the one mounted
+	// in pods by default
+	JWTFile string
+}
+
+const defaultJWTFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+// Login logs in to Vault with the service account token
+func (k KubernetesAuth) Login(ctx context.Context, c *Client) (string, error) {
+	jwtFile, mount := k.JWTFile, k.Mount
+	if jwtFile == "" {
+		jwtFile = defaultJWTFile
+	}
+	if mount == "" {
+		mount = "kubernetes"
+	}
+	jwt, err := ioutil.ReadFile(jwtFile)
+	if err != nil {
+		return "", fmt.Errorf("unable to read the service account token: %w", err)
+	}
+
+	var login struct {
+		Auth struct {
+			ClientToken string `json:"client_token"`
+		} `json:"auth"`
+	}
+	body := map[string]string{"role": k.Role, "jwt": strings.TrimSpace(string(jwt))}
+	resp, err := c.send(ctx, http.MethodPost, path.Join("auth", mount, "login"), "", body, &login)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", newStatusError(http.MethodPost, path.Join("auth", mount, "login"), resp)
+	}
+	if login.Auth.ClientToken == "" {
+		return "", fmt.Errorf("vault returned no token for role %s", k.Role)
+	}
+	return login.Auth.ClientToken, nil
+}
+
+// Client reads and writes secrets in a KV version 2 secrets engine
+type Client struct {
+	Address    url.URL
+	HTTPClient *http.Client
+	// Mount is the mount path of the secrets engine, secret by default
+	Mount string
+	Auth  Authenticator
+
+	tokenMu sync.Mutex
+	token   string
+}
+
+// Read returns the data of the latest version of the secret at secretPath.
+// It reports false if there is no such secret, or if it has been deleted.
+func (c *Client) Read(ctx context.Context, secretPath string) (map[string]string, bool, error) {
+	var secret struct {
+		Data struct {
+			Data map[string]string `json:"data"`
+		} `json:"data"`
+	}
+	resp, err := c.do(ctx, http.MethodGet, c.dataPath(secretPath), nil, &secret)
+	if err != nil {
+		return nil, false, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return secret.Data.Data, secret.Data.Data != nil, nil
+	case http.StatusNotFound:
+		return nil, false, nil
+	default:
+		return nil, false, newStatusError(http.MethodGet, c.dataPath(secretPath), resp)
+	}
+}
+
+// Write stores data as a new version of the secret at secretPath
+func (c *Client) Write(ctx context.Context, secretPath string, data map[string]string) error {
+	body := map[string]interface{}{"data": data}
+	resp, err := c.do(ctx, http.MethodPost, c.dataPath(secretPath), body, nil)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return newStatusError(http.MethodPost, c.dataPath(secretPath), resp)
+	}
+	return nil
+}
+
+// Delete deletes all versions of the secret at secretPath, along with its
+// metadata. Deleting a secret which doesn't exist succeeds.
+func (c *Client) Delete(ctx context.Context, secretPath string) error {
+	metadataPath := path.Join(c.mount(), "metadata", secretPath)
+	resp, err := c.do(ctx, http.MethodDelete, metadataPath, nil, nil)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		return newStatusError(http.MethodDelete, metadataPath, resp)
+	}
+	return nil
+}
+
+func (c *Client) mount() string {
+	if c.Mount == "" {
+		return "secret"
+	}
+	return strings.Trim(c.Mount, "/")
+}
+
+func (c *Client) dataPath(secretPath string) string {
+	return path.Join(c.mount(), "data", secretPath)
+}
+
+// do sends an authenticated request, logging in again once if the token has
+// been rejected, e.g. because it expired
+func (c *Client) do(ctx context.Context, method, apiPath string, body, v interface{}) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		token, err := c.currentToken(ctx)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := c.send(ctx, method, apiPath, token, body, v)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusForbidden || attempt > 0 {
+			return resp, nil
+		}
+		c.forgetToken(token)
+	}
+}
+
+func (c *Client) currentToken(ctx context.Context) (string, error) {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+	if c.token != "" {
+		return c.token, nil
+	}
+	if c.Auth == nil {
+		return "", fmt.Errorf("no authentication to vault is configured")
+	}
+	token, err := c.Auth.Login(ctx, c)
+	if err != nil {
+		return "", fmt.Errorf("unable to log in to vault: %w", err)
+	}
+	c.token = token
+	return token, nil
+}
+
+func (c *Client) forgetToken(token string) {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+	if c.token == token {
+		c.token = ""
+	}
+}
+
+func (c *Client) send(ctx context.Context, method, apiPath, token string, body, v interface{}) (*http.Response, error) {
+	var buf io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		buf = bytes.NewReader(raw)
+	}
+
+	u := c.Address
+	u.Path = path.Join("/", u.Path, "v1", apiPath)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), buf)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		raw, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		resp.Body = ioutil.NopCloser(bytes.NewReader(raw))
+		return resp, nil
+	}
+	if v == nil {
+		return resp, nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return resp, fmt.Errorf("%s %s returned a response that is not valid JSON: %w", method, apiPath, err)
+	}
+	return resp, nil
+}
+
+func newStatusError(method, apiPath string, resp *http.Response) error {
+	body, _ := ioutil.ReadAll(resp.Body)
+	return fmt.Errorf("%s %s vault request failed with status %s: %q", method, apiPath, resp.Status, strings.TrimSpace(string(body)))
+}
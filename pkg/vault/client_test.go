@@ -0,0 +1,139 @@
+package vault_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ory/hydra-maester/pkg/vault"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newClient(t *testing.T, h http.HandlerFunc, auth vault.Authenticator) (*vault.Client, func()) {
+	server := httptest.NewServer(h)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	return &vault.Client{Address: *u, HTTPClient: server.Client(), Auth: auth}, server.Close
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("method=write", func(t *testing.T) {
+		c, closeServer := newClient(t, func(w http.ResponseWriter, req *http.Request) {
+			assert.Equal(t, http.MethodPost, req.Method)
+			assert.Equal(t, "/v1/secret/data/clients/default/foo", req.URL.Path)
+			assert.Equal(t, "token", req.Header.Get("X-Vault-Token"))
+			var body struct {
+				Data map[string]string `json:"data"`
+			}
+			require.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+			assert.Equal(t, map[string]string{"client_id": "id"}, body.Data)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"data":{"version":1}}`))
+		}, vault.Token("token"))
+		defer closeServer()
+
+		require.NoError(t, c.Write(ctx, "clients/default/foo", map[string]string{"client_id": "id"}))
+	})
+
+	t.Run("method=read", func(t *testing.T) {
+		c, closeServer := newClient(t, func(w http.ResponseWriter, req *http.Request) {
+			assert.Equal(t, http.MethodGet, req.Method)
+			switch req.URL.Path {
+			case "/v1/kv/data/found":
+				w.Write([]byte(`{"data":{"data":{"client_id":"id","client_secret":"secret"}}}`))
+			case "/v1/kv/data/deleted":
+				w.Write([]byte(`{"data":{"data":null,"metadata":{"deletion_time":"2020-01-01T00:00:00Z"}}}`))
+			case "/v1/kv/data/failing":
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(`{"errors":["internal error"]}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}, vault.Token("token"))
+		defer closeServer()
+		c.Mount = "kv"
+
+		data, found, err := c.Read(ctx, "found")
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, map[string]string{"client_id": "id", "client_secret": "secret"}, data)
+
+		for _, p := range []string{"missing", "deleted"} {
+			_, found, err = c.Read(ctx, p)
+			require.NoError(t, err)
+			assert.False(t, found, p)
+		}
+
+		_, _, err = c.Read(ctx, "failing")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "internal error")
+	})
+
+	t.Run("method=delete", func(t *testing.T) {
+		for status, fails := range map[int]bool{
+			http.StatusNoContent:           false,
+			http.StatusNotFound:            false,
+			http.StatusInternalServerError: true,
+		} {
+			c, closeServer := newClient(t, func(w http.ResponseWriter, req *http.Request) {
+				assert.Equal(t, http.MethodDelete, req.Method)
+				assert.Equal(t, "/v1/secret/metadata/foo", req.URL.Path)
+				w.WriteHeader(status)
+			}, vault.Token("token"))
+			defer closeServer()
+
+			err := c.Delete(ctx, "foo")
+			assert.Equal(t, fails, err != nil, "status %d", status)
+		}
+	})
+
+	t.Run("case=kubernetes auth logs in again once the token is rejected", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "vault")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		jwtFile := filepath.Join(dir, "token")
+		require.NoError(t, ioutil.WriteFile(jwtFile, []byte("jwt\n"), 0600))
+
+		logins := 0
+		c, closeServer := newClient(t, func(w http.ResponseWriter, req *http.Request) {
+			switch req.URL.Path {
+			case "/v1/auth/k8s/login":
+				var body map[string]string
+				require.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+				assert.Equal(t, map[string]string{"role": "hydra-maester", "jwt": "jwt"}, body)
+				logins++
+				json.NewEncoder(w).Encode(map[string]interface{}{"auth": map[string]string{"client_token": "token-" + string(rune('0'+logins))}})
+			case "/v1/secret/data/foo":
+				if req.Header.Get("X-Vault-Token") != "token-2" {
+					w.WriteHeader(http.StatusForbidden)
+					return
+				}
+				w.WriteHeader(http.StatusNoContent)
+			default:
+				t.Errorf("unexpected request to %s", req.URL.Path)
+			}
+		}, vault.KubernetesAuth{Role: "hydra-maester", Mount: "k8s", JWTFile: jwtFile})
+		defer closeServer()
+
+		require.NoError(t, c.Write(ctx, "foo", map[string]string{"client_id": "id"}))
+		assert.Equal(t, 2, logins)
+	})
+
+	t.Run("case=missing service account token", func(t *testing.T) {
+		c, closeServer := newClient(t, func(w http.ResponseWriter, req *http.Request) {
+			t.Errorf("unexpected request to %s", req.URL.Path)
+		}, vault.KubernetesAuth{Role: "hydra-maester", JWTFile: filepath.Join(os.TempDir(), "does-not-exist")})
+		defer closeServer()
+
+		require.Error(t, c.Write(ctx, "foo", nil))
+	})
+}
@@ -0,0 +1,5 @@
+// Package vault is a minimal client of the KV version 2 secrets engine of
+// HashiCorp Vault, which the controller uses to store the credentials of
+// clients. It authenticates with a token or with Vault's Kubernetes auth
+// method.
+package vault